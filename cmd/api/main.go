@@ -6,13 +6,17 @@ import (
 	"os"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/config"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/controller"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/database"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/notifier"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/storage"
 )
 
 func main() {
@@ -41,25 +45,53 @@ func main() {
 	}
 	defer db.Close()
 
+	// Notifier Init
+	err = notifier.InitTemplates(cfg.NotifierTemplateDir)
+	if err != nil {
+		appError.LogError(err, "failed to load notifier templates")
+		os.Exit(1)
+	}
+
+	messageNotifier, err := notifier.NewNotifier(cfg)
+	if err != nil {
+		appError.LogError(err, "failed to initialize notifier")
+		os.Exit(1)
+	}
+
+	// Object Storage Init
+	objectStore, err := storage.NewObjectStore(context.Background(), cfg)
+	if err != nil {
+		appError.LogError(err, "failed to initialize object storage")
+		os.Exit(1)
+	}
+
+	// Session Cache
+	sessionCache := cache.NewSessionCache(cache.DefaultSessionCacheTTL)
+
 	// User Routes
 	userRepository := repository.NewUserRepository()
 	votingAccessRepository := repository.NewVotingAccessRepository()
-	userService := service.NewUserService(userRepository, votingAccessRepository, cfg, db, config.Validate)
+	authRepository := repository.NewAuthRepository()
+	passwordResetRepository := repository.NewPasswordResetRepository()
+	outboxRepository := repository.NewOutboxRepository()
+	auditLogRepository := repository.NewAuditLogRepository()
+	votingTokenRepository := repository.NewVotingTokenRepository()
+	profileChangeRepository := repository.NewProfileChangeRepository()
+	votingPeriodRepository := repository.NewVotingPeriodRepository()
+	userService := service.NewUserService(userRepository, votingAccessRepository, authRepository, passwordResetRepository, outboxRepository, auditLogRepository, votingTokenRepository, profileChangeRepository, votingPeriodRepository, sessionCache, cfg, db, config.Validate)
 	userController := controller.NewUserController(userService)
 
 	// Auth Routes
-	authRepository := repository.NewAuthRepository()
-	authService := service.NewAuthService(authRepository, userService, db, config.Validate)
+	authService := service.NewAuthService(authRepository, userRepository, votingTokenRepository, userService, sessionCache, db, config.Validate)
 	authController := controller.NewAuthController(authService)
 
-	// Upload Routes
-	uploadRepository := repository.NewUploadRepository()
-	uploadService := service.NewUploadService(uploadRepository, cfg, db, config.Validate)
-	uploadController := controller.NewUploadController(uploadService)
+	// Outbox Routes
+	outboxService := service.NewOutboxService(outboxRepository, userService, messageNotifier, db)
+	outboxController := controller.NewOutboxController(outboxService)
 
 	// Download Routes
 	downloadRepository := repository.NewDownloadRepository()
-	downloadService := service.NewDownloadService(downloadRepository, cfg, db, config.Validate)
+	downloadService := service.NewDownloadService(downloadRepository, objectStore, cfg, db, config.Validate)
 	downloadController := controller.NewDownloadController(downloadService)
 
 	// Log Routes
@@ -71,7 +103,12 @@ func main() {
 
 	// Candidate Routes
 	candidateRepository := repository.NewCandidateRepository()
-	candidateService := service.NewCandidateService(candidateRepository, cfg, voteService, db, config.Validate)
+	candidateMediaRepository := repository.NewCandidateMediaRepository()
+	candidateRevisionRepository := repository.NewCandidateRevisionRepository()
+	ballotDrawRepository := repository.NewBallotDrawRepository()
+	photoURLCache := cache.NewPresignedURLCache(service.CandidatePhotoURLCacheTTL)
+	candidateListCache := cache.NewCandidateListCache(cache.DefaultCandidateListCacheTTL)
+	candidateService := service.NewCandidateService(candidateRepository, candidateMediaRepository, votingPeriodRepository, ballotDrawRepository, auditLogRepository, candidateRevisionRepository, objectStore, photoURLCache, candidateListCache, cfg, voteService, db, config.Validate)
 
 	// Inject candidateService to voteService to solve Circular Dependency
 	voteService.SetCandidateService(candidateService)
@@ -79,42 +116,110 @@ func main() {
 	// Candidate Controller
 	candidateController := controller.NewCandidateController(candidateService)
 
+	// Candidate Application Routes
+	candidateApplicationRepository := repository.NewCandidateApplicationRepository()
+	candidateApplicationService := service.NewCandidateApplicationService(candidateApplicationRepository, candidateRepository, userRepository, votingPeriodRepository, ballotDrawRepository, candidateRevisionRepository, objectStore, candidateListCache, db, config.Validate)
+	candidateApplicationController := controller.NewCandidateApplicationController(candidateApplicationService)
+
+	// Ballot Draw Routes
+	ballotDrawService := service.NewBallotDrawService(ballotDrawRepository, votingPeriodRepository, candidateRepository, candidateRevisionRepository, candidateListCache, db, config.Validate)
+	ballotDrawController := controller.NewBallotDrawController(ballotDrawService)
+
+	// Upload Routes
+	uploadRepository := repository.NewUploadRepository()
+	uploadService := service.NewUploadService(uploadRepository, candidateRepository, candidateMediaRepository, objectStore, cfg, db, config.Validate)
+	uploadController := controller.NewUploadController(uploadService)
+
 	router := httprouter.New()
 
 	// User Path
-	router.POST("/api/users", middleware.AdminMiddleware(userController.Create, authService))
-	router.GET("/api/users", middleware.AdminMiddleware(userController.GetAll, authService))
-	// router.PATCH("/api/users/current", middleware.UserMiddleware(userController.UpdateCurrent, authService))
+	router.POST("/api/users", middleware.PermissionMiddleware(userController.Create, authService, domain.PermissionUserWrite))
+	router.GET("/api/users", middleware.PermissionMiddleware(userController.GetAll, authService, domain.PermissionUserRead))
+	router.GET("/api/users/export", middleware.PermissionMiddleware(userController.ExportVoterRoll, authService, domain.PermissionVoterRollExport))
 	router.GET("/api/users/current", middleware.UserMiddleware(userController.GetCurrent, authService))
-	router.PATCH("/api/users/:userId", middleware.AdminMiddleware(userController.UpdateById, authService))
-	router.DELETE("/api/users/:userId", middleware.AdminMiddleware(userController.DeleteById, authService))
-	router.POST("/api/users/bulk", middleware.AdminMiddleware(userController.BulkCreate, authService))
-	router.POST("/api/users/generate-passwords", middleware.AdminMiddleware(userController.GeneratePassword, authService))
+	// PATCH /api/users/current would conflict with PATCH /api/users/:userId, so corrections are requested here
+	router.POST("/api/users/current/profile-changes", middleware.UserMiddleware(userController.UpdateCurrent, authService))
+	router.GET("/api/users/current/profile-changes", middleware.UserMiddleware(userController.GetCurrentProfileChanges, authService))
+	router.GET("/api/users/profile-changes", middleware.PermissionMiddleware(userController.GetProfileChanges, authService, domain.PermissionUserRead))
+	router.POST("/api/users/profile-changes/:requestId/approve", middleware.PermissionMiddleware(userController.ApproveProfileChange, authService, domain.PermissionUserWrite))
+	router.POST("/api/users/profile-changes/:requestId/reject", middleware.PermissionMiddleware(userController.RejectProfileChange, authService, domain.PermissionUserWrite))
+	router.PATCH("/api/users/:userId", middleware.PermissionMiddleware(userController.UpdateById, authService, domain.PermissionUserWrite))
+	router.DELETE("/api/users/:userId", middleware.PermissionMiddleware(userController.DeleteById, authService, domain.PermissionUserWrite))
+	router.PATCH("/api/users/:userId/deactivate", middleware.PermissionMiddleware(userController.Deactivate, authService, domain.PermissionUserWrite))
+	router.PATCH("/api/users/:userId/restore", middleware.PermissionMiddleware(userController.Restore, authService, domain.PermissionUserWrite))
+	router.POST("/api/users/bulk", middleware.PermissionMiddleware(userController.BulkCreate, authService, domain.PermissionUserWrite))
+	router.POST("/api/users/sync", middleware.PermissionMiddleware(userController.Sync, authService, domain.PermissionUserWrite))
+	router.POST("/api/users/generate-passwords", middleware.PermissionMiddleware(userController.GeneratePassword, authService, domain.PermissionUserCredential))
+	router.POST("/api/users/credential-sheets", middleware.PermissionMiddleware(userController.ExportCredentialSheet, authService, domain.PermissionUserCredential))
+	router.POST("/api/users/voting-tokens", middleware.PermissionMiddleware(userController.GenerateVotingTokens, authService, domain.PermissionUserCredential))
+	router.POST("/api/users/regenerate-passwords", middleware.PermissionMiddleware(userController.RegeneratePasswords, authService, domain.PermissionUserCredential))
+	router.POST("/api/users/current/password", middleware.UserMiddleware(userController.ChangeCurrentPassword, authService))
+	router.POST("/api/users/forgot-password", userController.ForgotPassword)
+	router.POST("/api/users/reset-password", userController.ResetPassword)
 
 	// Auth Path
 	router.POST("/api/auth/login", authController.Login)
 	router.POST("/api/auth/logout", middleware.UserMiddleware(authController.Logout, authService))
 
+	// Outbox Path
+	router.GET("/api/messages/failed", middleware.PermissionMiddleware(outboxController.GetFailed, authService, domain.PermissionUserCredential))
+	router.POST("/api/messages/:messageId/resend", middleware.PermissionMiddleware(outboxController.Resend, authService, domain.PermissionUserCredential))
+
 	// Upload Path
-	router.GET("/api/upload/candidates/presigned-url", middleware.AdminMiddleware(uploadController.GetPresignedUrl, authService))
+	router.GET("/api/upload/candidates/presigned-url", middleware.PermissionMiddleware(uploadController.GetPresignedUrl, authService, domain.PermissionCandidateWrite))
+	router.POST("/api/upload/candidates/confirm", middleware.PermissionMiddleware(uploadController.ConfirmCandidatePhoto, authService, domain.PermissionCandidateWrite))
+	router.POST("/api/upload/candidates/documents/confirm", middleware.PermissionMiddleware(uploadController.ConfirmCandidateDocument, authService, domain.PermissionCandidateWrite))
 
 	// Download Path
-	router.GET("/api/download/logs/vote", middleware.AdminMiddleware(downloadController.GetPresignedUrl, authService))
+	router.GET("/api/download/logs/vote", middleware.PermissionMiddleware(downloadController.GetPresignedUrl, authService, domain.PermissionVoteLogDownload))
 
 	// Candidate Path
-	router.POST("/api/candidates", middleware.AdminMiddleware(candidateController.Create, authService))
+	router.POST("/api/candidates", middleware.PermissionMiddleware(candidateController.Create, authService, domain.PermissionCandidateWrite))
 	router.GET("/api/candidates", middleware.UserMiddleware(candidateController.GetCandidates, authService))
-	router.GET("/api/candidates/:candidateId", middleware.AdminMiddleware(candidateController.GetCandidateById, authService))
-	router.PATCH("/api/candidates/:candidateId", middleware.AdminMiddleware(candidateController.UpdateCandidateById, authService))
-	router.DELETE("/api/candidates/:candidateId", middleware.AdminMiddleware(candidateController.DeleteCandidateById, authService))
+	router.GET("/api/candidates/:candidateId", middleware.PermissionMiddleware(candidateController.GetCandidateById, authService, domain.PermissionCandidateRead))
+	router.PATCH("/api/candidates/:candidateId", middleware.PermissionMiddleware(candidateController.UpdateCandidateById, authService, domain.PermissionCandidateWrite))
+	router.DELETE("/api/candidates/:candidateId", middleware.PermissionMiddleware(candidateController.DeleteCandidateById, authService, domain.PermissionCandidateWrite))
+	router.GET("/api/candidates/:candidateId/history", middleware.PermissionMiddleware(candidateController.GetHistory, authService, domain.PermissionCandidateRead))
+	router.GET("/api/candidates/:candidateId/audit-logs", middleware.PermissionMiddleware(candidateController.GetAuditLogs, authService, domain.PermissionCandidateRead))
+	router.GET("/api/candidates/:candidateId/media", middleware.UserMiddleware(candidateController.GetMedia, authService))
+	router.POST("/api/candidates/:candidateId/media", middleware.PermissionMiddleware(candidateController.CreateMedia, authService, domain.PermissionCandidateWrite))
+	router.PUT("/api/candidates/:candidateId/media/order", middleware.PermissionMiddleware(candidateController.ReorderMedia, authService, domain.PermissionCandidateWrite))
+	router.PATCH("/api/candidates/:candidateId/media/:mediaId", middleware.PermissionMiddleware(candidateController.UpdateMedia, authService, domain.PermissionCandidateWrite))
+	router.DELETE("/api/candidates/:candidateId/media/:mediaId", middleware.PermissionMiddleware(candidateController.DeleteMedia, authService, domain.PermissionCandidateWrite))
+
+	// Candidate Application Path
+	router.POST("/api/users/current/candidate-applications", middleware.UserMiddleware(candidateApplicationController.Submit, authService))
+	router.GET("/api/users/current/candidate-applications", middleware.UserMiddleware(candidateApplicationController.GetCurrent, authService))
+	router.GET("/api/candidate-applications", middleware.PermissionMiddleware(candidateApplicationController.GetAll, authService, domain.PermissionCandidateRead))
+	router.GET("/api/candidate-applications/:applicationId", middleware.PermissionMiddleware(candidateApplicationController.GetById, authService, domain.PermissionCandidateRead))
+	router.POST("/api/candidate-applications/:applicationId/comments", middleware.PermissionMiddleware(candidateApplicationController.Comment, authService, domain.PermissionCandidateWrite))
+	router.POST("/api/candidate-applications/:applicationId/verify", middleware.PermissionMiddleware(candidateApplicationController.Verify, authService, domain.PermissionCandidateWrite))
+	router.POST("/api/candidate-applications/:applicationId/reject", middleware.PermissionMiddleware(candidateApplicationController.Reject, authService, domain.PermissionCandidateWrite))
+	router.POST("/api/candidate-applications/:applicationId/approve", middleware.PermissionMiddleware(candidateApplicationController.Approve, authService, domain.PermissionCandidateWrite))
+
+	// Ballot Draw Path
+	router.GET("/api/voting-periods/:period", middleware.UserMiddleware(ballotDrawController.GetVotingPeriod, authService))
+	router.PUT("/api/voting-periods/:period", middleware.PermissionMiddleware(ballotDrawController.SetVotingPeriod, authService, domain.PermissionElectionManage))
+	router.GET("/api/ballot-draws/:period", middleware.UserMiddleware(ballotDrawController.GetDraws, authService))
+	router.POST("/api/ballot-draws/:period/commit", middleware.PermissionMiddleware(ballotDrawController.Commit, authService, domain.PermissionElectionManage))
+	router.POST("/api/ballot-draws/:period/reveal", middleware.PermissionMiddleware(ballotDrawController.Reveal, authService, domain.PermissionElectionManage))
+	router.POST("/api/ballot-draws/:period/cancel", middleware.PermissionMiddleware(ballotDrawController.Cancel, authService, domain.PermissionElectionManage))
+
+	// Local storage serves its own presigned URLs
+	if localStore, ok := objectStore.(*storage.LocalObjectStore); ok {
+		router.Handler(http.MethodGet, storage.LocalRoutePrefix+"/*key", localStore)
+		router.Handler(http.MethodPut, storage.LocalRoutePrefix+"/*key", localStore)
+	}
 
 	// Vote Path
-	router.POST("/api/votes", middleware.UserMiddleware(voteController.Save, authService))
-	router.GET("/api/votes/:candidateId", middleware.AdminMiddleware(voteController.GetTotalVotesByCandidateId, authService))
-	router.GET("/ws/votes", middleware.AdminMiddleware(voteController.VotesLiveResult, authService))
+	router.POST("/api/votes", middleware.PermissionMiddleware(voteController.Save, authService, domain.PermissionVoteCast))
+	router.GET("/api/votes/:candidateId", middleware.PermissionMiddleware(voteController.GetTotalVotesByCandidateId, authService, domain.PermissionVoteResultRead))
+	router.GET("/ws/votes", middleware.PermissionMiddleware(voteController.VotesLiveResult, authService, domain.PermissionVoteResultRead))
 	router.GET("/api/user/vote-status", middleware.UserMiddleware(voteController.CheckIfUserHasVoted, authService))
 
 	go voteController.ListenToDB(context.Background())
+	go outboxService.RunWorker(context.Background())
+	go uploadService.RunReconciler(context.Background())
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,119 @@
+// Command normalize-phone-numbers rewrites every stored phone number to its
+// canonical +62 form and reports numbers that could not be normalized or that
+// collide with another user once normalized.
+//
+//	go run ./cmd/normalize-phone-numbers -dry-run
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/config"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/database"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report what would change")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	// Set TimeZone
+	os.Setenv("TZ", "Asia/Makassar")
+
+	// Logger Init
+	config.InitLogger()
+	defer config.CloseLogger()
+
+	// Load Config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appError.LogError(err, "failed to load config")
+		os.Exit(1)
+	}
+
+	// DB Init
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		appError.LogError(err, "failed to initialize database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	userService := service.NewUserService(
+		repository.NewUserRepository(),
+		repository.NewVotingAccessRepository(),
+		repository.NewAuthRepository(),
+		repository.NewPasswordResetRepository(),
+		repository.NewOutboxRepository(),
+		repository.NewAuditLogRepository(),
+		repository.NewVotingTokenRepository(),
+		repository.NewProfileChangeRepository(),
+		repository.NewVotingPeriodRepository(),
+		cache.NewSessionCache(cache.DefaultSessionCacheTTL),
+		cfg,
+		db,
+		config.Validate,
+	)
+
+	report, err := userService.NormalizePhoneNumbers(context.Background(), *dryRun)
+	if err != nil {
+		appError.LogError(err, "failed to normalize phone numbers")
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			appError.LogError(err, "failed to write report")
+			os.Exit(1)
+		}
+		return
+	}
+
+	printReport(report)
+}
+
+func printReport(report web.PhoneNumberMigrationReport) {
+	if report.DryRun {
+		fmt.Println("Dry run, nothing was saved")
+	}
+
+	fmt.Printf("Users checked:     %d\n", report.TotalUsers)
+	fmt.Printf("Already canonical: %d\n", report.AlreadyCanonical)
+	fmt.Printf("Normalized:        %d\n", report.Normalized)
+	fmt.Printf("Invalid:           %d\n", len(report.Invalid))
+	fmt.Printf("Collisions:        %d\n", len(report.Collisions))
+
+	if len(report.Invalid) > 0 {
+		fmt.Println("\nInvalid numbers (left unchanged):")
+		for _, owner := range report.Invalid {
+			fmt.Printf("  %s\n", formatOwner(owner))
+		}
+	}
+
+	if len(report.Collisions) > 0 {
+		fmt.Println("\nCollisions (left unchanged):")
+		for _, collision := range report.Collisions {
+			owners := make([]string, 0, len(collision.Users))
+			for _, owner := range collision.Users {
+				owners = append(owners, formatOwner(owner))
+			}
+			fmt.Printf("  %s\n    %s\n", collision.PhoneNumber, strings.Join(owners, "\n    "))
+		}
+	}
+}
+
+func formatOwner(owner web.PhoneNumberOwner) string {
+	return fmt.Sprintf("user %d, nim %q, %s: %q", owner.UserId, owner.NIM, owner.FullName, owner.PhoneNumber)
+}
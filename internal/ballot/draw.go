@@ -0,0 +1,72 @@
+package ballot
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A draw is committed before anyone knows the public seed, whose source is
+// named at commit time, and is revealed afterwards by a second user, so
+// neither the committee nor the public can choose the order alone. Every
+// value is a lowercase hex SHA-256 digest so a draw can be checked with
+// sha256sum:
+//
+//	commitment  = sha256(secret_seed)
+//	final_seed  = sha256(secret_seed + public_seed)
+//	ticket      = sha256(final_seed + ":" + candidate_id)
+//
+// Candidates are numbered from 1 in ascending order of their tickets.
+
+// NewSecretSeed generates the random seed a draw is committed to
+func NewSecretSeed() (string, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(seed), nil
+}
+
+// Commitment is published when a draw is committed and proves the secret
+// seed wasn't changed afterwards
+func Commitment(secretSeed string) string {
+	return digest(secretSeed)
+}
+
+// FinalSeed combines the secret and the public seed
+func FinalSeed(secretSeed, publicSeed string) string {
+	return digest(secretSeed + publicSeed)
+}
+
+// Ticket is the value a candidate is ordered by
+func Ticket(finalSeed string, candidateId int) string {
+	return digest(finalSeed + ":" + strconv.Itoa(candidateId))
+}
+
+// Order returns the candidate ids in ballot order, so the candidate at
+// index i gets number i+1
+func Order(finalSeed string, candidateIds []int) []int {
+	tickets := make(map[int]string, len(candidateIds))
+	for _, id := range candidateIds {
+		tickets[id] = Ticket(finalSeed, id)
+	}
+
+	order := slices.Clone(candidateIds)
+	slices.SortFunc(order, func(a, b int) int {
+		if c := strings.Compare(tickets[a], tickets[b]); c != 0 {
+			return c
+		}
+		return a - b
+	})
+
+	return order
+}
+
+func digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
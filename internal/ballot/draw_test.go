@@ -0,0 +1,116 @@
+package ballot
+
+import (
+	"encoding/hex"
+	"slices"
+	"testing"
+)
+
+// Expected digests can be reproduced with sha256sum, e.g.
+// echo -n "abcdef:1" | sha256sum
+const testFinalSeed = "bef57ec7f53a6d40beb640a780a639c83bc29ac8a9816f1fc6c5c6dcd93c4721"
+
+func TestCommitment(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretSeed string
+		want       string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"seed", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Commitment(tt.secretSeed); got != tt.want {
+				t.Errorf("Commitment(%q) = %s, want %s", tt.secretSeed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalSeed(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretSeed string
+		publicSeed string
+		want       string
+	}{
+		{"secret and public seed", "abc", "def", testFinalSeed},
+		{"only the concatenation counts", "abcd", "ef", testFinalSeed},
+		{"empty public seed is the commitment", "abc", "", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FinalSeed(tt.secretSeed, tt.publicSeed); got != tt.want {
+				t.Errorf("FinalSeed(%q, %q) = %s, want %s", tt.secretSeed, tt.publicSeed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTicket(t *testing.T) {
+	tests := []struct {
+		candidateId int
+		want        string
+	}{
+		{1, "c0c77ac7e26c9428a0676fb926ffc34661cd3eb6d3336f37809483ec09d72f1e"},
+		{2, "780cfedca9153b77ecbf70c4d3beffabd25590970eaeed8f800c9d8a6b04d937"},
+		{42, "c122171dcfbe209b689d0ba3d0b11e25159bdb13db5195d812440d8fd2097735"},
+	}
+
+	for _, tt := range tests {
+		if got := Ticket(testFinalSeed, tt.candidateId); got != tt.want {
+			t.Errorf("Ticket(%d) = %s, want %s", tt.candidateId, got, tt.want)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		candidateIds []int
+		want         []int
+	}{
+		{"no candidates", []int{}, []int{}},
+		{"one candidate", []int{7}, []int{7}},
+		{"ascending tickets", []int{1, 2, 3, 4, 5}, []int{5, 2, 3, 1, 4}},
+		{"input order is ignored", []int{4, 1, 5, 3, 2}, []int{5, 2, 3, 1, 4}},
+		{"ids with gaps", []int{10, 42, 3}, []int{3, 10, 42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(tt.candidateIds)
+
+			got := Order(testFinalSeed, tt.candidateIds)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Order(%v) = %v, want %v", tt.candidateIds, got, tt.want)
+			}
+			if !slices.Equal(tt.candidateIds, input) {
+				t.Errorf("Order changed its input to %v", tt.candidateIds)
+			}
+		})
+	}
+}
+
+func TestNewSecretSeed(t *testing.T) {
+	first, err := NewSecretSeed()
+	if err != nil {
+		t.Fatalf("NewSecretSeed() error = %v", err)
+	}
+	second, err := NewSecretSeed()
+	if err != nil {
+		t.Fatalf("NewSecretSeed() error = %v", err)
+	}
+
+	for _, seed := range []string{first, second} {
+		if decoded, err := hex.DecodeString(seed); err != nil || len(decoded) != 32 {
+			t.Errorf("NewSecretSeed() = %q, want 32 hex encoded bytes", seed)
+		}
+	}
+	if first == second {
+		t.Errorf("NewSecretSeed() returned %q twice", first)
+	}
+}
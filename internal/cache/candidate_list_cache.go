@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+// DefaultCandidateListCacheTTL is how long a candidate list is served from
+// memory. Writes through the candidate service clear it right away, so the
+// TTL only bounds staleness across multiple API instances.
+const DefaultCandidateListCacheTTL = 5 * time.Minute
+
+type candidateListCacheEntry struct {
+	value     []web.CandidateResponseWithURL
+	expiresAt time.Time
+}
+
+// CandidateListCache is an in-process TTL cache of candidate lists keyed by
+// period, an empty period being the list of all candidates. Cached lists are
+// shared between callers and must not be modified.
+type CandidateListCache struct {
+	ttl        time.Duration
+	mutex      sync.RWMutex
+	entries    map[string]candidateListCacheEntry
+	generation uint64
+}
+
+func NewCandidateListCache(ttl time.Duration) *CandidateListCache {
+	return &CandidateListCache{
+		ttl:     ttl,
+		entries: make(map[string]candidateListCacheEntry),
+	}
+}
+
+// Get returns the cached list if it exists and has not expired
+func (c *CandidateListCache) Get(period string) ([]web.CandidateResponseWithURL, bool) {
+	c.mutex.RLock()
+	entry, ok := c.entries[period]
+	c.mutex.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// Generation changes on every Clear. Read it before loading a list and pass
+// it to Set, so a list loaded before a write is not cached after the write.
+func (c *CandidateListCache) Generation() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.generation
+}
+
+func (c *CandidateListCache) Set(period string, generation uint64, value []web.CandidateResponseWithURL) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	c.entries[period] = candidateListCacheEntry{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// Clear removes every cached list, e.g. when a candidate is created, updated
+// or deleted
+func (c *CandidateListCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[string]candidateListCacheEntry)
+	c.generation++
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func TestCandidateListCacheGetSet(t *testing.T) {
+	c := NewCandidateListCache(time.Minute)
+
+	if _, ok := c.Get("2025"); ok {
+		t.Fatal("Get of a missing period reported a hit")
+	}
+
+	list := []web.CandidateResponseWithURL{{}, {}}
+	c.Set("2025", c.Generation(), list)
+
+	got, ok := c.Get("2025")
+	if !ok {
+		t.Fatal("Get after Set reported a miss")
+	}
+	if len(got) != len(list) {
+		t.Errorf("Get returned %d candidates, want %d", len(got), len(list))
+	}
+
+	if _, ok := c.Get(""); ok {
+		t.Error("Get of all candidates reported a hit for a list cached by period")
+	}
+}
+
+func TestCandidateListCacheTTL(t *testing.T) {
+	c := NewCandidateListCache(20 * time.Millisecond)
+	c.Set("", c.Generation(), []web.CandidateResponseWithURL{{}})
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Get(""); ok {
+		t.Fatal("Get after the TTL reported a hit")
+	}
+}
+
+func TestCandidateListCacheClear(t *testing.T) {
+	c := NewCandidateListCache(time.Minute)
+	c.Set("", c.Generation(), []web.CandidateResponseWithURL{{}})
+	c.Set("2025", c.Generation(), []web.CandidateResponseWithURL{{}})
+
+	c.Clear()
+
+	if _, ok := c.Get(""); ok {
+		t.Error("Get after Clear reported a hit for all candidates")
+	}
+	if _, ok := c.Get("2025"); ok {
+		t.Error("Get after Clear reported a hit for a period")
+	}
+}
+
+func TestCandidateListCacheGeneration(t *testing.T) {
+	c := NewCandidateListCache(time.Minute)
+
+	// A list loaded before a write must not be cached after the write
+	generation := c.Generation()
+	c.Clear()
+	c.Set("", generation, []web.CandidateResponseWithURL{{}})
+
+	if _, ok := c.Get(""); ok {
+		t.Fatal("Set with a generation from before Clear cached the list")
+	}
+
+	if c.Generation() == generation {
+		t.Fatal("Clear did not change the generation")
+	}
+
+	c.Set("", c.Generation(), []web.CandidateResponseWithURL{{}})
+	if _, ok := c.Get(""); !ok {
+		t.Error("Set with the current generation did not cache the list")
+	}
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type presignedURLCacheEntry struct {
+	url       string
+	expiresAt time.Time
+}
+
+// PresignedURLCache is an in-process TTL cache of presigned URLs keyed by
+// object key. Handing out the same URL lets browsers cache the object. The
+// TTL must be shorter than the URL expiry so a cached URL is still valid
+// for a while after it is returned.
+type PresignedURLCache struct {
+	ttl       time.Duration
+	mutex     sync.RWMutex
+	entries   map[string]presignedURLCacheEntry
+	lastSweep time.Time
+}
+
+func NewPresignedURLCache(ttl time.Duration) *PresignedURLCache {
+	return &PresignedURLCache{
+		ttl:       ttl,
+		entries:   make(map[string]presignedURLCacheEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the cached URL if it exists and has not expired
+func (c *PresignedURLCache) Get(key string) (string, bool) {
+	c.mutex.RLock()
+	entry, ok := c.entries[key]
+	c.mutex.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+
+	return entry.url, true
+}
+
+func (c *PresignedURLCache) Set(key, url string) {
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = presignedURLCacheEntry{
+		url:       url,
+		expiresAt: now.Add(c.ttl),
+	}
+
+	// Sweep expired entries once per TTL so deleted objects don't pile up
+	if now.Sub(c.lastSweep) > c.ttl {
+		for key, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// Delete removes the URL of an object, e.g. when the object is deleted
+func (c *PresignedURLCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresignedURLCacheGetSet(t *testing.T) {
+	c := NewPresignedURLCache(time.Minute)
+
+	if _, ok := c.Get("candidates/photos/a.jpg"); ok {
+		t.Fatal("Get of a missing key reported a hit")
+	}
+
+	c.Set("candidates/photos/a.jpg", "https://example.com/a")
+
+	got, ok := c.Get("candidates/photos/a.jpg")
+	if !ok || got != "https://example.com/a" {
+		t.Errorf("Get = (%q, %v), want (%q, true)", got, ok, "https://example.com/a")
+	}
+}
+
+func TestPresignedURLCacheTTL(t *testing.T) {
+	c := NewPresignedURLCache(20 * time.Millisecond)
+	c.Set("a", "https://example.com/a")
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get after the TTL reported a hit")
+	}
+}
+
+func TestPresignedURLCacheDelete(t *testing.T) {
+	c := NewPresignedURLCache(time.Minute)
+	c.Set("a", "https://example.com/a")
+	c.Set("b", "https://example.com/b")
+
+	c.Delete("a")
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get after Delete reported a hit")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("Delete removed another key")
+	}
+}
+
+func TestPresignedURLCacheSweep(t *testing.T) {
+	c := NewPresignedURLCache(20 * time.Millisecond)
+	c.Set("a", "https://example.com/a")
+
+	time.Sleep(40 * time.Millisecond)
+
+	// Setting another URL sweeps the expired one without it being read
+	c.Set("b", "https://example.com/b")
+
+	if _, ok := c.entries["a"]; ok {
+		t.Error("expired URL was not swept on Set")
+	}
+	if _, ok := c.entries["b"]; !ok {
+		t.Error("sweep removed the URL that was just set")
+	}
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+// DefaultSessionCacheTTL is how long a validated session is trusted before
+// it is read from the database again
+const DefaultSessionCacheTTL = 30 * time.Second
+
+type sessionCacheEntry struct {
+	value     domain.SessionWithUser
+	expiresAt time.Time
+}
+
+// SessionCache is an in-process TTL cache of validated sessions keyed by session id
+type SessionCache struct {
+	ttl       time.Duration
+	mutex     sync.RWMutex
+	entries   map[string]sessionCacheEntry
+	lastSweep time.Time
+}
+
+func NewSessionCache(ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		ttl:       ttl,
+		entries:   make(map[string]sessionCacheEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the cached session if it exists and has not expired
+func (c *SessionCache) Get(sessionId string) (domain.SessionWithUser, bool) {
+	c.mutex.RLock()
+	entry, ok := c.entries[sessionId]
+	c.mutex.RUnlock()
+
+	if !ok {
+		return domain.SessionWithUser{}, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		c.Delete(sessionId)
+		return domain.SessionWithUser{}, false
+	}
+
+	return entry.value, true
+}
+
+// Set stores the session until the cache TTL or the session's own max age
+// is reached, whichever comes first
+func (c *SessionCache) Set(sessionId string, value domain.SessionWithUser) {
+	now := time.Now()
+
+	expiresAt := now.Add(c.ttl)
+	sessionExpiresAt := value.Session.CreatedAt.Add(time.Second * time.Duration(value.Session.MaxAgeSeconds))
+	if sessionExpiresAt.Before(expiresAt) {
+		expiresAt = sessionExpiresAt
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[sessionId] = sessionCacheEntry{
+		value:     value,
+		expiresAt: expiresAt,
+	}
+
+	// Sweep expired entries once per TTL so abandoned sessions don't pile up
+	if now.Sub(c.lastSweep) > c.ttl {
+		for key, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// Delete removes a single session, e.g. on logout
+func (c *SessionCache) Delete(sessionId string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, sessionId)
+}
+
+// DeleteByUserId removes every cached session that belongs to the user,
+// e.g. when the user is deleted or their role changes
+func (c *SessionCache) DeleteByUserId(userId int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, entry := range c.entries {
+		if entry.value.User.Id == userId {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func newSession(sessionId string, userId int, createdAt time.Time, maxAge time.Duration) domain.SessionWithUser {
+	return domain.SessionWithUser{
+		Session: domain.Session{
+			SessionId:     sessionId,
+			UserId:        userId,
+			CreatedAt:     createdAt,
+			MaxAgeSeconds: int(maxAge / time.Second),
+		},
+		User: domain.User{Id: userId},
+	}
+}
+
+func TestSessionCacheGetSet(t *testing.T) {
+	c := NewSessionCache(time.Minute)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get of a missing session reported a hit")
+	}
+
+	session := newSession("a", 1, time.Now(), time.Hour)
+	c.Set("a", session)
+
+	got, ok := c.Get("a")
+	if !ok {
+		t.Fatal("Get after Set reported a miss")
+	}
+	if got.Session.SessionId != "a" || got.User.Id != 1 {
+		t.Errorf("Get = %+v, want %+v", got, session)
+	}
+}
+
+func TestSessionCacheTTL(t *testing.T) {
+	c := NewSessionCache(20 * time.Millisecond)
+	c.Set("a", newSession("a", 1, time.Now(), time.Hour))
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get after the TTL reported a hit")
+	}
+	if _, ok := c.entries["a"]; ok {
+		t.Error("expired session was not removed on Get")
+	}
+}
+
+func TestSessionCacheSessionMaxAge(t *testing.T) {
+	c := NewSessionCache(time.Hour)
+
+	// The session itself expired a second ago, so the cache TTL must not extend it
+	c.Set("a", newSession("a", 1, time.Now().Add(-time.Hour-time.Second), time.Hour))
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get of a session past its max age reported a hit")
+	}
+}
+
+func TestSessionCacheDelete(t *testing.T) {
+	c := NewSessionCache(time.Minute)
+	c.Set("a", newSession("a", 1, time.Now(), time.Hour))
+	c.Set("b", newSession("b", 1, time.Now(), time.Hour))
+	c.Set("c", newSession("c", 2, time.Now(), time.Hour))
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get after Delete reported a hit")
+	}
+
+	c.DeleteByUserId(1)
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get after DeleteByUserId reported a hit for the user's session")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("DeleteByUserId removed the session of another user")
+	}
+}
+
+func TestSessionCacheSweep(t *testing.T) {
+	c := NewSessionCache(20 * time.Millisecond)
+	c.Set("a", newSession("a", 1, time.Now(), time.Hour))
+
+	time.Sleep(40 * time.Millisecond)
+
+	// Setting another session sweeps the expired one without it being read
+	c.Set("b", newSession("b", 2, time.Now(), time.Hour))
+
+	if _, ok := c.entries["a"]; ok {
+		t.Error("expired session was not swept on Set")
+	}
+	if _, ok := c.entries["b"]; !ok {
+		t.Error("sweep removed the session that was just set")
+	}
+}
@@ -16,9 +16,25 @@ type Config struct {
 	S3Bucket          string
 	S3URL             string
 
+	StorageProvider   string
+	StorageLocalDir   string
+	StorageSigningKey string
+	StoragePublicURL  string
+	APIURL            string
+
 	FonnteAPIKey  string
 	FonnteSendURL string
 
+	NotifierProvider    string
+	NotifierLogPath     string
+	NotifierTemplateDir string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
 	FrontendURL string
 }
 
@@ -41,9 +57,25 @@ func LoadConfig() (*Config, error) {
 		S3Bucket:          os.Getenv("S3_BUCKET"),
 		S3URL:             os.Getenv("S3_URL"),
 
+		StorageProvider:   os.Getenv("STORAGE_PROVIDER"),
+		StorageLocalDir:   os.Getenv("STORAGE_LOCAL_DIR"),
+		StorageSigningKey: os.Getenv("STORAGE_SIGNING_KEY"),
+		StoragePublicURL:  os.Getenv("STORAGE_PUBLIC_URL"),
+		APIURL:            os.Getenv("API_URL"),
+
 		FonnteAPIKey:  os.Getenv("FONNTE_API_KEY"),
 		FonnteSendURL: os.Getenv("FONNTE_SEND_URL"),
 
+		NotifierProvider:    os.Getenv("NOTIFIER_PROVIDER"),
+		NotifierLogPath:     os.Getenv("NOTIFIER_LOG_PATH"),
+		NotifierTemplateDir: os.Getenv("NOTIFIER_TEMPLATE_DIR"),
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:     os.Getenv("SMTP_FROM"),
+
 		FrontendURL: os.Getenv("FRONTEND_URL"),
 	}, nil
 }
@@ -1,6 +1,9 @@
 package config
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/phone"
+)
 
 var Validate = validator.New()
 
@@ -16,4 +19,10 @@ func ValidatorInit() {
 
 		return true
 	})
+
+	// Validation for Indonesian mobile numbers in any common format
+	Validate.RegisterValidation("phone_id", func(fl validator.FieldLevel) bool {
+		_, ok := phone.Normalize(fl.Field().String())
+		return ok
+	})
 }
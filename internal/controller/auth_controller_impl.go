@@ -38,9 +38,14 @@ func (controller *AuthControllerImpl) Login(w http.ResponseWriter, r *http.Reque
 
 	var loginResponse web.LoginResponse
 	var sessionData string
+	cookieMaxAge := MaxAge
 
-	// If NIM is empty that means login as admin
-	if loginRequest.NIM == "" {
+	// A voting token replaces NIM and password, otherwise an empty NIM means login as admin
+	if loginRequest.Token != "" {
+		loginResponse, sessionData, cookieMaxAge, err = controller.AuthService.LoginToken(r.Context(), MaxAge, web.LoginTokenRequest{
+			Token: loginRequest.Token,
+		})
+	} else if loginRequest.NIM == "" {
 		loginResponse, sessionData, err = controller.AuthService.LoginAdmin(r.Context(), MaxAge, loginRequest)
 	} else {
 		loginResponse, sessionData, err = controller.AuthService.LoginUser(r.Context(), MaxAge, loginRequest)
@@ -78,7 +83,7 @@ func (controller *AuthControllerImpl) Login(w http.ResponseWriter, r *http.Reque
 	http.SetCookie(w, &http.Cookie{
 		Name:     SessionName,
 		Value:    sessionData,
-		MaxAge:   MaxAge,
+		MaxAge:   cookieMaxAge,
 		HttpOnly: true,
 		SameSite: http.SameSiteNoneMode,
 		Secure:   true,
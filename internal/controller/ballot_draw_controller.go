@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type BallotDrawController interface {
+	GetVotingPeriod(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	SetVotingPeriod(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetDraws(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Commit(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Reveal(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Cancel(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
+)
+
+func NewBallotDrawController(ballotDrawService service.BallotDrawService) BallotDrawController {
+	return &BallotDrawControllerImpl{
+		BallotDrawService: ballotDrawService,
+	}
+}
+
+type BallotDrawControllerImpl struct {
+	BallotDrawService service.BallotDrawService
+}
+
+func (controller *BallotDrawControllerImpl) GetVotingPeriod(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	votingPeriod, err := controller.BallotDrawService.GetVotingPeriod(r.Context(), period)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get voting period")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get voting period",
+		Data:    votingPeriod,
+	})
+}
+
+func (controller *BallotDrawControllerImpl) SetVotingPeriod(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to votingPeriodRequest
+	votingPeriodRequest := web.VotingPeriodRequest{}
+	err := helper.ReadFromRequestBody(r, &votingPeriodRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	votingPeriod, err := controller.BallotDrawService.SetVotingPeriod(r.Context(), currentUser, period, votingPeriodRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to set voting period")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Voting period saved",
+		Data:    votingPeriod,
+	})
+}
+
+func (controller *BallotDrawControllerImpl) GetDraws(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	draws, err := controller.BallotDrawService.GetDraws(r.Context(), period)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get ballot draws")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get ballot draws",
+		Data:    draws,
+	})
+}
+
+func (controller *BallotDrawControllerImpl) Commit(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to commitRequest
+	commitRequest := web.BallotDrawCommitRequest{}
+	err := helper.ReadFromRequestBody(r, &commitRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	draw, err := controller.BallotDrawService.Commit(r.Context(), currentUser, period, commitRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to commit ballot draw")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Ballot draw committed",
+		Data:    draw,
+	})
+}
+
+func (controller *BallotDrawControllerImpl) Reveal(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to revealRequest
+	revealRequest := web.BallotDrawRevealRequest{}
+	err := helper.ReadFromRequestBody(r, &revealRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	draw, err := controller.BallotDrawService.Reveal(r.Context(), currentUser, period, revealRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to reveal ballot draw")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Ballot draw revealed",
+		Data:    draw,
+	})
+}
+
+func (controller *BallotDrawControllerImpl) Cancel(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get period from named parameter
+	period := params.ByName("period")
+
+	// Call service
+	draw, err := controller.BallotDrawService.Cancel(r.Context(), currentUser, period)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to cancel ballot draw")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Ballot draw cancelled",
+		Data:    draw,
+	})
+}
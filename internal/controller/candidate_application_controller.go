@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type CandidateApplicationController interface {
+	Submit(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetAll(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Comment(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Verify(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Reject(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Approve(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+}
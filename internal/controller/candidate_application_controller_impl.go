@@ -0,0 +1,561 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
+)
+
+func NewCandidateApplicationController(candidateApplicationService service.CandidateApplicationService) CandidateApplicationController {
+	return &CandidateApplicationControllerImpl{
+		CandidateApplicationService: candidateApplicationService,
+	}
+}
+
+type CandidateApplicationControllerImpl struct {
+	CandidateApplicationService service.CandidateApplicationService
+}
+
+func (controller *CandidateApplicationControllerImpl) Submit(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to applicationRequest
+	applicationRequest := web.CandidateApplicationCreateRequest{}
+	err := helper.ReadFromRequestBody(r, &applicationRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.Submit(r.Context(), currentUser, applicationRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to submit candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Candidate application submitted",
+		Data:    application,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) GetCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Call service
+	applications, err := controller.CandidateApplicationService.GetCurrent(r.Context(), currentUser.ID)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate applications")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate applications",
+		Data:    applications,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) GetAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get query parameters
+	period := r.URL.Query().Get("period")
+	status := r.URL.Query().Get("status")
+
+	// Call service
+	applications, err := controller.CandidateApplicationService.GetAll(r.Context(), period, status)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate applications")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate applications",
+		Data:    applications,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) GetById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get application id from named parameter
+	applicationId := params.ByName("applicationId")
+
+	// Convert query params to int
+	applicationIdInt, err := strconv.Atoi(applicationId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Application not found",
+				Details: fmt.Sprintf("Candidate application with id '%v' does not exist", applicationId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.GetById(r.Context(), applicationIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate application",
+		Data:    application,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) Comment(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get application id from named parameter
+	applicationId := params.ByName("applicationId")
+
+	// Convert query params to int
+	applicationIdInt, err := strconv.Atoi(applicationId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Application not found",
+				Details: fmt.Sprintf("Candidate application with id '%v' does not exist", applicationId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to applicationRequest
+	applicationRequest := web.CandidateApplicationCommentRequest{}
+	err = helper.ReadFromRequestBody(r, &applicationRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.Comment(r.Context(), currentUser, applicationIdInt, applicationRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to comment on candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Comment added",
+		Data:    application,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) Verify(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get application id from named parameter
+	applicationId := params.ByName("applicationId")
+
+	// Convert query params to int
+	applicationIdInt, err := strconv.Atoi(applicationId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Application not found",
+				Details: fmt.Sprintf("Candidate application with id '%v' does not exist", applicationId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to applicationRequest
+	applicationRequest := web.CandidateApplicationReviewRequest{}
+	err = helper.ReadFromRequestBody(r, &applicationRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.Verify(r.Context(), currentUser, applicationIdInt, applicationRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to verify candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Candidate application verified",
+		Data:    application,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) Reject(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get application id from named parameter
+	applicationId := params.ByName("applicationId")
+
+	// Convert query params to int
+	applicationIdInt, err := strconv.Atoi(applicationId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Application not found",
+				Details: fmt.Sprintf("Candidate application with id '%v' does not exist", applicationId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to applicationRequest
+	applicationRequest := web.CandidateApplicationCommentRequest{}
+	err = helper.ReadFromRequestBody(r, &applicationRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.Reject(r.Context(), currentUser, applicationIdInt, applicationRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to reject candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Candidate application rejected",
+		Data:    application,
+	})
+}
+
+func (controller *CandidateApplicationControllerImpl) Approve(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get application id from named parameter
+	applicationId := params.ByName("applicationId")
+
+	// Convert query params to int
+	applicationIdInt, err := strconv.Atoi(applicationId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Application not found",
+				Details: fmt.Sprintf("Candidate application with id '%v' does not exist", applicationId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to applicationRequest
+	applicationRequest := web.CandidateApplicationApproveRequest{}
+	err = helper.ReadFromRequestBody(r, &applicationRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	application, err := controller.CandidateApplicationService.Approve(r.Context(), currentUser, applicationIdInt, applicationRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to approve candidate application")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Candidate application approved",
+		Data:    application,
+	})
+}
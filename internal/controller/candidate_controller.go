@@ -12,4 +12,11 @@ type CandidateController interface {
 	GetCandidateById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	UpdateCandidateById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	DeleteCandidateById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetAuditLogs(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	CreateMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	UpdateMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	DeleteMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ReorderMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
 )
@@ -24,6 +25,21 @@ type CandidateControllerImpl struct {
 }
 
 func (controller *CandidateControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
 	// Get request body and write it to candidateRequest
 	candidateRequest := web.CandidateCreateRequest{}
 	err := helper.ReadFromRequestBody(r, &candidateRequest)
@@ -33,7 +49,7 @@ func (controller *CandidateControllerImpl) Create(w http.ResponseWriter, r *http
 	}
 
 	// Call service
-	candidateResponse, err := controller.CandidateService.Create(r.Context(), candidateRequest)
+	candidateResponse, err := controller.CandidateService.Create(r.Context(), currentUser, candidateRequest)
 	if err != nil {
 		var customError *appError.AppError
 
@@ -170,6 +186,21 @@ func (controller *CandidateControllerImpl) GetCandidateById(w http.ResponseWrite
 }
 
 func (controller *CandidateControllerImpl) UpdateCandidateById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
 	// Get id from named parameter
 	candidateId := params.ByName("candidateId")
 
@@ -197,7 +228,7 @@ func (controller *CandidateControllerImpl) UpdateCandidateById(w http.ResponseWr
 	}
 
 	// Call service
-	updatedCandidate, err := controller.CandidateService.UpdateCandidateById(r.Context(), candidateIdInt, candidateRequest)
+	updatedCandidate, err := controller.CandidateService.UpdateCandidateById(r.Context(), currentUser, candidateIdInt, candidateRequest)
 	if err != nil {
 		var customError *appError.AppError
 
@@ -290,3 +321,458 @@ func (controller *CandidateControllerImpl) DeleteCandidateById(w http.ResponseWr
 		Data:    nil,
 	})
 }
+
+func (controller *CandidateControllerImpl) GetHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	history, err := controller.CandidateService.GetHistory(r.Context(), candidateIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate history")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate history",
+		Data:    history,
+	})
+}
+
+func (controller *CandidateControllerImpl) GetAuditLogs(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	auditLogs, err := controller.CandidateService.GetAuditLogs(r.Context(), candidateIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate audit logs")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate audit logs",
+		Data:    auditLogs,
+	})
+}
+
+func (controller *CandidateControllerImpl) GetMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	media, err := controller.CandidateService.GetMedia(r.Context(), candidateIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get candidate media")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get candidate media",
+		Data:    media,
+	})
+}
+
+func (controller *CandidateControllerImpl) CreateMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to mediaRequest
+	mediaRequest := web.CandidateMediaCreateRequest{}
+	err = helper.ReadFromRequestBody(r, &mediaRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	media, err := controller.CandidateService.CreateMedia(r.Context(), candidateIdInt, mediaRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to create candidate media")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success create candidate media",
+		Data:    media,
+	})
+}
+
+func (controller *CandidateControllerImpl) UpdateMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	mediaId := params.ByName("mediaId")
+
+	mediaIdInt, err := strconv.Atoi(mediaId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Media not found",
+				Details: fmt.Sprintf("Media with id '%v' does not exist", mediaId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to mediaRequest
+	mediaRequest := web.CandidateMediaUpdateRequest{}
+	err = helper.ReadFromRequestBody(r, &mediaRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	media, err := controller.CandidateService.UpdateMedia(r.Context(), candidateIdInt, mediaIdInt, mediaRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to update candidate media")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success update candidate media",
+		Data:    media,
+	})
+}
+
+func (controller *CandidateControllerImpl) DeleteMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	mediaId := params.ByName("mediaId")
+
+	mediaIdInt, err := strconv.Atoi(mediaId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Media not found",
+				Details: fmt.Sprintf("Media with id '%v' does not exist", mediaId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	err = controller.CandidateService.DeleteMedia(r.Context(), candidateIdInt, mediaIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to delete candidate media")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success delete candidate media",
+		Data:    nil,
+	})
+}
+
+func (controller *CandidateControllerImpl) ReorderMedia(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get id from named parameter
+	candidateId := params.ByName("candidateId")
+
+	// Convert query params to int
+	candidateIdInt, err := strconv.Atoi(candidateId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Candidate not found",
+				Details: fmt.Sprintf("Candidate with id '%v' does not exist", candidateId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to mediaRequest
+	mediaRequest := web.CandidateMediaOrderRequest{}
+	err = helper.ReadFromRequestBody(r, &mediaRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	media, err := controller.CandidateService.ReorderMedia(r.Context(), candidateIdInt, mediaRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to reorder candidate media")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success reorder candidate media",
+		Data:    media,
+	})
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type OutboxController interface {
+	GetFailed(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Resend(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+}
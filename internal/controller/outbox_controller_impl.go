@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
+)
+
+func NewOutboxController(outboxService service.OutboxService) OutboxController {
+	return &OutboxControllerImpl{
+		OutboxService: outboxService,
+	}
+}
+
+type OutboxControllerImpl struct {
+	OutboxService service.OutboxService
+}
+
+func (controller *OutboxControllerImpl) GetFailed(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Call service
+	messages, err := controller.OutboxService.GetFailed(r.Context())
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get failed messages")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success get failed messages",
+		Data:    messages,
+	})
+}
+
+func (controller *OutboxControllerImpl) Resend(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get message id from named parameter
+	messageId := params.ByName("messageId")
+
+	// Convert query params to int
+	messageIdInt, err := strconv.Atoi(messageId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Message not found",
+				Details: fmt.Sprintf("Failed message with id '%v' does not exist", messageId),
+			},
+		})
+		return
+	}
+
+	// Call service
+	message, err := controller.OutboxService.Resend(r.Context(), currentUser, messageIdInt)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to resend message")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Message has been queued for delivery",
+		Data:    message,
+	})
+}
@@ -8,4 +8,6 @@ import (
 
 type UploadController interface {
 	GetPresignedUrl(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ConfirmCandidatePhoto(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ConfirmCandidateDocument(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/middleware"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
 )
@@ -22,11 +23,26 @@ type UploadControllerImpl struct {
 }
 
 func (controller *UploadControllerImpl) GetPresignedUrl(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
 	// Get query paramaters
 	filename := r.URL.Query().Get("filename")
 
 	if filename != "" {
-		data, err := controller.UploadService.CreatePresignedURL(r.Context(), filename)
+		data, err := controller.UploadService.CreatePresignedURL(r.Context(), currentUser, filename)
 		if err != nil {
 			var customError *appError.AppError
 
@@ -72,3 +88,97 @@ func (controller *UploadControllerImpl) GetPresignedUrl(w http.ResponseWriter, r
 		return
 	}
 }
+
+func (controller *UploadControllerImpl) ConfirmCandidatePhoto(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get request body and write it to confirmRequest
+	confirmRequest := web.CandidateUploadConfirmRequest{}
+	err := helper.ReadFromRequestBody(r, &confirmRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	data, err := controller.UploadService.ConfirmCandidatePhoto(r.Context(), confirmRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to confirm candidate photo")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success confirm candidate photo",
+		Data:    data,
+	})
+}
+
+func (controller *UploadControllerImpl) ConfirmCandidateDocument(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get request body and write it to confirmRequest
+	confirmRequest := web.CandidateUploadConfirmRequest{}
+	err := helper.ReadFromRequestBody(r, &confirmRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	data, err := controller.UploadService.ConfirmCandidateDocument(r.Context(), confirmRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to confirm candidate document")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Success confirm candidate document",
+		Data:    data,
+	})
+}
@@ -9,10 +9,24 @@ import (
 type UserController interface {
 	Create(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	UpdateCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetCurrentProfileChanges(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GetProfileChanges(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ApproveProfileChange(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	RejectProfileChange(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	GetCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	GetAll(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ExportVoterRoll(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	UpdateById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	DeleteById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Deactivate(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Restore(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	BulkCreate(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Sync(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 	GeneratePassword(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ExportCredentialSheet(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	GenerateVotingTokens(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	RegeneratePasswords(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ChangeCurrentPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ForgotPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	ResetPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params)
 }
@@ -1,10 +1,8 @@
 package controller
 
 import (
-	"encoding/csv"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -28,6 +26,21 @@ type UserControllerImpl struct {
 }
 
 func (controller *UserControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
 	// Get request body and write it to userRequest
 	userRequest := web.UserCreateRequest{}
 	err := helper.ReadFromRequestBody(r, &userRequest)
@@ -37,7 +50,7 @@ func (controller *UserControllerImpl) Create(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Call service
-	userResponse, err := controller.UserService.Create(r.Context(), userRequest)
+	userResponse, err := controller.UserService.Create(r.Context(), currentUser, userRequest)
 	if err != nil {
 		var customError *appError.AppError
 
@@ -75,8 +88,8 @@ func (controller *UserControllerImpl) Create(w http.ResponseWriter, r *http.Requ
 }
 
 func (controller *UserControllerImpl) UpdateCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get cookie from context
-	cookie, ok := r.Context().Value(middleware.SessionContextKey).(web.SessionResponse)
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
 	if !ok {
 		appError.LogError(nil, "invalid session data")
 
@@ -99,12 +112,12 @@ func (controller *UserControllerImpl) UpdateCurrent(w http.ResponseWriter, r *ht
 	}
 
 	// Call service
-	userResponse, err := controller.UserService.UpdateCurrent(r.Context(), cookie.SessionId, userUpdateRequest)
+	changeRequest, err := controller.UserService.UpdateCurrent(r.Context(), currentUser, userUpdateRequest)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to update user")
+			appError.LogError(err, "failed to request profile change")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -129,16 +142,16 @@ func (controller *UserControllerImpl) UpdateCurrent(w http.ResponseWriter, r *ht
 	}
 
 	// Write and send the response
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "User updated successfully",
-		Data:    userResponse,
+		Message: "Profile change requested and waiting for admin approval",
+		Data:    changeRequest,
 	})
 }
 
-func (controller *UserControllerImpl) GetCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get cookie from context
-	cookie, ok := r.Context().Value(middleware.SessionContextKey).(web.SessionResponse)
+func (controller *UserControllerImpl) GetCurrentProfileChanges(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
 	if !ok {
 		appError.LogError(nil, "invalid session data")
 
@@ -153,12 +166,12 @@ func (controller *UserControllerImpl) GetCurrent(w http.ResponseWriter, r *http.
 	}
 
 	// Call service
-	userResponse, err := controller.UserService.GetCurrent(r.Context(), cookie.SessionId)
+	changeRequests, err := controller.UserService.GetCurrentProfileChanges(r.Context(), currentUser.ID)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to get current user")
+			appError.LogError(err, "failed to get profile change requests")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -182,22 +195,22 @@ func (controller *UserControllerImpl) GetCurrent(w http.ResponseWriter, r *http.
 		}
 	}
 
-	// Write data and send the response
+	// Write and send the response
 	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "Get current user successfully",
-		Data:    userResponse,
+		Message: "Success get profile change requests",
+		Data:    changeRequests,
 	})
 }
 
-func (controller *UserControllerImpl) GetAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Call service
-	users, err := controller.UserService.GetAll(r.Context())
+func (controller *UserControllerImpl) GetProfileChanges(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Call service, pending requests are listed unless ?status= is given
+	changeRequests, err := controller.UserService.GetProfileChanges(r.Context(), r.URL.Query().Get("status"))
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to get all users")
+			appError.LogError(err, "failed to get profile change requests")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -224,45 +237,52 @@ func (controller *UserControllerImpl) GetAll(w http.ResponseWriter, r *http.Requ
 	// Write and send the response
 	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "Success get all users",
-		Data:    users,
+		Message: "Success get profile change requests",
+		Data:    changeRequests,
 	})
 }
 
-func (controller *UserControllerImpl) UpdateById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get user id from named parameter
-	userId := params.ByName("userId")
+func (controller *UserControllerImpl) ApproveProfileChange(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request id from named parameter
+	requestId := params.ByName("requestId")
 
 	// Convert query params to int
-	userIdInt, err := strconv.Atoi(userId)
+	requestIdInt, err := strconv.Atoi(requestId)
 	if err != nil {
 		appError.LogError(err, "failed to convert id to int")
 
 		w.WriteHeader(http.StatusNotFound)
 		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 			"error": {
-				Message: "User not found",
-				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+				Message: "Profile change request not found",
+				Details: fmt.Sprintf("Profile change request with id '%v' does not exist", requestId),
 			},
 		})
 		return
 	}
 
-	// Get request body and write it to userRequest
-	userRequest := web.UserUpdateByIdRequest{}
-	err = helper.ReadFromRequestBody(r, &userRequest)
-	if err != nil {
-		helper.WriteJSONDecodeError(w, err)
-		return
-	}
-
 	// Call service
-	userResponse, err := controller.UserService.UpdateById(r.Context(), userIdInt, userRequest)
+	changeRequest, err := controller.UserService.ApproveProfileChange(r.Context(), currentUser, requestIdInt)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to update user by id")
+			appError.LogError(err, "failed to approve profile change request")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -289,37 +309,60 @@ func (controller *UserControllerImpl) UpdateById(w http.ResponseWriter, r *http.
 	// Write and send the response
 	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "User updated successfully",
-		Data:    userResponse,
+		Message: "Profile change approved and applied",
+		Data:    changeRequest,
 	})
 }
 
-func (controller *UserControllerImpl) DeleteById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get user id from named parameter
-	userId := params.ByName("userId")
+func (controller *UserControllerImpl) RejectProfileChange(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request id from named parameter
+	requestId := params.ByName("requestId")
 
 	// Convert query params to int
-	userIdInt, err := strconv.Atoi(userId)
+	requestIdInt, err := strconv.Atoi(requestId)
 	if err != nil {
 		appError.LogError(err, "failed to convert id to int")
 
 		w.WriteHeader(http.StatusNotFound)
 		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 			"error": {
-				Message: "User not found",
-				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+				Message: "Profile change request not found",
+				Details: fmt.Sprintf("Profile change request with id '%v' does not exist", requestId),
 			},
 		})
 		return
 	}
 
+	// Get request body and write it to rejectRequest
+	rejectRequest := web.ProfileChangeRejectRequest{}
+	err = helper.ReadFromRequestBody(r, &rejectRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
 	// Call service
-	err = controller.UserService.DeleteById(r.Context(), userIdInt)
+	changeRequest, err := controller.UserService.RejectProfileChange(r.Context(), currentUser, requestIdInt, rejectRequest)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to delete user by id")
+			appError.LogError(err, "failed to reject profile change request")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -346,143 +389,159 @@ func (controller *UserControllerImpl) DeleteById(w http.ResponseWriter, r *http.
 	// Write and send the response
 	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "User deleted successfully",
-		Data:    nil,
+		Message: "Profile change rejected",
+		Data:    changeRequest,
 	})
 }
 
-func (controller *UserControllerImpl) BulkCreate(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Return 400 if request content-type isn't multipart/form-data
-	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
-		w.WriteHeader(http.StatusBadRequest)
-		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
-			"error": {
-				Message: "Bad request",
-				Details: "Request content-type must be multipart/form-data",
-			},
-		})
-		return
-	}
-
-	// Parse multipart form (max size 10MB)
-	err := r.ParseMultipartForm(10 << 20)
-	if err != nil {
-		appError.LogError(err, "failed to parse multipart form")
+func (controller *UserControllerImpl) GetCurrent(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	userResponse, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
 
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusUnauthorized)
 		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 			"error": {
-				Message: "Internal Server Error",
-				Details: "Internal Server Error. Please try again later.",
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
 			},
 		})
 		return
 	}
 
-	// Get the file with the field name "file"
-	file, _, err := r.FormFile("file")
-	if err != nil {
-		appError.LogError(err, "file not found")
+	// Write data and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Get current user successfully",
+		Data:    userResponse,
+	})
+}
 
-		w.WriteHeader(http.StatusNotFound)
-		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
-			"error": {
-				Message: "Not found",
-				Details: "File not found in the request",
-			},
-		})
-		return
+func (controller *UserControllerImpl) GetAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get query paramaters
+	query := r.URL.Query()
+	request := web.UserListRequest{
+		Search:       query.Get("search"),
+		Role:         query.Get("role"),
+		StudyProgram: query.Get("study_program"),
+		Status:       query.Get("status"),
+		Sort:         query.Get("sort"),
+		Order:        query.Get("order"),
 	}
-	defer file.Close()
-
-	// Read the csv data
-	var records []web.UserCreateRequest
-	reader := csv.NewReader(file)
 
-	// Set the Reader's FieldsPerRecord negative to skip the header
-	reader.FieldsPerRecord = -1
-
-	firstRow, err := reader.Read()
-	if err != nil {
-		appError.LogError(err, "file is not valid CSV format")
-
-		w.WriteHeader(http.StatusBadRequest)
-		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
-			"error": {
-				Message: "Invalid CSV",
-				Details: "Uploaded file is not a valid CSV (comma-separated values)",
-			},
-		})
-		return
+	var err error
+	request.Page, err = helper.QueryInt(query, "page")
+	if err == nil {
+		request.PageSize, err = helper.QueryInt(query, "page_size")
 	}
-
-	// Validate the first row
-	if len(firstRow) != 4 {
+	if err == nil {
+		request.HasPassword, err = helper.QueryBool(query, "has_password")
+	}
+	if err == nil {
+		request.HasVoted, err = helper.QueryBool(query, "has_voted")
+	}
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 			"error": {
-				Message: "Invalid CSV structure in line 1",
-				Details: "CSV must contain 4 columns: NIM, Full Name, Study Program, Phone Number",
+				Message: "Invalid query parameter",
+				Details: err.Error(),
 			},
 		})
 		return
 	}
 
-	// Append the first row to the records
-	records = append(records, web.UserCreateRequest{
-		NIM:          firstRow[0],
-		FullName:     firstRow[1],
-		StudyProgram: firstRow[2],
-		PhoneNumber:  firstRow[3],
-	})
+	// Call service
+	users, meta, err := controller.UserService.GetAll(r.Context(), request)
+	if err != nil {
+		var customError *appError.AppError
 
-	i := 2
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			appError.LogError(err, "failed to read file")
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to get all users")
 
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 				"error": {
-					Message: "Internal Server Error",
-					Details: "Internal Server Error. Please try again later.",
+					Message: customError.Message,
+					Details: customError.Details,
 				},
 			})
 			return
-		}
+		} else {
+			appError.LogError(err, "unexpected error")
 
-		if len(record) != 4 {
-			w.WriteHeader(http.StatusBadRequest)
+			w.WriteHeader(http.StatusInternalServerError)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
 				"error": {
-					Message: fmt.Sprintf("Invalid CSV structure in line %d", i),
-					Details: "CSV must contain 4 columns: NIM, Full Name, Study Program, Phone Number",
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
 				},
 			})
 			return
 		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebPaginatedResponse{
+		WebSuccessResponse: web.WebSuccessResponse{
+			Message: "Success get all users",
+			Data:    users,
+		},
+		Meta: meta,
+	})
+}
+
+func (controller *UserControllerImpl) UpdateById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get user id from named parameter
+	userId := params.ByName("userId")
+
+	// Convert query params to int
+	userIdInt, err := strconv.Atoi(userId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
 
-		records = append(records, web.UserCreateRequest{
-			NIM:          record[0],
-			FullName:     record[1],
-			StudyProgram: record[2],
-			PhoneNumber:  record[3],
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "User not found",
+				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+			},
 		})
+		return
+	}
 
-		i++
+	// Get request body and write it to userRequest
+	userRequest := web.UserUpdateByIdRequest{}
+	err = helper.ReadFromRequestBody(r, &userRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
 	}
 
 	// Call service
-	userResponses, err := controller.UserService.CreateBulk(r.Context(), records)
+	userResponse, err := controller.UserService.UpdateById(r.Context(), currentUser, userIdInt, userRequest)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to create user")
+			appError.LogError(err, "failed to update user by id")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -507,21 +566,54 @@ func (controller *UserControllerImpl) BulkCreate(w http.ResponseWriter, r *http.
 	}
 
 	// Write and send the response
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "All users created successfully",
-		Data:    userResponses,
+		Message: "User updated successfully",
+		Data:    userResponse,
 	})
 }
 
-func (controller *UserControllerImpl) GeneratePassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+func (controller *UserControllerImpl) DeleteById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get user id from named parameter
+	userId := params.ByName("userId")
+
+	// Convert query params to int
+	userIdInt, err := strconv.Atoi(userId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "User not found",
+				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+			},
+		})
+		return
+	}
+
 	// Call service
-	err := controller.UserService.GeneratePassword(r.Context())
+	err = controller.UserService.DeleteById(r.Context(), currentUser, userIdInt)
 	if err != nil {
 		var customError *appError.AppError
 
 		if errors.As(err, &customError) {
-			appError.LogError(err, "failed to generate password and send to whatsapp")
+			appError.LogError(err, "failed to delete user by id")
 
 			w.WriteHeader(customError.StatusCode)
 			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
@@ -546,9 +638,829 @@ func (controller *UserControllerImpl) GeneratePassword(w http.ResponseWriter, r
 	}
 
 	// Write and send the response
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "User deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (controller *UserControllerImpl) Deactivate(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get user id from named parameter
+	userId := params.ByName("userId")
+
+	// Convert query params to int
+	userIdInt, err := strconv.Atoi(userId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "User not found",
+				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to deactivateRequest
+	deactivateRequest := web.UserDeactivateRequest{}
+	err = helper.ReadFromRequestBody(r, &deactivateRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	userResponse, err := controller.UserService.DeactivateById(r.Context(), currentUser, userIdInt, deactivateRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to deactivate user by id")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "User deactivated successfully",
+		Data:    userResponse,
+	})
+}
+
+func (controller *UserControllerImpl) Restore(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get user id from named parameter
+	userId := params.ByName("userId")
+
+	// Convert query params to int
+	userIdInt, err := strconv.Atoi(userId)
+	if err != nil {
+		appError.LogError(err, "failed to convert id to int")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "User not found",
+				Details: fmt.Sprintf("User with id '%v' does not exist", userId),
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to restoreRequest
+	restoreRequest := web.UserRestoreRequest{}
+	err = helper.ReadFromRequestBody(r, &restoreRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	userResponse, err := controller.UserService.RestoreById(r.Context(), currentUser, userIdInt, restoreRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to restore user by id")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "User restored successfully",
+		Data:    userResponse,
+	})
+}
+
+func (controller *UserControllerImpl) BulkCreate(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Return 400 if request content-type isn't multipart/form-data
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		w.WriteHeader(http.StatusBadRequest)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Bad request",
+				Details: "Request content-type must be multipart/form-data",
+			},
+		})
+		return
+	}
+
+	// Parse multipart form (max size 10MB)
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil {
+		appError.LogError(err, "failed to parse multipart form")
+
+		w.WriteHeader(http.StatusInternalServerError)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Internal Server Error",
+				Details: "Internal Server Error. Please try again later.",
+			},
+		})
+		return
+	}
+
+	// Get the file with the field name "file"
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		appError.LogError(err, "file not found")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Not found",
+				Details: "File not found in the request",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	// .xlsx uploads are read as workbooks, anything else as CSV
+	rowReader, err := helper.NewUserRowReader(file, fileHeader.Filename)
+	if err != nil {
+		appError.LogError(err, "file is not a valid XLSX workbook")
+
+		w.WriteHeader(http.StatusBadRequest)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid XLSX",
+				Details: "Uploaded file is not a valid XLSX workbook",
+			},
+		})
+		return
+	}
+
+	// Validate rows only, without saving, when dry_run=true
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	// Call service
+	importResponse, err := controller.UserService.ImportUsers(r.Context(), rowReader, dryRun)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to import users")
+
+			// Send the row-level report when the rows have problems
+			var details interface{} = customError.Details
+			if len(importResponse.Errors) > 0 {
+				details = importResponse
+			}
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	if dryRun {
+		w.WriteHeader(http.StatusOK)
+		helper.WriteToResponseBody(w, web.WebSuccessResponse{
+			Message: "Import file checked successfully",
+			Data:    importResponse,
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "All users created successfully",
+		Data:    importResponse,
+	})
+}
+
+func (controller *UserControllerImpl) Sync(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Return 400 if request content-type isn't multipart/form-data
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		w.WriteHeader(http.StatusBadRequest)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Bad request",
+				Details: "Request content-type must be multipart/form-data",
+			},
+		})
+		return
+	}
+
+	// Parse multipart form (max size 10MB)
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil {
+		appError.LogError(err, "failed to parse multipart form")
+
+		w.WriteHeader(http.StatusInternalServerError)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Internal Server Error",
+				Details: "Internal Server Error. Please try again later.",
+			},
+		})
+		return
+	}
+
+	// Get the file with the field name "file"
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		appError.LogError(err, "file not found")
+
+		w.WriteHeader(http.StatusNotFound)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Not found",
+				Details: "File not found in the request",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	// .xlsx uploads are read as workbooks, anything else as CSV
+	rowReader, err := helper.NewUserRowReader(file, fileHeader.Filename)
+	if err != nil {
+		appError.LogError(err, "file is not a valid XLSX workbook")
+
+		w.WriteHeader(http.StatusBadRequest)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid XLSX",
+				Details: "Uploaded file is not a valid XLSX workbook",
+			},
+		})
+		return
+	}
+
+	// Report the diff only, without saving, when dry_run=true
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	deactivateMissing := r.URL.Query().Get("deactivate_missing") == "true"
+
+	// Call service
+	syncResponse, err := controller.UserService.SyncUsers(r.Context(), currentUser, rowReader, dryRun, deactivateMissing)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to sync users")
+
+			// Send the diff with its row-level errors when the rows have problems
+			var details interface{} = customError.Details
+			if len(syncResponse.Errors) > 0 {
+				details = syncResponse
+			}
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	message := "Users synced successfully"
+	if dryRun {
+		message = "Roster diff checked successfully"
+	}
+
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: message,
+		Data:    syncResponse,
+	})
+}
+
+func (controller *UserControllerImpl) GeneratePassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Call service
+	err := controller.UserService.GeneratePassword(r.Context())
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to generate password and send to whatsapp")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusCreated)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Password generated successfully and has been queued for delivery",
+		Data:    nil,
+	})
+}
+
+func (controller *UserControllerImpl) RegeneratePasswords(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to regenerateRequest
+	regenerateRequest := web.UserRegeneratePasswordRequest{}
+	err := helper.ReadFromRequestBody(r, &regenerateRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	response, err := controller.UserService.RegeneratePasswords(r.Context(), currentUser, regenerateRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to regenerate passwords")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusCreated)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Password regenerated successfully and has been queued for delivery",
+		Data:    response,
+	})
+}
+
+func (controller *UserControllerImpl) ExportCredentialSheet(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to sheetRequest
+	sheetRequest := web.UserCredentialSheetRequest{}
+	err := helper.ReadFromRequestBody(r, &sheetRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	file, err := controller.UserService.ExportCredentialSheet(r.Context(), currentUser, sheetRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to export credential sheet")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Send the generated file
+	helper.WriteFileToResponse(w, file)
+}
+
+func (controller *UserControllerImpl) ExportVoterRoll(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get query paramaters
+	request := web.UserVoterRollExportRequest{
+		Format: r.URL.Query().Get("format"),
+		Period: r.URL.Query().Get("period"),
+	}
+
+	// Call service
+	file, err := controller.UserService.ExportVoterRoll(r.Context(), request)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to export voter roll")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Stream the voter roll
+	helper.WriteFileStreamToResponse(w, file)
+}
+
+func (controller *UserControllerImpl) GenerateVotingTokens(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get current user resolved by the middleware from context
+	currentUser, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to votingTokenRequest
+	votingTokenRequest := web.UserGenerateVotingTokenRequest{}
+	err := helper.ReadFromRequestBody(r, &votingTokenRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	response, err := controller.UserService.GenerateVotingTokens(r.Context(), currentUser, votingTokenRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to generate voting tokens")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusCreated)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Voting tokens generated successfully and have been queued for delivery",
+		Data:    response,
+	})
+}
+
+func (controller *UserControllerImpl) ChangeCurrentPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get cookie from context
+	cookie, ok := r.Context().Value(middleware.SessionContextKey).(web.SessionResponse)
+	if !ok {
+		appError.LogError(nil, "invalid session data")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+			"error": {
+				Message: "Invalid session data",
+				Details: "Session data may be corrupted or missing",
+			},
+		})
+		return
+	}
+
+	// Get request body and write it to changePasswordRequest
+	changePasswordRequest := web.UserChangePasswordRequest{}
+	err := helper.ReadFromRequestBody(r, &changePasswordRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	err = controller.UserService.ChangeCurrentPassword(r.Context(), cookie.UserId, cookie.SessionId, changePasswordRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to change password")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "Password changed successfully",
+		Data:    nil,
+	})
+}
+
+func (controller *UserControllerImpl) ForgotPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get request body and write it to forgotPasswordRequest
+	forgotPasswordRequest := web.UserForgotPasswordRequest{}
+	err := helper.ReadFromRequestBody(r, &forgotPasswordRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	err = controller.UserService.ForgotPassword(r.Context(), forgotPasswordRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to send password reset OTP")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
+	helper.WriteToResponseBody(w, web.WebSuccessResponse{
+		Message: "If the NIM is registered, an OTP will be sent to its registered contact",
+		Data:    nil,
+	})
+}
+
+func (controller *UserControllerImpl) ResetPassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Get request body and write it to resetPasswordRequest
+	resetPasswordRequest := web.UserResetPasswordRequest{}
+	err := helper.ReadFromRequestBody(r, &resetPasswordRequest)
+	if err != nil {
+		helper.WriteJSONDecodeError(w, err)
+		return
+	}
+
+	// Call service
+	err = controller.UserService.ResetPassword(r.Context(), resetPasswordRequest)
+	if err != nil {
+		var customError *appError.AppError
+
+		if errors.As(err, &customError) {
+			appError.LogError(err, "failed to reset password")
+
+			w.WriteHeader(customError.StatusCode)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: customError.Message,
+					Details: customError.Details,
+				},
+			})
+			return
+		} else {
+			appError.LogError(err, "unexpected error")
+
+			w.WriteHeader(http.StatusInternalServerError)
+			helper.WriteToResponseBody(w, map[string]web.WebFailedResponse{
+				"error": {
+					Message: "Internal Server Error",
+					Details: "Internal Server Error. Please try again later.",
+				},
+			})
+			return
+		}
+	}
+
+	// Write and send the response
+	w.WriteHeader(http.StatusOK)
 	helper.WriteToResponseBody(w, web.WebSuccessResponse{
-		Message: "Password generated successfully and has been sent to whatsapp",
+		Message: "Password reset successfully",
 		Data:    nil,
 	})
 }
@@ -85,8 +85,8 @@ func (controller *VoteControllerImpl) BroadcastToClients(message string) {
 }
 
 func (controller *VoteControllerImpl) Save(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get cookie from context
-	cookie, ok := r.Context().Value(middleware.SessionContextKey).(web.SessionResponse)
+	// Get current user from context
+	user, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
 	if !ok {
 		appError.LogError(nil, "invalid session data")
 
@@ -109,7 +109,7 @@ func (controller *VoteControllerImpl) Save(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Call service
-	voteResponse, err := controller.VoteService.SaveVoteRecord(r.Context(), voteRequest, cookie.UserId)
+	voteResponse, err := controller.VoteService.SaveVoteRecord(r.Context(), voteRequest, user)
 	if err != nil {
 		var customError *appError.AppError
 
@@ -268,8 +268,8 @@ func (controller *VoteControllerImpl) ListenToDB(ctx context.Context) {
 }
 
 func (controller *VoteControllerImpl) CheckIfUserHasVoted(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	// Get cookie from context
-	cookie, ok := r.Context().Value(middleware.SessionContextKey).(web.SessionResponse)
+	// Get current user from context
+	user, ok := r.Context().Value(middleware.UserContextKey).(web.UserResponse)
 	if !ok {
 		appError.LogError(nil, "invalid session data")
 
@@ -284,7 +284,7 @@ func (controller *VoteControllerImpl) CheckIfUserHasVoted(w http.ResponseWriter,
 	}
 
 	// Call service
-	isVoted, err := controller.VoteService.CheckIfUserHasVoted(r.Context(), cookie.SessionId)
+	isVoted, err := controller.VoteService.CheckIfUserHasVoted(r.Context(), user)
 	if err != nil {
 		var customError *appError.AppError
 
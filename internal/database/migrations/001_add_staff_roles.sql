@@ -0,0 +1,17 @@
+-- Adds the finer-grained staff roles used by the permission model.
+-- users.role may be a plain text column or an enum type depending on how the
+-- database was created, so only extend the type when it is an enum.
+DO $$
+DECLARE
+    role_type TEXT;
+BEGIN
+    SELECT udt_name INTO role_type
+    FROM information_schema.columns
+    WHERE table_name = 'users' AND column_name = 'role';
+
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = role_type AND typtype = 'e') THEN
+        EXECUTE format('ALTER TYPE %I ADD VALUE IF NOT EXISTS %L', role_type, 'super_admin');
+        EXECUTE format('ALTER TYPE %I ADD VALUE IF NOT EXISTS %L', role_type, 'candidate_manager');
+        EXECUTE format('ALTER TYPE %I ADD VALUE IF NOT EXISTS %L', role_type, 'committee_observer');
+    END IF;
+END $$;
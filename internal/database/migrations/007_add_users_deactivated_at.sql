@@ -0,0 +1,2 @@
+-- Deactivated users stay in the database for history but are removed from the voter roll.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
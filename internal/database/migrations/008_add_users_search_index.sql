@@ -0,0 +1,2 @@
+-- Speeds up searching users by name on GET /api/users.
+CREATE INDEX IF NOT EXISTS users_full_name_search_idx ON users USING GIN (to_tsvector('simple', full_name));
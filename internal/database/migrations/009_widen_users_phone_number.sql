@@ -0,0 +1,3 @@
+-- Phone numbers are stored in E.164 form (+6281234567890), which needs up to 15 characters.
+-- Run `go run ./cmd/normalize-phone-numbers` after this migration to convert existing rows.
+ALTER TABLE users ALTER COLUMN phone_number TYPE VARCHAR(20);
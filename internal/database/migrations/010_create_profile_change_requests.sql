@@ -0,0 +1,17 @@
+-- Corrections requested by users to their own profile, applied only after an admin approves them.
+CREATE TABLE IF NOT EXISTS profile_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changes JSONB NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    review_note VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one pending request, a new request replaces it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_profile_change_requests_pending ON profile_change_requests (user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_profile_change_requests_status ON profile_change_requests (status, created_at);
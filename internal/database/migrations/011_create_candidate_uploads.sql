@@ -0,0 +1,13 @@
+-- Candidate photo upload keys handed out by the API, so uploads that are never used can be cleaned up.
+CREATE TABLE IF NOT EXISTS candidate_uploads (
+    id SERIAL PRIMARY KEY,
+    upload_key VARCHAR(255) NOT NULL UNIQUE,
+    photo_key VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'issued',
+    issued_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_uploads_status ON candidate_uploads (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_candidate_uploads_photo_key ON candidate_uploads (photo_key) WHERE photo_key <> '';
@@ -0,0 +1,15 @@
+-- Mission items are stored as an ordered JSON array instead of a '*' joined string,
+-- so an item may contain '*' and the order is explicit.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'candidates' AND column_name = 'mission' AND data_type <> 'jsonb'
+    ) THEN
+        ALTER TABLE candidates ALTER COLUMN mission TYPE JSONB
+            USING to_jsonb(COALESCE(string_to_array(NULLIF(mission, ''), '*'), ARRAY[]::TEXT[]));
+    END IF;
+END $$;
+
+ALTER TABLE candidates ALTER COLUMN mission SET DEFAULT '[]';
+ALTER TABLE candidates ALTER COLUMN mission SET NOT NULL;
@@ -0,0 +1,27 @@
+-- Attachments shown on a candidate's profile: files kept in object storage
+-- (poster, document) and external links (video, social, link), in display order.
+CREATE TABLE IF NOT EXISTS candidate_media (
+    id SERIAL PRIMARY KEY,
+    candidate_id INTEGER NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    object_key VARCHAR(255) NOT NULL DEFAULT '',
+    url VARCHAR(2048) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_media_candidate ON candidate_media (candidate_id, position);
+
+-- Uploads are no longer only photos
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'candidate_uploads' AND column_name = 'photo_key'
+    ) THEN
+        ALTER TABLE candidate_uploads RENAME COLUMN photo_key TO object_key;
+        ALTER INDEX idx_candidate_uploads_photo_key RENAME TO idx_candidate_uploads_object_key;
+    END IF;
+END $$;
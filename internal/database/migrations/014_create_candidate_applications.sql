@@ -0,0 +1,32 @@
+-- Candidacy applications submitted by students. An application is verified by
+-- the committee and then approved or rejected; approving it creates the candidate.
+CREATE TABLE IF NOT EXISTS candidate_applications (
+    id SERIAL PRIMARY KEY,
+    period INTEGER NOT NULL,
+    president_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vice_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vision TEXT NOT NULL DEFAULT '',
+    mission JSONB NOT NULL DEFAULT '[]'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted',
+    submitted_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    candidate_id INTEGER REFERENCES candidates(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_applications_status ON candidate_applications (period, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_candidate_applications_president ON candidate_applications (president_id);
+CREATE INDEX IF NOT EXISTS idx_candidate_applications_vice ON candidate_applications (vice_id);
+
+-- Committee comments on an application. Status changes are recorded as a
+-- comment with the new status, plain comments have an empty status.
+CREATE TABLE IF NOT EXISTS candidate_application_comments (
+    id SERIAL PRIMARY KEY,
+    application_id INTEGER NOT NULL REFERENCES candidate_applications(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_application_comments_application ON candidate_application_comments (application_id, created_at);
@@ -0,0 +1,33 @@
+-- Voting schedule of a period. Ballot numbers are locked once voting opens.
+CREATE TABLE IF NOT EXISTS voting_periods (
+    period INTEGER PRIMARY KEY,
+    opens_at TIMESTAMPTZ NOT NULL,
+    closes_at TIMESTAMPTZ NOT NULL,
+    updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Commit-reveal draws of the ballot numbers of a period. The secret seed is
+-- only shown once the draw is revealed, so the commitment proves it was
+-- chosen before the public seed was known. Cancelled draws are kept so
+-- re-draws stay visible.
+CREATE TABLE IF NOT EXISTS ballot_draws (
+    id SERIAL PRIMARY KEY,
+    period INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'committed',
+    candidate_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    secret_seed VARCHAR(64) NOT NULL,
+    commitment VARCHAR(64) NOT NULL,
+    public_seed VARCHAR(255) NOT NULL DEFAULT '',
+    result JSONB NOT NULL DEFAULT '[]'::jsonb,
+    committed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revealed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    cancelled_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revealed_at TIMESTAMP,
+    cancelled_at TIMESTAMP
+);
+
+-- A period has at most one draw that is not cancelled
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ballot_draws_period ON ballot_draws (period) WHERE status <> 'cancelled';
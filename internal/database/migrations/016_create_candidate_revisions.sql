@@ -0,0 +1,24 @@
+-- Every change of a candidate with who made it and the old and new value of
+-- each changed field. Revisions are kept after the candidate is deleted and
+-- can't be changed or removed.
+CREATE TABLE IF NOT EXISTS candidate_revisions (
+    id SERIAL PRIMARY KEY,
+    candidate_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}'::jsonb,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (candidate_id, revision)
+);
+
+CREATE OR REPLACE FUNCTION reject_candidate_revision_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'candidate revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS candidate_revisions_immutable ON candidate_revisions;
+CREATE TRIGGER candidate_revisions_immutable
+    BEFORE UPDATE OR DELETE ON candidate_revisions
+    FOR EACH ROW EXECUTE FUNCTION reject_candidate_revision_change();
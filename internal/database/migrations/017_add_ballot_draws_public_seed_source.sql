@@ -0,0 +1,4 @@
+-- Where the public seed of a draw will come from, e.g. a future beacon round
+-- or the dice protocol of the draw event. It is recorded at commit time so
+-- the seed given on reveal can be checked against it.
+ALTER TABLE ballot_draws ADD COLUMN IF NOT EXISTS public_seed_source VARCHAR(255) NOT NULL DEFAULT '';
@@ -24,12 +24,20 @@ var (
 	ErrForbiddenAccess       = errors.New("forbidden access")
 	ErrLoadDefaultConfig     = errors.New("failed to load s3 default config")
 	ErrCreatePresignedPut    = errors.New("failed to create presigned for put object")
+	ErrCreatePresignedGet    = errors.New("failed to create presigned for get object")
+	ErrObjectStorage         = errors.New("object storage error")
 	ErrLoadEnvironmentConfig = errors.New("unable to load environment config")
 	ErrNumberIsUsed          = errors.New("number is used")
 	ErrPhotoKeyIsUsed        = errors.New("photo key is used")
 	ErrInvalidPeriodRange    = errors.New("period must be a positive number and can't exceed 4 digits")
 	ErrInvalidPeriodSyntax   = errors.New("period can't contain characters")
 	ErrCandidateHasVotes     = errors.New("candidate has votes")
+	ErrInvalidOTP            = errors.New("invalid OTP")
+	ErrOTPExpired            = errors.New("OTP expired")
+	ErrSendMessage           = errors.New("failed to send message")
+	ErrUserDeactivated       = errors.New("user is deactivated")
+	ErrVotingStarted         = errors.New("voting has started")
+	ErrBallotNumbersDrawn    = errors.New("ballot numbers are drawn")
 )
 
 type AppError struct {
@@ -87,6 +95,11 @@ func FormatValidationDetails(ve validator.ValidationErrors) string {
 				"[%s]: '%v' failed validation 'required'; ",
 				fe.Field(), fe.Value(),
 			))
+		case "phone_id":
+			details.WriteString(fmt.Sprintf(
+				"[%s]: '%v' failed validation 'phone_id' (expected an Indonesian mobile number, e.g. 081234567890 or +6281234567890); ",
+				fe.Field(), fe.Value(),
+			))
 		default:
 			details.WriteString(fmt.Sprintf(
 				"[%s]: '%v' failed validation '%s'; ",
@@ -118,6 +131,11 @@ func FormatValidationDetailsWithRow(ve validator.ValidationErrors, row int) stri
 				"[%s]: '%v' failed validation 'required'; ",
 				fe.Field(), fe.Value(),
 			))
+		case "phone_id":
+			details.WriteString(fmt.Sprintf(
+				"[%s]: '%v' failed validation 'phone_id' (expected an Indonesian mobile number, e.g. 081234567890 or +6281234567890); ",
+				fe.Field(), fe.Value(),
+			))
 		default:
 			details.WriteString(fmt.Sprintf(
 				"[%s]: '%v' failed validation '%s'; ",
@@ -127,3 +145,18 @@ func FormatValidationDetailsWithRow(ve validator.ValidationErrors, row int) stri
 	}
 	return strings.TrimSuffix(details.String(), "; ")
 }
+
+// FormatFieldError describes a single failed validation without the field name,
+// e.g. for row-level import reports that carry the column separately
+func FormatFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "min":
+		return fmt.Sprintf("failed validation 'minLength' (minimum length: %s)", fe.Param())
+	case "max":
+		return fmt.Sprintf("failed validation 'maxLength' (maximum length: %s)", fe.Param())
+	case "phone_id":
+		return "failed validation 'phone_id' (expected an Indonesian mobile number, e.g. 081234567890 or +6281234567890)"
+	default:
+		return fmt.Sprintf("failed validation '%s'", fe.Tag())
+	}
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// CredentialSlip is a single student's printable credential
+type CredentialSlip struct {
+	FullName     string
+	NIM          string
+	StudyProgram string
+	Password     string
+}
+
+// Slip layout on an A4 page, in millimetres
+const (
+	slipColumns = 2
+	slipRows    = 5
+	pageMargin  = 10.0
+	slipPadding = 4.0
+	qrSize      = 28.0
+)
+
+// CredentialSheetCSV renders the slips as CSV with a header row
+func CredentialSheetCSV(slips []CredentialSlip, loginURL string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write([]string{"full_name", "nim", "study_program", "password", "login_url"}); err != nil {
+		return nil, err
+	}
+
+	for _, slip := range slips {
+		if err := writer.Write([]string{slip.FullName, slip.NIM, slip.StudyProgram, slip.Password, loginURL}); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CredentialSheetPDF renders the slips as A4 pages of tear-off slips, each with
+// a QR code pointing to loginURL
+func CredentialSheetPDF(slips []CredentialSlip, title, loginURL string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(title, true)
+
+	// Core fonts only support cp1252, translate names so accents still print
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// Every slip shares the same QR code, register it once
+	qrPNG, err := qrcode.Encode(loginURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	pdf.RegisterImageOptionsReader("login-qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrPNG))
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	slipWidth := (pageWidth - 2*pageMargin) / slipColumns
+	slipHeight := (pageHeight - 2*pageMargin) / slipRows
+	perPage := slipColumns * slipRows
+
+	for i, slip := range slips {
+		position := i % perPage
+		if position == 0 {
+			pdf.AddPage()
+			drawCutLines(pdf, slipWidth, slipHeight)
+		}
+
+		x := pageMargin + float64(position%slipColumns)*slipWidth
+		y := pageMargin + float64(position/slipColumns)*slipHeight
+		textWidth := slipWidth - qrSize - 3*slipPadding
+
+		pdf.SetXY(x+slipPadding, y+slipPadding)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(textWidth, 6, tr(title), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(textWidth, 5, tr("Nama: "+slip.FullName), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 5, tr("NIM: "+slip.NIM), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 5, tr("Prodi: "+slip.StudyProgram), "", 2, "L", false, 0, "")
+
+		pdf.Ln(2)
+		pdf.SetX(x + slipPadding)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(textWidth, 4, "Password:", "", 2, "L", false, 0, "")
+		pdf.SetFont("Courier", "B", 12)
+		pdf.CellFormat(textWidth, 7, tr(slip.Password), "1", 2, "C", false, 0, "")
+
+		pdf.Ln(1)
+		pdf.SetX(x + slipPadding)
+		pdf.SetFont("Helvetica", "I", 7)
+		pdf.MultiCell(textWidth, 3.5, tr("Scan QR atau buka "+loginURL+" untuk login."), "", "L", false)
+
+		pdf.ImageOptions("login-qr", x+slipWidth-qrSize-slipPadding, y+slipPadding, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	}
+
+	if len(slips) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawCutLines draws dashed lines between slips so they can be torn off
+func drawCutLines(pdf *fpdf.Fpdf, slipWidth, slipHeight float64) {
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.SetLineWidth(0.2)
+	pdf.SetDashPattern([]float64{2, 2}, 0)
+
+	for column := 0; column <= slipColumns; column++ {
+		x := pageMargin + float64(column)*slipWidth
+		pdf.Line(x, pageMargin, x, pageMargin+slipRows*slipHeight)
+	}
+	for row := 0; row <= slipRows; row++ {
+		y := pageMargin + float64(row)*slipHeight
+		pdf.Line(pageMargin, y, pageMargin+slipColumns*slipWidth, y)
+	}
+
+	pdf.SetDashPattern([]float64{}, 0)
+	pdf.SetDrawColor(0, 0, 0)
+}
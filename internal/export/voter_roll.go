@@ -0,0 +1,137 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// VoterRollRow is a single student on the voter roll
+type VoterRollRow struct {
+	NIM          string
+	FullName     string
+	StudyProgram string
+	HasVoted     bool
+}
+
+var voterRollHeader = []string{"no", "nim", "full_name", "study_program", "has_voted"}
+
+// VoterRollWriter writes the roll one row at a time. Close must be called
+// to finish the file.
+type VoterRollWriter interface {
+	Write(row VoterRollRow) error
+	Close() error
+}
+
+// NewVoterRollCSVWriter writes the roll straight to w as CSV with a header row
+func NewVoterRollCSVWriter(w io.Writer) (VoterRollWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(voterRollHeader); err != nil {
+		return nil, err
+	}
+
+	return &voterRollCSVWriter{writer: writer}, nil
+}
+
+type voterRollCSVWriter struct {
+	writer *csv.Writer
+	count  int
+}
+
+func (writer *voterRollCSVWriter) Write(row VoterRollRow) error {
+	writer.count++
+
+	return writer.writer.Write([]string{
+		strconv.Itoa(writer.count),
+		row.NIM,
+		row.FullName,
+		row.StudyProgram,
+		strconv.FormatBool(row.HasVoted),
+	})
+}
+
+func (writer *voterRollCSVWriter) Close() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
+
+// NewVoterRollXLSXWriter builds a single sheet workbook with the excelize
+// stream writer and writes it to w on Close. An empty signature column is
+// added for sign-off sheets.
+func NewVoterRollXLSXWriter(w io.Writer, period int) (VoterRollWriter, error) {
+	file := excelize.NewFile()
+
+	sheet := fmt.Sprintf("Voter Roll %d", period)
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// NIM and full name columns are wider so printed sheets stay readable
+	_ = stream.SetColWidth(2, 2, 16)
+	_ = stream.SetColWidth(3, 3, 36)
+	_ = stream.SetColWidth(4, 4, 24)
+	_ = stream.SetColWidth(6, 6, 24)
+
+	header := make([]interface{}, 0, len(voterRollHeader)+1)
+	for _, column := range voterRollHeader {
+		header = append(header, column)
+	}
+	header = append(header, "signature")
+
+	if err := stream.SetRow("A1", header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &voterRollXLSXWriter{
+		out:    w,
+		file:   file,
+		stream: stream,
+	}, nil
+}
+
+type voterRollXLSXWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	count  int
+}
+
+func (writer *voterRollXLSXWriter) Write(row VoterRollRow) error {
+	writer.count++
+
+	cell, err := excelize.CoordinatesToCellName(1, writer.count+1)
+	if err != nil {
+		return err
+	}
+
+	// NIM is written as text so leading zeros are kept
+	return writer.stream.SetRow(cell, []interface{}{
+		writer.count,
+		row.NIM,
+		row.FullName,
+		row.StudyProgram,
+		row.HasVoted,
+	})
+}
+
+func (writer *voterRollXLSXWriter) Close() error {
+	defer writer.file.Close()
+
+	if err := writer.stream.Flush(); err != nil {
+		return err
+	}
+
+	_, err := writer.file.WriteTo(writer.out)
+	return err
+}
@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func ToAuditLogResponse(auditLog domain.AuditLog) web.AuditLogResponse {
+	return web.AuditLogResponse{
+		Id:        auditLog.Id,
+		ActorId:   auditLog.ActorId,
+		ActorName: auditLog.ActorName,
+		Action:    auditLog.Action,
+		Details:   auditLog.Details,
+		CreatedAt: auditLog.CreatedAt,
+	}
+}
+
+func ToAuditLogsResponse(auditLogs []domain.AuditLog) []web.AuditLogResponse {
+	responses := []web.AuditLogResponse{}
+	for _, auditLog := range auditLogs {
+		responses = append(responses, ToAuditLogResponse(auditLog))
+	}
+	return responses
+}
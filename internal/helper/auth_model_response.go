@@ -13,6 +13,7 @@ func ToLoginResponse(user domain.User) web.LoginResponse {
 		StudyProgram: user.StudyProgram,
 		Role:         user.Role,
 		PhoneNumber:  user.PhoneNumber,
+		Email:        user.Email,
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    user.UpdatedAt,
 	}
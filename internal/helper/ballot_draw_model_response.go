@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/ballot"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func ToVotingPeriodResponse(votingPeriod domain.VotingPeriod, votingStarted bool) web.VotingPeriodResponse {
+	return web.VotingPeriodResponse{
+		Period:        votingPeriod.Period,
+		OpensAt:       votingPeriod.OpensAt,
+		ClosesAt:      votingPeriod.ClosesAt,
+		VotingStarted: votingStarted,
+		UpdatedBy:     votingPeriod.UpdatedBy,
+		UpdatedAt:     votingPeriod.UpdatedAt,
+	}
+}
+
+// ToBallotDrawResponse includes the values needed to check a revealed draw
+func ToBallotDrawResponse(draw domain.BallotDraw) web.BallotDrawResponse {
+	response := web.BallotDrawResponse{
+		Id:               draw.Id,
+		Period:           draw.Period,
+		Status:           draw.Status,
+		CandidateIds:     draw.CandidateIds,
+		Commitment:       draw.Commitment,
+		PublicSeedSource: draw.PublicSeedSource,
+		Result:           []web.BallotDrawNumberResponse{},
+		CommittedBy:      draw.CommittedBy,
+		RevealedBy:       draw.RevealedBy,
+		CancelledBy:      draw.CancelledBy,
+		CreatedAt:        draw.CreatedAt,
+		RevealedAt:       draw.RevealedAt,
+		CancelledAt:      draw.CancelledAt,
+	}
+
+	if draw.Status == domain.BallotDrawStatusCommitted {
+		return response
+	}
+	response.SecretSeed = draw.SecretSeed
+
+	if draw.Status == domain.BallotDrawStatusRevealed {
+		response.PublicSeed = draw.PublicSeed
+		response.FinalSeed = ballot.FinalSeed(draw.SecretSeed, draw.PublicSeed)
+
+		for _, number := range draw.Result {
+			response.Result = append(response.Result, web.BallotDrawNumberResponse{
+				CandidateId: number.CandidateId,
+				Number:      number.Number,
+				Ticket:      ballot.Ticket(response.FinalSeed, number.CandidateId),
+			})
+		}
+	}
+
+	return response
+}
+
+func ToBallotDrawsResponse(draws []domain.BallotDraw) []web.BallotDrawResponse {
+	responses := []web.BallotDrawResponse{}
+	for _, draw := range draws {
+		responses = append(responses, ToBallotDrawResponse(draw))
+	}
+	return responses
+}
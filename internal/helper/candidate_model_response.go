@@ -1,22 +1,18 @@
 package helper
 
 import (
-	"strings"
-
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 )
 
 func ToCandidateResponse(candidate domain.Candidate) web.CandidateResponse {
-	mission := strings.Split(candidate.Mission, "*")
-
 	return web.CandidateResponse{
 		Id:                    candidate.Id,
 		Number:                candidate.Number,
 		President:             candidate.President,
 		Vice:                  candidate.Vice,
 		Vision:                candidate.Vision,
-		Mission:               mission,
+		Mission:               candidate.Mission,
 		PhotoKey:              candidate.PhotoKey,
 		PresidentStudyProgram: candidate.PresidentStudyProgram,
 		ViceStudyProgram:      candidate.ViceStudyProgram,
@@ -36,16 +32,15 @@ func ToCandidatesResponse(candidates []domain.Candidate) []web.CandidateResponse
 }
 
 func ToCandidateResponseWithURL(candidate domain.CandidateWithURL) web.CandidateResponseWithURL {
-	mission := strings.Split(candidate.Mission, "*")
-
 	return web.CandidateResponseWithURL{
 		Id:                    candidate.Id,
 		Number:                candidate.Number,
 		President:             candidate.President,
 		Vice:                  candidate.Vice,
 		Vision:                candidate.Vision,
-		Mission:               mission,
+		Mission:               candidate.Mission,
 		PhotoURL:              candidate.PhotoURL,
+		ThumbnailURL:          candidate.ThumbnailURL,
 		PresidentStudyProgram: candidate.PresidentStudyProgram,
 		ViceStudyProgram:      candidate.ViceStudyProgram,
 		PresidentNIM:          candidate.PresidentNIM,
@@ -62,3 +57,53 @@ func ToCandidatesResponseWithURL(candidates []domain.CandidateWithURL) []web.Can
 	}
 	return candidateResponsesWithURL
 }
+
+func ToCandidateMediaResponse(media domain.CandidateMedia, url, thumbnailURL string) web.CandidateMediaResponse {
+	return web.CandidateMediaResponse{
+		Id:           media.Id,
+		CandidateId:  media.CandidateId,
+		Type:         media.Type,
+		Title:        media.Title,
+		ObjectKey:    media.ObjectKey,
+		URL:          url,
+		ThumbnailURL: thumbnailURL,
+		Position:     media.Position,
+		CreatedAt:    media.CreatedAt,
+		UpdatedAt:    media.UpdatedAt,
+	}
+}
+
+func ToCandidateApplicationResponse(application domain.CandidateApplication, comments []domain.CandidateApplicationComment) web.CandidateApplicationResponse {
+	commentResponses := []web.CandidateApplicationCommentResponse{}
+	for _, comment := range comments {
+		commentResponses = append(commentResponses, web.CandidateApplicationCommentResponse{
+			Id:         comment.Id,
+			AuthorId:   comment.AuthorId,
+			AuthorName: comment.AuthorName,
+			Status:     comment.Status,
+			Body:       comment.Body,
+			CreatedAt:  comment.CreatedAt,
+		})
+	}
+
+	return web.CandidateApplicationResponse{
+		Id:                    application.Id,
+		Period:                application.Period,
+		PresidentId:           application.PresidentId,
+		PresidentNIM:          application.PresidentNIM,
+		President:             application.President,
+		PresidentStudyProgram: application.PresidentStudyProgram,
+		ViceId:                application.ViceId,
+		ViceNIM:               application.ViceNIM,
+		Vice:                  application.Vice,
+		ViceStudyProgram:      application.ViceStudyProgram,
+		Vision:                application.Vision,
+		Mission:               application.Mission,
+		Status:                application.Status,
+		SubmittedBy:           application.SubmittedBy,
+		CandidateId:           application.CandidateId,
+		Comments:              commentResponses,
+		CreatedAt:             application.CreatedAt,
+		UpdatedAt:             application.UpdatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package helper
+
+import (
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Candidate files are uploaded under CandidateUploadPrefix and, once
+// confirmed, stored as standardized photo variants under CandidatePhotoPrefix
+// or as documents under CandidateDocumentPrefix. All of them are inside
+// CandidatePrefix, which the upload reconciler cleans up.
+const (
+	CandidatePrefix         = "candidates/"
+	CandidateUploadPrefix   = "candidates/uploads/"
+	CandidatePhotoPrefix    = "candidates/photos/"
+	CandidateDocumentPrefix = "candidates/documents/"
+
+	candidatePhotoExt     = ".jpg"
+	candidateThumbnailExt = "_thumb.jpg"
+	candidateDocumentExt  = ".pdf"
+)
+
+// candidateUploadExts are the file extensions accepted for uploads, images
+// for photos and posters and PDF for documents
+var candidateUploadExts = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+	".pdf":  true,
+}
+
+// NewCandidateUploadKey generates a random upload key keeping the extension
+// of fileName. It returns false when the extension is not accepted.
+func NewCandidateUploadKey(fileName string) (string, bool) {
+	ext := strings.ToLower(path.Ext(fileName))
+	if !candidateUploadExts[ext] {
+		return "", false
+	}
+
+	return CandidateUploadPrefix + uuid.NewString() + ext, true
+}
+
+// IsCandidateUploadKey reports whether key was generated by NewCandidateUploadKey
+func IsCandidateUploadKey(key string) bool {
+	id, ok := strings.CutPrefix(key, CandidateUploadPrefix)
+	if !ok {
+		return false
+	}
+
+	ext := path.Ext(id)
+	if !candidateUploadExts[ext] {
+		return false
+	}
+
+	return uuid.Validate(strings.TrimSuffix(id, ext)) == nil
+}
+
+// CandidatePhotoKeys returns the keys of the full size and thumbnail variants
+// of an upload key
+func CandidatePhotoKeys(uploadKey string) (string, string) {
+	id := strings.TrimSuffix(strings.TrimPrefix(uploadKey, CandidateUploadPrefix), path.Ext(uploadKey))
+	return CandidatePhotoPrefix + id + candidatePhotoExt, CandidatePhotoPrefix + id + candidateThumbnailExt
+}
+
+// IsCandidatePhotoKey reports whether key is the full size variant of a
+// confirmed photo
+func IsCandidatePhotoKey(key string) bool {
+	id, ok := strings.CutPrefix(key, CandidatePhotoPrefix)
+	if !ok || strings.HasSuffix(id, candidateThumbnailExt) {
+		return false
+	}
+
+	id, ok = strings.CutSuffix(id, candidatePhotoExt)
+	return ok && uuid.Validate(id) == nil
+}
+
+// CandidateThumbnailKey returns the thumbnail key of a confirmed photo, or
+// an empty string for photos uploaded before thumbnails existed
+func CandidateThumbnailKey(photoKey string) string {
+	if !IsCandidatePhotoKey(photoKey) {
+		return ""
+	}
+
+	return strings.TrimSuffix(photoKey, candidatePhotoExt) + candidateThumbnailExt
+}
+
+// CandidateThumbnailPhotoKey returns the full size photo key of a thumbnail
+// key, or an empty string when key is not a thumbnail
+func CandidateThumbnailPhotoKey(thumbnailKey string) string {
+	photoKey, ok := strings.CutSuffix(thumbnailKey, candidateThumbnailExt)
+	if !ok {
+		return ""
+	}
+
+	photoKey += candidatePhotoExt
+	if !IsCandidatePhotoKey(photoKey) {
+		return ""
+	}
+
+	return photoKey
+}
+
+// CandidateDocumentKey returns the key a confirmed document of an upload key is stored under
+func CandidateDocumentKey(uploadKey string) string {
+	id := strings.TrimSuffix(strings.TrimPrefix(uploadKey, CandidateUploadPrefix), path.Ext(uploadKey))
+	return CandidateDocumentPrefix + id + candidateDocumentExt
+}
+
+// IsCandidateDocumentKey reports whether key is a confirmed document
+func IsCandidateDocumentKey(key string) bool {
+	id, ok := strings.CutPrefix(key, CandidateDocumentPrefix)
+	if !ok {
+		return false
+	}
+
+	id, ok = strings.CutSuffix(id, candidateDocumentExt)
+	return ok && uuid.Validate(id) == nil
+}
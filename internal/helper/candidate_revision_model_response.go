@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func ToCandidateRevisionResponse(revision domain.CandidateRevision) web.CandidateRevisionResponse {
+	changes := make(map[string]web.CandidateFieldChangeResponse, len(revision.Changes))
+	for field, change := range revision.Changes {
+		changes[field] = web.CandidateFieldChangeResponse{
+			From: change.From,
+			To:   change.To,
+		}
+	}
+
+	return web.CandidateRevisionResponse{
+		Revision:  revision.Revision,
+		Changes:   changes,
+		Reason:    revision.Reason,
+		ActorId:   revision.ActorId,
+		ActorName: revision.ActorName,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+func ToCandidateRevisionsResponse(revisions []domain.CandidateRevision) []web.CandidateRevisionResponse {
+	responses := []web.CandidateRevisionResponse{}
+	for _, revision := range revisions {
+		responses = append(responses, ToCandidateRevisionResponse(revision))
+	}
+	return responses
+}
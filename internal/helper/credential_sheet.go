@@ -0,0 +1,44 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/export"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+const credentialSheetTitle = "Kredensial Voting HIMA TI"
+
+// ToCredentialSheetFile renders users carrying plain passwords as a PDF or CSV file
+func ToCredentialSheetFile(users []domain.User, format, loginURL string, generatedAt time.Time) (web.FileResponse, error) {
+	slips := make([]export.CredentialSlip, 0, len(users))
+	for _, user := range users {
+		slips = append(slips, export.CredentialSlip{
+			FullName:     user.FullName,
+			NIM:          user.NIM,
+			StudyProgram: user.StudyProgram,
+			Password:     user.Password,
+		})
+	}
+
+	fileName := fmt.Sprintf("credentials-%s.%s", generatedAt.Format("20060102-150405"), format)
+
+	switch format {
+	case "csv":
+		content, err := export.CredentialSheetCSV(slips, loginURL)
+		if err != nil {
+			return web.FileResponse{}, err
+		}
+		return web.FileResponse{FileName: fileName, ContentType: "text/csv", Content: content}, nil
+	case "pdf":
+		content, err := export.CredentialSheetPDF(slips, credentialSheetTitle, loginURL)
+		if err != nil {
+			return web.FileResponse{}, err
+		}
+		return web.FileResponse{FileName: fileName, ContentType: "application/pdf", Content: content}, nil
+	default:
+		return web.FileResponse{}, fmt.Errorf("unsupported credential sheet format '%s'", format)
+	}
+}
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+// WriteFileToResponse sends the file as a download attachment
+func WriteFileToResponse(w http.ResponseWriter, file web.FileResponse) {
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(file.Content); err != nil {
+		appError.LogError(err, "error when writing file response")
+	}
+}
+
+// WriteFileStreamToResponse streams the file as a download attachment
+func WriteFileStreamToResponse(w http.ResponseWriter, file web.FileStream) {
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only be logged
+	if err := file.WriteTo(w); err != nil {
+		appError.LogError(err, "error when streaming file response")
+	}
+}
@@ -41,3 +41,45 @@ func GeneratePassword(length int) (string, error) {
 
 	return string(bytePassword), nil
 }
+
+var DefaultOTPLength = 6
+
+// GenerateOTP returns a random numeric one-time password
+func GenerateOTP(length int) (string, error) {
+	const digits = "0123456789"
+
+	byteOTP := make([]byte, length)
+	for i := range byteOTP {
+		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(digits))))
+		if err != nil {
+			return "", err
+		}
+		byteOTP[i] = digits[num.Int64()]
+	}
+
+	return string(byteOTP), nil
+}
+
+var DefaultVotingTokenLength = 10
+
+// GenerateVotingToken returns a random code without characters that are easily misread
+func GenerateVotingToken(length int) (string, error) {
+	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	byteToken := make([]byte, length)
+	for i := range byteToken {
+		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		byteToken[i] = charset[num.Int64()]
+	}
+
+	return string(byteToken), nil
+}
+
+// HashVotingToken returns the lookup key stored for a voting token
+func HashVotingToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/notifier"
+)
+
+func ToNotifierRecipient(message domain.OutboxMessage) notifier.Recipient {
+	return notifier.Recipient{
+		Name:        message.RecipientName,
+		PhoneNumber: message.PhoneNumber,
+		Email:       message.Email,
+	}
+}
+
+func ToNotifierMessage(message domain.OutboxMessage) notifier.Message {
+	return notifier.Message{
+		Subject: message.Subject,
+		Body:    message.Body,
+	}
+}
+
+// ToOutboxMessage queues a rendered template for the given user
+func ToOutboxMessage(user domain.User, template string, message notifier.Message) domain.OutboxMessage {
+	userId := user.Id
+
+	return domain.OutboxMessage{
+		UserId:        &userId,
+		Template:      template,
+		RecipientName: user.FullName,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		Subject:       message.Subject,
+		Body:          message.Body,
+	}
+}
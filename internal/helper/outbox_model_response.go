@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func ToOutboxMessageResponse(message domain.OutboxMessage) web.OutboxMessageResponse {
+	return web.OutboxMessageResponse{
+		ID:            message.Id,
+		UserId:        message.UserId,
+		Template:      message.Template,
+		RecipientName: message.RecipientName,
+		PhoneNumber:   message.PhoneNumber,
+		Email:         message.Email,
+		Status:        message.Status,
+		Attempts:      message.Attempts,
+		LastError:     message.LastError,
+		NextAttemptAt: message.NextAttemptAt,
+		SentAt:        message.SentAt,
+		CreatedAt:     message.CreatedAt,
+		UpdatedAt:     message.UpdatedAt,
+	}
+}
+
+func ToOutboxMessageResponses(messages []domain.OutboxMessage) []web.OutboxMessageResponse {
+	outboxMessageResponses := []web.OutboxMessageResponse{}
+	for _, message := range messages {
+		outboxMessageResponses = append(outboxMessageResponses, ToOutboxMessageResponse(message))
+	}
+	return outboxMessageResponses
+}
@@ -0,0 +1,58 @@
+package helper
+
+import "github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+
+// rolePermissions maps every role to the permissions it is granted
+var rolePermissions = map[string][]domain.Permission{
+	domain.RoleSuperAdmin: {
+		domain.PermissionUserRead,
+		domain.PermissionUserWrite,
+		domain.PermissionUserCredential,
+		domain.PermissionRoleManage,
+		domain.PermissionCandidateRead,
+		domain.PermissionCandidateWrite,
+		domain.PermissionVoteResultRead,
+		domain.PermissionVoteLogDownload,
+		domain.PermissionVoterRollExport,
+		domain.PermissionElectionManage,
+	},
+	domain.RoleAdmin: {
+		domain.PermissionUserRead,
+		domain.PermissionUserWrite,
+		domain.PermissionUserCredential,
+		domain.PermissionCandidateRead,
+		domain.PermissionCandidateWrite,
+		domain.PermissionVoteResultRead,
+		domain.PermissionVoteLogDownload,
+		domain.PermissionVoterRollExport,
+		domain.PermissionElectionManage,
+	},
+	domain.RoleCandidateManager: {
+		domain.PermissionCandidateRead,
+		domain.PermissionCandidateWrite,
+	},
+	domain.RoleCommitteeObserver: {
+		domain.PermissionCandidateRead,
+		domain.PermissionVoteResultRead,
+		domain.PermissionVoterRollExport,
+	},
+	domain.RoleStudent: {
+		domain.PermissionVoteCast,
+	},
+}
+
+// HasPermission reports whether the role is granted the permission
+func HasPermission(role string, permission domain.Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRole reports whether the role is a known role
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
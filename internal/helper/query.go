@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// QueryInt parses an optional integer query parameter, returning 0 when absent
+func QueryInt(query url.Values, key string) (int, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("query parameter '%s' must be a number", key)
+	}
+
+	return value, nil
+}
+
+// QueryBool parses an optional boolean query parameter, returning nil when absent
+func QueryBool(query url.Values, key string) (*bool, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter '%s' must be true or false", key)
+	}
+
+	return &value, nil
+}
@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+const utf8BOM = "\ufeff"
+
+// UserImportColumns is the expected column order of a roster file
+var UserImportColumns = []string{"nim", "full_name", "study_program", "phone_number"}
+
+// UserRowReader streams roster rows from an uploaded file.
+// Next returns io.EOF after the last row. A *RowError is reported
+// for that row only and reading can continue.
+type UserRowReader interface {
+	Next() (web.UserImportRow, error)
+}
+
+// RowError is a structural problem with a single row
+type RowError struct {
+	web.ImportRowError
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+}
+
+func NewCSVUserRowReader(r io.Reader) UserRowReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return &CSVUserRowReader{
+		Reader: reader,
+	}
+}
+
+// CSVUserRowReader reads NIM, Full Name, Study Program, Phone Number columns.
+// A first row starting with "nim" is treated as a header and skipped.
+type CSVUserRowReader struct {
+	Reader  *csv.Reader
+	started bool
+}
+
+func (reader *CSVUserRowReader) Next() (web.UserImportRow, error) {
+	record, err := reader.Reader.Read()
+
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return web.UserImportRow{}, &RowError{web.ImportRowError{
+			Row:     parseError.StartLine,
+			Message: fmt.Sprintf("Invalid CSV: %v", parseError.Err),
+		}}
+	}
+	if err != nil {
+		return web.UserImportRow{}, err
+	}
+
+	line, _ := reader.Reader.FieldPos(0)
+
+	if !reader.started {
+		reader.started = true
+		if len(record) > 0 && strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(record[0], utf8BOM)), "nim") {
+			return reader.Next()
+		}
+	}
+
+	if len(record) != len(UserImportColumns) {
+		return web.UserImportRow{}, &RowError{web.ImportRowError{
+			Row:     line,
+			Message: fmt.Sprintf("Row must contain %d columns: NIM, Full Name, Study Program, Phone Number", len(UserImportColumns)),
+		}}
+	}
+
+	return web.UserImportRow{
+		Row:          line,
+		NIM:          strings.TrimSpace(strings.TrimPrefix(record[0], utf8BOM)),
+		FullName:     strings.TrimSpace(record[1]),
+		StudyProgram: strings.TrimSpace(record[2]),
+		PhoneNumber:  strings.TrimSpace(record[3]),
+	}, nil
+}
+
+// ToImportColumn maps a UserImportRow field to its column name
+func ToImportColumn(field string) string {
+	switch field {
+	case "NIM":
+		return "nim"
+	case "FullName":
+		return "full_name"
+	case "StudyProgram":
+		return "study_program"
+	case "PhoneNumber":
+		return "phone_number"
+	default:
+		return strings.ToLower(field)
+	}
+}
+
+// NewUserRowReader picks the reader from the uploaded file name,
+// .xlsx files are read as workbooks and everything else as CSV
+func NewUserRowReader(r io.Reader, fileName string) (UserRowReader, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".xlsx") {
+		return NewXLSXUserRowReader(r)
+	}
+
+	return NewCSVUserRowReader(r), nil
+}
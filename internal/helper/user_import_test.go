@@ -0,0 +1,134 @@
+package helper
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+// readAllRows drains reader, collecting valid rows and row errors separately
+func readAllRows(t *testing.T, reader UserRowReader) ([]web.UserImportRow, []web.ImportRowError) {
+	t.Helper()
+
+	var rows []web.UserImportRow
+	var rowErrors []web.ImportRowError
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, rowErrors
+		}
+
+		var rowError *RowError
+		if errors.As(err, &rowError) {
+			rowErrors = append(rowErrors, rowError.ImportRowError)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVUserRowReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		csv        string
+		wantRows   []web.UserImportRow
+		wantErrors []web.ImportRowError
+	}{
+		{
+			name: "header is skipped",
+			csv:  "nim,full_name,study_program,phone_number\n2201,Budi Santoso,Teknik Informatika,081234567890\n",
+			wantRows: []web.UserImportRow{
+				{Row: 2, NIM: "2201", FullName: "Budi Santoso", StudyProgram: "Teknik Informatika", PhoneNumber: "081234567890"},
+			},
+		},
+		{
+			name: "header with byte order mark and capitals",
+			csv:  "\ufeffNIM,Full Name,Study Program,Phone Number\n2201,Budi,TI,0812\n",
+			wantRows: []web.UserImportRow{
+				{Row: 2, NIM: "2201", FullName: "Budi", StudyProgram: "TI", PhoneNumber: "0812"},
+			},
+		},
+		{
+			name: "no header",
+			csv:  "\ufeff2201,Budi,TI,0812\n2202,Sari,TI,0813\n",
+			wantRows: []web.UserImportRow{
+				{Row: 1, NIM: "2201", FullName: "Budi", StudyProgram: "TI", PhoneNumber: "0812"},
+				{Row: 2, NIM: "2202", FullName: "Sari", StudyProgram: "TI", PhoneNumber: "0813"},
+			},
+		},
+		{
+			name: "values are trimmed",
+			csv:  " 2201 , Budi Santoso ,TI , 0812 \n",
+			wantRows: []web.UserImportRow{
+				{Row: 1, NIM: "2201", FullName: "Budi Santoso", StudyProgram: "TI", PhoneNumber: "0812"},
+			},
+		},
+		{
+			name: "wrong column count is reported and reading continues",
+			csv:  "nim,full_name,study_program,phone_number\n2201,Budi,TI\n2202,Sari,TI,0813,extra\n2203,Andi,TI,0814\n",
+			wantRows: []web.UserImportRow{
+				{Row: 4, NIM: "2203", FullName: "Andi", StudyProgram: "TI", PhoneNumber: "0814"},
+			},
+			wantErrors: []web.ImportRowError{
+				{Row: 2, Message: "Row must contain 4 columns: NIM, Full Name, Study Program, Phone Number"},
+				{Row: 3, Message: "Row must contain 4 columns: NIM, Full Name, Study Program, Phone Number"},
+			},
+		},
+		{
+			name: "quoted value spanning lines keeps the starting line",
+			csv:  "2201,\"Budi\nSantoso\",TI,0812\n2202,Sari,TI,0813\n",
+			wantRows: []web.UserImportRow{
+				{Row: 1, NIM: "2201", FullName: "Budi\nSantoso", StudyProgram: "TI", PhoneNumber: "0812"},
+				{Row: 3, NIM: "2202", FullName: "Sari", StudyProgram: "TI", PhoneNumber: "0813"},
+			},
+		},
+		{
+			name: "invalid quoting is a row error",
+			csv:  "2201,Bu\"di,TI,0812\n",
+			wantErrors: []web.ImportRowError{
+				{Row: 1, Message: "Invalid CSV: bare \" in non-quoted-field"},
+			},
+		},
+		{
+			name: "empty file",
+			csv:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors := readAllRows(t, NewCSVUserRowReader(strings.NewReader(tt.csv)))
+
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("rows = %+v, want %+v", rows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(rowErrors, tt.wantErrors) {
+				t.Errorf("errors = %+v, want %+v", rowErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestToImportColumn(t *testing.T) {
+	tests := map[string]string{
+		"NIM":          "nim",
+		"FullName":     "full_name",
+		"StudyProgram": "study_program",
+		"PhoneNumber":  "phone_number",
+		"Other":        "other",
+	}
+
+	for field, want := range tests {
+		if got := ToImportColumn(field); got != want {
+			t.Errorf("ToImportColumn(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
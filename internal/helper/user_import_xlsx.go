@@ -0,0 +1,156 @@
+package helper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/xuri/excelize/v2"
+)
+
+// requiredImportColumns must be present in the header of every XLSX sheet
+var requiredImportColumns = []string{"nim", "full_name", "phone_number"}
+
+func NewXLSXUserRowReader(r io.Reader) (UserRowReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XLSXUserRowReader{
+		File:   file,
+		sheets: file.GetSheetList(),
+	}, nil
+}
+
+// XLSXUserRowReader reads every sheet of a workbook in order. The first
+// non-empty row of a sheet is its header and columns are mapped by name,
+// so their order does not matter and extra columns are ignored.
+type XLSXUserRowReader struct {
+	File    *excelize.File
+	sheets  []string
+	sheet   int
+	rows    *excelize.Rows
+	row     int
+	columns map[string]int
+}
+
+func (reader *XLSXUserRowReader) Next() (web.UserImportRow, error) {
+	for {
+		if reader.rows == nil {
+			if reader.sheet >= len(reader.sheets) {
+				reader.File.Close()
+				return web.UserImportRow{}, io.EOF
+			}
+
+			rows, err := reader.File.Rows(reader.sheets[reader.sheet])
+			if err != nil {
+				reader.File.Close()
+				return web.UserImportRow{}, err
+			}
+
+			reader.rows = rows
+			reader.row = 0
+			reader.columns = nil
+		}
+
+		sheet := reader.sheets[reader.sheet]
+
+		if !reader.rows.Next() {
+			err := reader.rows.Error()
+			reader.nextSheet()
+			if err != nil {
+				reader.File.Close()
+				return web.UserImportRow{}, err
+			}
+			continue
+		}
+		reader.row++
+
+		// Raw values keep NIMs and phone numbers typed as numbers out of scientific notation
+		cells, err := reader.rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return web.UserImportRow{}, &RowError{web.ImportRowError{
+				Sheet:   sheet,
+				Row:     reader.row,
+				Message: fmt.Sprintf("Invalid row: %v", err),
+			}}
+		}
+
+		if isBlankRow(cells) {
+			continue
+		}
+
+		if reader.columns == nil {
+			columns, missing := mapImportColumns(cells)
+			if len(missing) > 0 {
+				// The rest of the sheet cannot be mapped
+				row := reader.row
+				reader.nextSheet()
+				return web.UserImportRow{}, &RowError{web.ImportRowError{
+					Sheet:   sheet,
+					Row:     row,
+					Message: fmt.Sprintf("Header is missing columns: %s", strings.Join(missing, ", ")),
+				}}
+			}
+
+			reader.columns = columns
+			continue
+		}
+
+		return web.UserImportRow{
+			Sheet:        sheet,
+			Row:          reader.row,
+			NIM:          reader.cell(cells, "nim"),
+			FullName:     reader.cell(cells, "full_name"),
+			StudyProgram: reader.cell(cells, "study_program"),
+			PhoneNumber:  reader.cell(cells, "phone_number"),
+		}, nil
+	}
+}
+
+func (reader *XLSXUserRowReader) nextSheet() {
+	reader.rows.Close()
+	reader.rows = nil
+	reader.sheet++
+}
+
+func (reader *XLSXUserRowReader) cell(cells []string, column string) string {
+	i, ok := reader.columns[column]
+	if !ok || i >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[i])
+}
+
+// mapImportColumns maps header names such as "NIM" or "Full Name" to their index
+func mapImportColumns(header []string) (map[string]int, []string) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+
+	var missing []string
+	for _, column := range requiredImportColumns {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+
+	return columns, missing
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package helper
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/xuri/excelize/v2"
+)
+
+// newWorkbook builds an XLSX file with a sheet for every entry of sheets, in order
+func newWorkbook(t *testing.T, names []string, sheets map[string][][]any) *bytes.Buffer {
+	t.Helper()
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	for i, name := range names {
+		if i == 0 {
+			if err := file.SetSheetName("Sheet1", name); err != nil {
+				t.Fatalf("failed to rename sheet: %v", err)
+			}
+		} else if _, err := file.NewSheet(name); err != nil {
+			t.Fatalf("failed to add sheet: %v", err)
+		}
+
+		for r, row := range sheets[name] {
+			cell, err := excelize.CoordinatesToCellName(1, r+1)
+			if err != nil {
+				t.Fatalf("invalid cell: %v", err)
+			}
+			if err := file.SetSheetRow(name, cell, &row); err != nil {
+				t.Fatalf("failed to write row: %v", err)
+			}
+		}
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("failed to write workbook: %v", err)
+	}
+	return buf
+}
+
+func TestXLSXUserRowReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheets     []string
+		rows       map[string][][]any
+		wantRows   []web.UserImportRow
+		wantErrors []web.ImportRowError
+	}{
+		{
+			name:   "columns are mapped by header name",
+			sheets: []string{"TI"},
+			rows: map[string][][]any{
+				"TI": {
+					{"Phone Number", "Notes", "Full Name", "NIM", "study-program"},
+					{"0812", "ignored", "Budi Santoso", "2201", "Teknik Informatika"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 2, NIM: "2201", FullName: "Budi Santoso", StudyProgram: "Teknik Informatika", PhoneNumber: "0812"},
+			},
+		},
+		{
+			name:   "study program is optional",
+			sheets: []string{"TI"},
+			rows: map[string][][]any{
+				"TI": {
+					{"nim", "full_name", "phone_number"},
+					{"2201", " Budi ", "0812"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 2, NIM: "2201", FullName: "Budi", PhoneNumber: "0812"},
+			},
+		},
+		{
+			name:   "numbers are read without scientific notation",
+			sheets: []string{"TI"},
+			rows: map[string][][]any{
+				"TI": {
+					{"nim", "full_name", "phone_number"},
+					{22011234567890, "Budi", 81234567890},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 2, NIM: "22011234567890", FullName: "Budi", PhoneNumber: "81234567890"},
+			},
+		},
+		{
+			name:   "blank rows before the header and between rows are skipped",
+			sheets: []string{"TI"},
+			rows: map[string][][]any{
+				"TI": {
+					{},
+					{"nim", "full_name", "phone_number"},
+					{"2201", "Budi", "0812"},
+					{" ", "", ""},
+					{"2202", "Sari", "0813"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 3, NIM: "2201", FullName: "Budi", PhoneNumber: "0812"},
+				{Sheet: "TI", Row: 5, NIM: "2202", FullName: "Sari", PhoneNumber: "0813"},
+			},
+		},
+		{
+			name:   "missing header columns skip only that sheet",
+			sheets: []string{"Broken", "SI"},
+			rows: map[string][][]any{
+				"Broken": {
+					{"nim", "name"},
+					{"2201", "Budi"},
+				},
+				"SI": {
+					{"nim", "full_name", "phone_number"},
+					{"2301", "Sari", "0813"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "SI", Row: 2, NIM: "2301", FullName: "Sari", PhoneNumber: "0813"},
+			},
+			wantErrors: []web.ImportRowError{
+				{Sheet: "Broken", Row: 1, Message: "Header is missing columns: full_name, phone_number"},
+			},
+		},
+		{
+			name:   "every sheet is read in order",
+			sheets: []string{"TI", "Empty", "SI"},
+			rows: map[string][][]any{
+				"TI": {
+					{"nim", "full_name", "phone_number"},
+					{"2201", "Budi", "0812"},
+				},
+				"SI": {
+					{"full_name", "nim", "phone_number"},
+					{"Sari", "2301", "0813"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 2, NIM: "2201", FullName: "Budi", PhoneNumber: "0812"},
+				{Sheet: "SI", Row: 2, NIM: "2301", FullName: "Sari", PhoneNumber: "0813"},
+			},
+		},
+		{
+			name:   "short rows leave trailing columns empty",
+			sheets: []string{"TI"},
+			rows: map[string][][]any{
+				"TI": {
+					{"nim", "full_name", "phone_number"},
+					{"2201"},
+				},
+			},
+			wantRows: []web.UserImportRow{
+				{Sheet: "TI", Row: 2, NIM: "2201"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewXLSXUserRowReader(newWorkbook(t, tt.sheets, tt.rows))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rows, rowErrors := readAllRows(t, reader)
+
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("rows = %+v, want %+v", rows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(rowErrors, tt.wantErrors) {
+				t.Errorf("errors = %+v, want %+v", rowErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestMapImportColumns(t *testing.T) {
+	columns, missing := mapImportColumns([]string{" NIM ", "Full Name", "nim", "Phone-Number"})
+
+	want := map[string]int{"nim": 0, "full_name": 1, "phone_number": 3}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+	if len(missing) != 0 {
+		t.Errorf("missing = %v, want none", missing)
+	}
+
+	_, missing = mapImportColumns([]string{"name", "phone"})
+	if want := []string{"nim", "full_name", "phone_number"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestNewUserRowReader(t *testing.T) {
+	reader, err := NewUserRowReader(strings.NewReader("2201,Budi,TI,0812\n"), "roster.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := reader.(*CSVUserRowReader); !ok {
+		t.Errorf("reader for roster.csv = %T, want *CSVUserRowReader", reader)
+	}
+
+	reader, err = NewUserRowReader(newWorkbook(t, []string{"TI"}, nil), "ROSTER.XLSX")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := reader.(*XLSXUserRowReader); !ok {
+		t.Errorf("reader for ROSTER.XLSX = %T, want *XLSXUserRowReader", reader)
+	}
+
+	_, err = NewUserRowReader(strings.NewReader("not a workbook"), "roster.xlsx")
+	if err == nil {
+		t.Error("expected an error opening an invalid workbook")
+	}
+}
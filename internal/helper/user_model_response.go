@@ -7,14 +7,16 @@ import (
 
 func ToUserResponse(user domain.User) web.UserResponse {
 	return web.UserResponse{
-		ID:           user.Id,
-		NIM:          user.NIM,
-		FullName:     user.FullName,
-		StudyProgram: user.StudyProgram,
-		Role:         user.Role,
-		PhoneNumber:  user.PhoneNumber,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:            user.Id,
+		NIM:           user.NIM,
+		FullName:      user.FullName,
+		StudyProgram:  user.StudyProgram,
+		Role:          user.Role,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		DeactivatedAt: user.DeactivatedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 }
 
@@ -28,15 +30,17 @@ func ToUserResponses(users []domain.User) []web.UserResponse {
 
 func ToUserGetByNimResponse(user domain.User) web.UserGetByNimResponse {
 	return web.UserGetByNimResponse{
-		ID:           user.Id,
-		NIM:          user.NIM,
-		FullName:     user.FullName,
-		StudyProgram: user.StudyProgram,
-		Password:     user.Password,
-		Role:         user.Role,
-		PhoneNumber:  user.PhoneNumber,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:            user.Id,
+		NIM:           user.NIM,
+		FullName:      user.FullName,
+		StudyProgram:  user.StudyProgram,
+		Password:      user.Password,
+		Role:          user.Role,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		DeactivatedAt: user.DeactivatedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 }
 
@@ -58,6 +62,7 @@ func ToAdminResponse(admin domain.User) web.AdminResponse {
 		Password:     admin.Password,
 		Role:         admin.Role,
 		PhoneNumber:  admin.PhoneNumber,
+		Email:        admin.Email,
 		CreatedAt:    admin.CreatedAt,
 		UpdatedAt:    admin.UpdatedAt,
 	}
@@ -70,3 +75,42 @@ func ToAdminResponses(admins []domain.User) []web.AdminResponse {
 	}
 	return adminResponses
 }
+
+func ToPhoneNumberOwner(user domain.User) web.PhoneNumberOwner {
+	return web.PhoneNumberOwner{
+		UserId:      user.Id,
+		NIM:         user.NIM,
+		FullName:    user.FullName,
+		PhoneNumber: user.PhoneNumber,
+	}
+}
+
+func ToProfileChangeRequestResponse(request domain.ProfileChangeRequest) web.ProfileChangeRequestResponse {
+	changes := make(map[string]web.UserFieldChange, len(request.Changes))
+	for field, change := range request.Changes {
+		changes[field] = web.UserFieldChange{Old: change.Old, New: change.New}
+	}
+
+	return web.ProfileChangeRequestResponse{
+		ID:           request.Id,
+		UserId:       request.UserId,
+		UserNIM:      request.UserNIM,
+		UserFullName: request.UserFullName,
+		Changes:      changes,
+		Note:         request.Note,
+		Status:       request.Status,
+		ReviewedBy:   request.ReviewedBy,
+		ReviewNote:   request.ReviewNote,
+		ReviewedAt:   request.ReviewedAt,
+		CreatedAt:    request.CreatedAt,
+		UpdatedAt:    request.UpdatedAt,
+	}
+}
+
+func ToProfileChangeRequestResponses(requests []domain.ProfileChangeRequest) []web.ProfileChangeRequestResponse {
+	profileChangeRequestResponses := []web.ProfileChangeRequestResponse{}
+	for _, request := range requests {
+		profileChangeRequestResponses = append(profileChangeRequestResponses, ToProfileChangeRequestResponse(request))
+	}
+	return profileChangeRequestResponses
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/export"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+// VoterRollContentType returns the content type of a voter roll format
+func VoterRollContentType(format string) string {
+	if format == "xlsx" {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// NewVoterRollWriter returns a CSV or XLSX writer for the voter roll
+func NewVoterRollWriter(w io.Writer, format string, period int) (export.VoterRollWriter, error) {
+	switch format {
+	case "csv":
+		return export.NewVoterRollCSVWriter(w)
+	case "xlsx":
+		return export.NewVoterRollXLSXWriter(w, period)
+	default:
+		return nil, fmt.Errorf("unsupported voter roll format '%s'", format)
+	}
+}
+
+func ToVoterRollRow(entry domain.VoterRollEntry) export.VoterRollRow {
+	return export.VoterRollRow{
+		NIM:          entry.NIM,
+		FullName:     entry.FullName,
+		StudyProgram: entry.StudyProgram,
+		HasVoted:     entry.HasVoted,
+	}
+}
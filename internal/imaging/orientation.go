@@ -0,0 +1,141 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// exifOrientationTag is the IFD0 tag holding how the camera was rotated
+const exifOrientationTag = 0x0112
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG file, or 1
+// when the data is not a JPEG or carries no orientation
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	// Walk the segments until the image data starts
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte before a marker
+			i++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			// Start of scan or end of image
+			return 1
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+
+		// APP1 holds the EXIF data
+		if marker == 0xE1 {
+			if orientation := exifOrientation(data[i+4 : i+2+size]); orientation != 0 {
+				return orientation
+			}
+		}
+
+		i += 2 + size
+	}
+
+	return 1
+}
+
+// exifOrientation reads the orientation tag from an APP1 segment, returning
+// 0 when there is none
+func exifOrientation(segment []byte) int {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := segment[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 0
+		}
+	}
+
+	return 0
+}
+
+// orient flips and rotates img so it is displayed upright for the given
+// EXIF orientation
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// Orientations 5 to 8 swap width and height
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				dx, dy = width-1-x, y
+			case 3: // Rotated 180°
+				dx, dy = width-1-x, height-1-y
+			case 4: // Mirrored vertically
+				dx, dy = x, height-1-y
+			case 5: // Transposed
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise
+				dx, dy = height-1-y, x
+			case 7: // Transversed
+				dx, dy = height-1-y, width-1-x
+			case 8: // Rotated 90° counter clockwise
+				dx, dy = y, width-1-x
+			}
+
+			src := img.PixOffset(x+img.Rect.Min.X, y+img.Rect.Min.Y)
+			copy(dst.Pix[dst.PixOffset(dx, dy):], img.Pix[src:src+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,120 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Standard candidate photo variants. Both fit inside a square box, so
+// portrait and landscape photos keep their aspect ratio.
+const (
+	FullMaxSize      = 1080
+	ThumbnailMaxSize = 320
+	JPEGQuality      = 85
+)
+
+// MaxPixels rejects images whose header claims huge dimensions before they
+// are decoded, as a small file can still expand to gigabytes in memory
+const MaxPixels = 40_000_000
+
+// ErrUnsupportedImage is returned for data that is not a JPEG, PNG or WebP image
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// Variant is a re-encoded JPEG image
+type Variant struct {
+	Content []byte
+	Width   int
+	Height  int
+}
+
+// PhotoVariants holds the standardized versions of an uploaded photo
+type PhotoVariants struct {
+	Format    string // Format of the uploaded image e.g: "jpeg", "png" or "webp"
+	Full      Variant
+	Thumbnail Variant
+}
+
+// ProcessPhoto decodes an uploaded photo and re-encodes it as a full size and
+// a thumbnail JPEG. Only pixels are written back, so EXIF data such as GPS
+// location is dropped. The EXIF orientation is applied first so phone
+// photos aren't shown sideways.
+func ProcessPhoto(data []byte) (PhotoVariants, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return PhotoVariants{}, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return PhotoVariants{}, fmt.Errorf("%w: %dx%d pixels is too large", ErrUnsupportedImage, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return PhotoVariants{}, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	// Orient after scaling down, as it is cheaper on the smaller image. The
+	// box is square, so swapping width and height doesn't change the fit.
+	full := orient(fit(img, FullMaxSize), jpegOrientation(data))
+	thumbnail := fit(full, ThumbnailMaxSize)
+
+	fullVariant, err := encodeJPEG(full)
+	if err != nil {
+		return PhotoVariants{}, err
+	}
+
+	thumbnailVariant, err := encodeJPEG(thumbnail)
+	if err != nil {
+		return PhotoVariants{}, err
+	}
+
+	return PhotoVariants{
+		Format:    format,
+		Full:      fullVariant,
+		Thumbnail: thumbnailVariant,
+	}, nil
+}
+
+// fit scales img down to fit inside a maxSize square. Smaller images are
+// not scaled up. Transparent areas are flattened on white, as JPEG has no
+// alpha channel.
+func fit(img image.Image, maxSize int) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	return dst
+}
+
+func encodeJPEG(img *image.RGBA) (Variant, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return Variant{}, fmt.Errorf("failed to encode jpeg: %w", err)
+	}
+
+	return Variant{
+		Content: buf.Bytes(),
+		Width:   img.Bounds().Dx(),
+		Height:  img.Bounds().Dy(),
+	}, nil
+}
@@ -8,13 +8,17 @@ import (
 	"github.com/julienschmidt/httprouter"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/service"
 )
 
 type contextKey string
 
-const SessionContextKey contextKey = "session"
+const (
+	SessionContextKey contextKey = "session"
+	UserContextKey    contextKey = "user"
+)
 
 func UserMiddleware(next httprouter.Handle, authService service.AuthService) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
@@ -36,7 +40,7 @@ func UserMiddleware(next httprouter.Handle, authService service.AuthService) htt
 		}
 
 		// Call service
-		session, err := authService.UserValidateSession(r.Context(), cookie.Value)
+		session, user, err := authService.UserValidateSession(r.Context(), cookie.Value)
 		if err != nil {
 			var customError *appError.AppError
 
@@ -65,13 +69,15 @@ func UserMiddleware(next httprouter.Handle, authService service.AuthService) htt
 			}
 		}
 
-		// Send session data through context
+		// Send session and user data through context
 		ctx := context.WithValue(r.Context(), SessionContextKey, session)
+		ctx = context.WithValue(ctx, UserContextKey, user)
 		next(w, r.WithContext(ctx), params)
 	}
 }
 
-func AdminMiddleware(next httprouter.Handle, authService service.AuthService) httprouter.Handle {
+// PermissionMiddleware only lets through users whose role is granted the permission
+func PermissionMiddleware(next httprouter.Handle, authService service.AuthService, permission domain.Permission) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 		// Get cookie from header
 		cookie, err := r.Cookie("e_election_session")
@@ -91,7 +97,7 @@ func AdminMiddleware(next httprouter.Handle, authService service.AuthService) ht
 		}
 
 		// Call service
-		session, err := authService.AdminValidateSession(r.Context(), cookie.Value)
+		session, user, err := authService.PermissionValidateSession(r.Context(), cookie.Value, permission)
 		if err != nil {
 			var customError *appError.AppError
 
@@ -120,8 +126,9 @@ func AdminMiddleware(next httprouter.Handle, authService service.AuthService) ht
 			}
 		}
 
-		// Send session data through context
+		// Send session and user data through context
 		ctx := context.WithValue(r.Context(), SessionContextKey, session)
+		ctx = context.WithValue(ctx, UserContextKey, user)
 		next(w, r.WithContext(ctx), params)
 	}
 }
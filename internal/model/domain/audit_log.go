@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit log target types
+const (
+	AuditTargetUser      = "user"
+	AuditTargetCandidate = "candidate"
+)
+
+// Audit log actions
+const (
+	AuditActionPasswordRegenerated   = "user.password_regenerated"
+	AuditActionVotingTokenIssued     = "user.voting_token_issued"
+	AuditActionCredentialExported    = "user.credential_exported"
+	AuditActionUserDeactivated       = "user.deactivated"
+	AuditActionUserRestored          = "user.restored"
+	AuditActionProfileChangeApproved = "user.profile_change_approved"
+	AuditActionProfileChangeRejected = "user.profile_change_rejected"
+	AuditActionCandidateCorrected    = "candidate.corrected_after_lock"
+)
+
+type AuditLog struct {
+	Id         int             `json:"id"`
+	ActorId    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   *int            `json:"target_id"`
+	Details    json.RawMessage `json:"details"`
+	CreatedAt  time.Time       `json:"created_at"`
+
+	// Joined from users
+	ActorName string `json:"actor_name"`
+}
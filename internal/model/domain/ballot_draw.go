@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Ballot draw statuses. A committed draw is revealed to assign the numbers,
+// or cancelled to start over.
+const (
+	BallotDrawStatusCommitted = "committed"
+	BallotDrawStatusRevealed  = "revealed"
+	BallotDrawStatusCancelled = "cancelled"
+)
+
+type BallotDraw struct {
+	Id               int                `json:"id"`
+	Period           int                `json:"period"`
+	Status           string             `json:"status"`
+	CandidateIds     []int              `json:"candidate_ids"`
+	SecretSeed       string             `json:"secret_seed"`
+	Commitment       string             `json:"commitment"`
+	PublicSeedSource string             `json:"public_seed_source"`
+	PublicSeed       string             `json:"public_seed"`
+	Result           []BallotDrawNumber `json:"result"`
+	CommittedBy      *int               `json:"committed_by"`
+	RevealedBy       *int               `json:"revealed_by"`
+	CancelledBy      *int               `json:"cancelled_by"`
+	CreatedAt        time.Time          `json:"created_at"`
+	RevealedAt       *time.Time         `json:"revealed_at"`
+	CancelledAt      *time.Time         `json:"cancelled_at"`
+}
+
+// BallotDrawNumber is the number a revealed draw assigned to a candidate
+type BallotDrawNumber struct {
+	CandidateId int `json:"candidate_id"`
+	Number      int `json:"number"`
+}
+
+// VotingPeriod is the voting schedule of a period
+type VotingPeriod struct {
+	Period    int       `json:"period"`
+	OpensAt   time.Time `json:"opens_at"`
+	ClosesAt  time.Time `json:"closes_at"`
+	UpdatedBy *int      `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
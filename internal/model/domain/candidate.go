@@ -8,7 +8,7 @@ type Candidate struct {
 	President             string    `json:"president"`
 	Vice                  string    `json:"vice"`
 	Vision                string    `json:"vision"`
-	Mission               string    `json:"mission"`
+	Mission               []string  `json:"mission"`
 	PhotoKey              string    `json:"photo_key"`
 	PresidentStudyProgram string    `json:"president_study_program"`
 	ViceStudyProgram      string    `json:"vice_study_program"`
@@ -18,14 +18,21 @@ type Candidate struct {
 	UpdatedAt             time.Time `json:"updated_at"`
 }
 
+// CandidateFieldChange is the old and new value of a changed candidate field
+type CandidateFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
 type CandidateWithURL struct {
 	Id                    int       `json:"id"`
 	Number                int       `json:"number"`
 	President             string    `json:"president"`
 	Vice                  string    `json:"vice"`
 	Vision                string    `json:"vision"`
-	Mission               string    `json:"mission"`
+	Mission               []string  `json:"mission"`
 	PhotoURL              string    `json:"photo_url"`
+	ThumbnailURL          string    `json:"thumbnail_url"`
 	PresidentStudyProgram string    `json:"president_study_program"`
 	ViceStudyProgram      string    `json:"vice_study_program"`
 	PresidentNIM          string    `json:"president_nim"`
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Candidacy application statuses. A submitted application is verified by the
+// committee, then approved as a candidate or rejected.
+const (
+	CandidateApplicationStatusSubmitted = "submitted"
+	CandidateApplicationStatusVerified  = "verified"
+	CandidateApplicationStatusRejected  = "rejected"
+	CandidateApplicationStatusApproved  = "approved"
+)
+
+type CandidateApplication struct {
+	Id          int       `json:"id"`
+	Period      int       `json:"period"`
+	PresidentId int       `json:"president_id"`
+	ViceId      int       `json:"vice_id"`
+	Vision      string    `json:"vision"`
+	Mission     []string  `json:"mission"`
+	Status      string    `json:"status"`
+	SubmittedBy *int      `json:"submitted_by"`
+	CandidateId *int      `json:"candidate_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Joined from users
+	PresidentNIM          string `json:"president_nim"`
+	President             string `json:"president"`
+	PresidentStudyProgram string `json:"president_study_program"`
+	ViceNIM               string `json:"vice_nim"`
+	Vice                  string `json:"vice"`
+	ViceStudyProgram      string `json:"vice_study_program"`
+}
+
+// CandidateApplicationComment is a committee note on an application. Status
+// is the status the application moved to, or empty for a plain comment.
+type CandidateApplicationComment struct {
+	Id            int       `json:"id"`
+	ApplicationId int       `json:"application_id"`
+	AuthorId      *int      `json:"author_id"`
+	Status        string    `json:"status"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Joined from users
+	AuthorName string `json:"author_name"`
+}
+
+// CandidateApplicationFilter selects applications. Empty fields do not filter.
+type CandidateApplicationFilter struct {
+	Period int
+	Status string
+}
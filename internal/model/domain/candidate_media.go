@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Candidate media types. Posters and documents are files in object storage,
+// the other types are external links.
+const (
+	CandidateMediaPoster   = "poster"   // Campaign poster, a confirmed photo
+	CandidateMediaDocument = "document" // PDF such as the full vision and mission
+	CandidateMediaVideo    = "video"    // Campaign video link
+	CandidateMediaSocial   = "social"   // Social media profile link
+	CandidateMediaLink     = "link"     // Any other link
+)
+
+type CandidateMedia struct {
+	Id          int       `json:"id"`
+	CandidateId int       `json:"candidate_id"`
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	ObjectKey   string    `json:"object_key"`
+	URL         string    `json:"url"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IsFile reports whether the media is stored in object storage
+func (media CandidateMedia) IsFile() bool {
+	return media.Type == CandidateMediaPoster || media.Type == CandidateMediaDocument
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// CandidateRevision is an immutable record of a change of a candidate.
+// Revisions of a candidate are numbered from 1.
+type CandidateRevision struct {
+	Id          int                             `json:"id"`
+	CandidateId int                             `json:"candidate_id"`
+	Revision    int                             `json:"revision"`
+	Changes     map[string]CandidateFieldChange `json:"changes"`
+	Reason      string                          `json:"reason"`
+	ActorId     *int                            `json:"actor_id"`
+	CreatedAt   time.Time                       `json:"created_at"`
+
+	// Joined from users
+	ActorName string `json:"actor_name"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Candidate upload statuses
+const (
+	CandidateUploadStatusIssued    = "issued"    // Presigned URL handed out
+	CandidateUploadStatusConfirmed = "confirmed" // File validated and stored under its final key
+	CandidateUploadStatusRejected  = "rejected"  // File failed validation and was deleted
+	CandidateUploadStatusExpired   = "expired"   // Never confirmed within the grace period
+	CandidateUploadStatusDeleted   = "deleted"   // Removed by the reconciler as no candidate uses it
+)
+
+type CandidateUpload struct {
+	Id        int       `json:"id"`
+	UploadKey string    `json:"upload_key"`
+	ObjectKey string    `json:"object_key"`
+	Status    string    `json:"status"`
+	IssuedBy  *int      `json:"issued_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
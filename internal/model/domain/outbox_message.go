@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Outbox message statuses
+const (
+	OutboxStatusPending    = "pending"
+	OutboxStatusProcessing = "processing"
+	OutboxStatusSent       = "sent"
+	OutboxStatusFailed     = "failed"
+	OutboxStatusCancelled  = "cancelled"
+)
+
+type OutboxMessage struct {
+	Id            int        `json:"id"`
+	UserId        *int       `json:"user_id"`
+	Template      string     `json:"template"`
+	RecipientName string     `json:"recipient_name"`
+	PhoneNumber   string     `json:"phone_number"`
+	Email         string     `json:"email"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type PasswordResetOTP struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	HashedOTP string    `json:"hashed_otp"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
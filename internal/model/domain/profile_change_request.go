@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// Profile change request statuses
+const (
+	ProfileChangeStatusPending  = "pending"
+	ProfileChangeStatusApproved = "approved"
+	ProfileChangeStatusRejected = "rejected"
+)
+
+// ProfileFieldChange is a requested correction of a single user column
+type ProfileFieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type ProfileChangeRequest struct {
+	Id         int                           `json:"id"`
+	UserId     int                           `json:"user_id"`
+	Changes    map[string]ProfileFieldChange `json:"changes"`
+	Note       string                        `json:"note"`
+	Status     string                        `json:"status"`
+	ReviewedBy *int                          `json:"reviewed_by"`
+	ReviewNote string                        `json:"review_note"`
+	ReviewedAt *time.Time                    `json:"reviewed_at"`
+	CreatedAt  time.Time                     `json:"created_at"`
+	UpdatedAt  time.Time                     `json:"updated_at"`
+
+	// Joined from users
+	UserNIM      string `json:"user_nim"`
+	UserFullName string `json:"user_full_name"`
+}
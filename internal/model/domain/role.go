@@ -0,0 +1,27 @@
+package domain
+
+// Roles stored in users.role
+const (
+	RoleSuperAdmin        = "super_admin"
+	RoleAdmin             = "admin"
+	RoleCandidateManager  = "candidate_manager"
+	RoleCommitteeObserver = "committee_observer"
+	RoleStudent           = "student"
+)
+
+// Permission is a named action that a role may perform
+type Permission string
+
+const (
+	PermissionUserRead        Permission = "user:read"
+	PermissionUserWrite       Permission = "user:write"
+	PermissionUserCredential  Permission = "user:credential"
+	PermissionRoleManage      Permission = "role:manage"
+	PermissionCandidateRead   Permission = "candidate:read"
+	PermissionCandidateWrite  Permission = "candidate:write"
+	PermissionVoteCast        Permission = "vote:cast"
+	PermissionVoteResultRead  Permission = "vote:result:read"
+	PermissionVoteLogDownload Permission = "vote:log:download"
+	PermissionVoterRollExport Permission = "vote:roll:export"
+	PermissionElectionManage  Permission = "election:manage"
+)
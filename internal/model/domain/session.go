@@ -8,3 +8,8 @@ type Session struct {
 	CreatedAt     time.Time `json:"created_at"`
 	MaxAgeSeconds int       `json:"max_age_seconds"`
 }
+
+type SessionWithUser struct {
+	Session Session `json:"session"`
+	User    User    `json:"user"`
+}
@@ -3,13 +3,47 @@ package domain
 import "time"
 
 type User struct {
-	Id           int       `json:"id"`
-	NIM          string    `json:"nim"`
-	FullName     string    `json:"full_name"`
-	StudyProgram string    `json:"study_program"`
-	Password     string    `json:"password,omitempty"`
-	Role         string    `json:"role"`
-	PhoneNumber  string    `json:"phone_number"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	Id           int    `json:"id"`
+	NIM          string `json:"nim"`
+	FullName     string `json:"full_name"`
+	StudyProgram string `json:"study_program"`
+	Password     string `json:"password,omitempty"`
+	Role         string `json:"role"`
+	PhoneNumber  string `json:"phone_number"`
+	Email        string `json:"email"`
+	// DeactivatedAt is set when the user is removed from the voter roll
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// UserCredentialFilter selects the students whose credentials are regenerated.
+// Every non-empty field narrows the selection.
+type UserCredentialFilter struct {
+	Ids          []int
+	StudyProgram string
+	NotVoted     bool
+	// HasVotingAccess keeps only users with a voting_access entry
+	HasVotingAccess bool
+}
+
+// User statuses derived from deactivated_at
+const (
+	UserStatusActive      = "active"
+	UserStatusDeactivated = "deactivated"
+)
+
+// UserListFilter selects a page of users. Empty fields do not filter.
+type UserListFilter struct {
+	Search       string
+	Role         string
+	StudyProgram string
+	Status       string
+	HasPassword  *bool
+	// HasVoted checks votes in the current period
+	HasVoted *bool
+	SortBy   string
+	SortDesc bool
+	Limit    int
+	Offset   int
 }
@@ -0,0 +1,11 @@
+package domain
+
+// VoterRollEntry is an eligible student and whether they voted in a period.
+// It never carries the chosen candidate.
+type VoterRollEntry struct {
+	UserId       int
+	NIM          string
+	FullName     string
+	StudyProgram string
+	HasVoted     bool
+}
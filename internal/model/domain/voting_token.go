@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type VotingToken struct {
+	Id          int        `json:"id"`
+	UserId      int        `json:"user_id"`
+	HashedToken string     `json:"hashed_token"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
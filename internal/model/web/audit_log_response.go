@@ -0,0 +1,15 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLogResponse struct {
+	Id        int             `json:"id"`
+	ActorId   *int            `json:"actor_id"`
+	ActorName string          `json:"actor_name"`
+	Action    string          `json:"action"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
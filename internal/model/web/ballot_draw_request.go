@@ -0,0 +1,21 @@
+package web
+
+import "time"
+
+type VotingPeriodRequest struct {
+	OpensAt  time.Time `json:"opens_at" validate:"required"`
+	ClosesAt time.Time `json:"closes_at" validate:"required,gtfield=OpensAt"`
+}
+
+// BallotDrawCommitRequest names where the public seed will come from before
+// anyone can know it, e.g. a future beacon round or the dice protocol of the
+// draw event
+type BallotDrawCommitRequest struct {
+	PublicSeedSource string `json:"public_seed_source" validate:"required,min=8,max=255"`
+}
+
+// BallotDrawRevealRequest carries the public seed, a value nobody could know
+// when the draw was committed, e.g. dice rolled at the draw event
+type BallotDrawRevealRequest struct {
+	PublicSeed string `json:"public_seed" validate:"required,min=8,max=255"`
+}
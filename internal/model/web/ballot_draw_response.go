@@ -0,0 +1,39 @@
+package web
+
+import "time"
+
+type VotingPeriodResponse struct {
+	Period        int       `json:"period"`
+	OpensAt       time.Time `json:"opens_at"`
+	ClosesAt      time.Time `json:"closes_at"`
+	VotingStarted bool      `json:"voting_started"`
+	UpdatedBy     *int      `json:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BallotDrawResponse hides the secret seed until the draw is revealed or
+// cancelled
+type BallotDrawResponse struct {
+	Id               int                        `json:"id"`
+	Period           int                        `json:"period"`
+	Status           string                     `json:"status"`
+	CandidateIds     []int                      `json:"candidate_ids"`
+	Commitment       string                     `json:"commitment"`
+	PublicSeedSource string                     `json:"public_seed_source"`
+	SecretSeed       string                     `json:"secret_seed,omitempty"`
+	PublicSeed       string                     `json:"public_seed,omitempty"`
+	FinalSeed        string                     `json:"final_seed,omitempty"`
+	Result           []BallotDrawNumberResponse `json:"result"`
+	CommittedBy      *int                       `json:"committed_by"`
+	RevealedBy       *int                       `json:"revealed_by"`
+	CancelledBy      *int                       `json:"cancelled_by"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	RevealedAt       *time.Time                 `json:"revealed_at"`
+	CancelledAt      *time.Time                 `json:"cancelled_at"`
+}
+
+type BallotDrawNumberResponse struct {
+	CandidateId int    `json:"candidate_id"`
+	Number      int    `json:"number"`
+	Ticket      string `json:"ticket"`
+}
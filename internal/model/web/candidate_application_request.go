@@ -0,0 +1,25 @@
+package web
+
+type CandidateApplicationCreateRequest struct {
+	PresidentNIM string   `json:"president_nim" validate:"required,min=4,max=14"`
+	ViceNIM      string   `json:"vice_nim" validate:"required,min=4,max=14"`
+	Vision       string   `json:"vision" validate:"omitempty,min=3"`
+	Mission      []string `json:"mission" validate:"omitempty,min=1,dive,min=3"`
+}
+
+type CandidateApplicationCommentRequest struct {
+	Comment string `json:"comment" validate:"required,min=3,max=1000"`
+}
+
+// CandidateApplicationReviewRequest moves an application to the next status
+// with an optional comment
+type CandidateApplicationReviewRequest struct {
+	Comment string `json:"comment" validate:"omitempty,min=3,max=1000"`
+}
+
+// CandidateApplicationApproveRequest needs the photo the committee uploaded
+// for the ballot
+type CandidateApplicationApproveRequest struct {
+	PhotoKey string `json:"photo_key" validate:"required"`
+	Comment  string `json:"comment" validate:"omitempty,min=3,max=1000"`
+}
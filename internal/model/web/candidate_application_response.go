@@ -0,0 +1,33 @@
+package web
+
+import "time"
+
+type CandidateApplicationResponse struct {
+	Id                    int                                   `json:"id"`
+	Period                int                                   `json:"period"`
+	PresidentId           int                                   `json:"president_id"`
+	PresidentNIM          string                                `json:"president_nim"`
+	President             string                                `json:"president"`
+	PresidentStudyProgram string                                `json:"president_study_program"`
+	ViceId                int                                   `json:"vice_id"`
+	ViceNIM               string                                `json:"vice_nim"`
+	Vice                  string                                `json:"vice"`
+	ViceStudyProgram      string                                `json:"vice_study_program"`
+	Vision                string                                `json:"vision"`
+	Mission               []string                              `json:"mission"`
+	Status                string                                `json:"status"`
+	SubmittedBy           *int                                  `json:"submitted_by"`
+	CandidateId           *int                                  `json:"candidate_id"`
+	Comments              []CandidateApplicationCommentResponse `json:"comments"`
+	CreatedAt             time.Time                             `json:"created_at"`
+	UpdatedAt             time.Time                             `json:"updated_at"`
+}
+
+type CandidateApplicationCommentResponse struct {
+	Id         int       `json:"id"`
+	AuthorId   *int      `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Status     string    `json:"status"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
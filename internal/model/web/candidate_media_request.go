@@ -0,0 +1,21 @@
+package web
+
+// CandidateMediaCreateRequest attaches a file or a link to a candidate.
+// Posters and documents need the key returned by the upload API, the other
+// types need a URL.
+type CandidateMediaCreateRequest struct {
+	Type      string `json:"type" validate:"required,oneof=poster document video social link"`
+	Title     string `json:"title" validate:"omitempty,max=255"`
+	ObjectKey string `json:"object_key" validate:"omitempty"`
+	URL       string `json:"url" validate:"omitempty,max=2048,http_url"`
+}
+
+type CandidateMediaUpdateRequest struct {
+	Title string `json:"title" validate:"omitempty,max=255"`
+	URL   string `json:"url" validate:"omitempty,max=2048,http_url"`
+}
+
+// CandidateMediaOrderRequest lists every media id of a candidate in display order
+type CandidateMediaOrderRequest struct {
+	MediaIds []int `json:"media_ids" validate:"required,min=1,dive,min=1"`
+}
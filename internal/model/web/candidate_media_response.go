@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+type CandidateMediaResponse struct {
+	Id           int       `json:"id"`
+	CandidateId  int       `json:"candidate_id"`
+	Type         string    `json:"type"`
+	Title        string    `json:"title"`
+	ObjectKey    string    `json:"object_key,omitempty"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -5,7 +5,7 @@ type CandidateCreateRequest struct {
 	President             string   `json:"president" validate:"required,min=3,max=255"`
 	Vice                  string   `json:"vice" validate:"required,min=3,max=255"`
 	Vision                string   `json:"vision" validate:"omitempty,min=3"`
-	Mission               []string `json:"mission" validate:"omitempty,min=1"`
+	Mission               []string `json:"mission" validate:"omitempty,min=1,dive,min=3"`
 	PhotoKey              string   `json:"photo_key" validate:"required"`
 	PresidentStudyProgram string   `json:"president_study_program" validate:"required,min=3,max=100"`
 	ViceStudyProgram      string   `json:"vice_study_program" validate:"required,min=3,max=100"`
@@ -18,10 +18,13 @@ type CandidateUpdateRequest struct {
 	President             string   `json:"president" validate:"omitempty,min=3,max=255"`
 	Vice                  string   `json:"vice" validate:"omitempty,min=3,max=255"`
 	Vision                string   `json:"vision" validate:"omitempty,min=3"`
-	Mission               []string `json:"mission" validate:"omitempty,min=1"`
+	Mission               []string `json:"mission" validate:"omitempty,min=1,dive,min=3"`
 	PhotoKey              string   `json:"photo_key" validate:"omitempty"`
 	PresidentStudyProgram string   `json:"president_study_program" validate:"omitempty,min=3,max=100"`
 	ViceStudyProgram      string   `json:"vice_study_program" validate:"omitempty,min=3,max=100"`
 	PresidentNIM          string   `json:"president_nim" validate:"omitempty,min=4,max=14"`
 	ViceNIM               string   `json:"vice_nim" validate:"omitempty,min=4,max=14"`
+
+	// OverrideReason is required to correct a candidate once voting has started
+	OverrideReason string `json:"override_reason" validate:"omitempty,min=10,max=500"`
 }
@@ -19,17 +19,19 @@ type CandidateResponse struct {
 }
 
 type CandidateResponseWithURL struct {
-	Id                    int       `json:"id"`
-	Number                int       `json:"number"`
-	President             string    `json:"president"`
-	Vice                  string    `json:"vice"`
-	Vision                string    `json:"vision"`
-	Mission               []string  `json:"mission"`
-	PhotoURL              string    `json:"photo_url"`
-	PresidentStudyProgram string    `json:"president_study_program"`
-	ViceStudyProgram      string    `json:"vice_study_program"`
-	PresidentNIM          string    `json:"president_nim"`
-	ViceNIM               string    `json:"vice_nim"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	Id                    int                      `json:"id"`
+	Number                int                      `json:"number"`
+	President             string                   `json:"president"`
+	Vice                  string                   `json:"vice"`
+	Vision                string                   `json:"vision"`
+	Mission               []string                 `json:"mission"`
+	PhotoURL              string                   `json:"photo_url"`
+	ThumbnailURL          string                   `json:"thumbnail_url"`
+	PresidentStudyProgram string                   `json:"president_study_program"`
+	ViceStudyProgram      string                   `json:"vice_study_program"`
+	PresidentNIM          string                   `json:"president_nim"`
+	ViceNIM               string                   `json:"vice_nim"`
+	Media                 []CandidateMediaResponse `json:"media"`
+	CreatedAt             time.Time                `json:"created_at"`
+	UpdatedAt             time.Time                `json:"updated_at"`
 }
@@ -0,0 +1,17 @@
+package web
+
+import "time"
+
+type CandidateRevisionResponse struct {
+	Revision  int                                     `json:"revision"`
+	Changes   map[string]CandidateFieldChangeResponse `json:"changes"`
+	Reason    string                                  `json:"reason"`
+	ActorId   *int                                    `json:"actor_id"`
+	ActorName string                                  `json:"actor_name"`
+	CreatedAt time.Time                               `json:"created_at"`
+}
+
+type CandidateFieldChangeResponse struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
@@ -0,0 +1,18 @@
+package web
+
+import "io"
+
+// FileResponse is a generated file written directly to the response body
+type FileResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// FileStream is a file written to the response body as it is generated.
+// WriteTo is only called after the response headers are sent.
+type FileStream struct {
+	FileName    string
+	ContentType string
+	WriteTo     func(w io.Writer) error
+}
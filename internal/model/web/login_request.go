@@ -3,4 +3,10 @@ package web
 type LoginRequest struct {
 	NIM      string `json:"nim" validate:"omitempty,min=4,max=14"`
 	Password string `json:"password" validate:"required,min=6,max=100"`
+	// Token logs in with a one-time voting token instead of NIM and password
+	Token string `json:"token"`
+}
+
+type LoginTokenRequest struct {
+	Token string `json:"token" validate:"required,min=8,max=64"`
 }
@@ -9,6 +9,7 @@ type LoginResponse struct {
 	StudyProgram string    `json:"study_program"`
 	Role         string    `json:"role"`
 	PhoneNumber  string    `json:"phone_number"`
+	Email        string    `json:"email"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -0,0 +1,19 @@
+package web
+
+import "time"
+
+type OutboxMessageResponse struct {
+	ID            int        `json:"id"`
+	UserId        *int       `json:"user_id"`
+	Template      string     `json:"template"`
+	RecipientName string     `json:"recipient_name"`
+	PhoneNumber   string     `json:"phone_number"`
+	Email         string     `json:"email"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package web
+
+// PhoneNumberOwner is a user listed in the phone number migration report
+type PhoneNumberOwner struct {
+	UserId      int    `json:"user_id"`
+	NIM         string `json:"nim,omitempty"`
+	FullName    string `json:"full_name"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// PhoneNumberCollision groups users whose stored numbers are the same
+// number once normalized
+type PhoneNumberCollision struct {
+	PhoneNumber string             `json:"phone_number"`
+	Users       []PhoneNumberOwner `json:"users"`
+}
+
+type PhoneNumberMigrationReport struct {
+	DryRun           bool                   `json:"dry_run"`
+	TotalUsers       int                    `json:"total_users"`
+	AlreadyCanonical int                    `json:"already_canonical"`
+	Normalized       int                    `json:"normalized"`
+	Invalid          []PhoneNumberOwner     `json:"invalid"`
+	Collisions       []PhoneNumberCollision `json:"collisions"`
+}
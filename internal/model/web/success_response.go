@@ -4,3 +4,16 @@ type WebSuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
 }
+
+// WebPaginatedResponse is a WebSuccessResponse carrying a page of Data
+type WebPaginatedResponse struct {
+	WebSuccessResponse
+	Meta PaginationMeta `json:"meta"`
+}
+
+type PaginationMeta struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalItems int `json:"total_items"`
+	TotalPages int `json:"total_pages"`
+}
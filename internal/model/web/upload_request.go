@@ -0,0 +1,5 @@
+package web
+
+type CandidateUploadConfirmRequest struct {
+	FileName string `json:"file_name" validate:"required"`
+}
@@ -4,3 +4,17 @@ type PresignedURLResponse struct {
 	URL      string `json:"url"`
 	FileName string `json:"file_name"`
 }
+
+type CandidatePhotoResponse struct {
+	PhotoKey     string `json:"photo_key"`
+	PhotoURL     string `json:"photo_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+type CandidateDocumentResponse struct {
+	DocumentKey string `json:"document_key"`
+	DocumentURL string `json:"document_url"`
+	Size        int    `json:"size"`
+}
@@ -0,0 +1,53 @@
+package web
+
+// UserImportRow is a single roster row read from an uploaded file
+type UserImportRow struct {
+	Sheet        string `json:"sheet,omitempty"`
+	Row          int    `json:"row"`
+	NIM          string `json:"nim" validate:"required,min=4,max=14"`
+	FullName     string `json:"full_name" validate:"required,min=3,max=100"`
+	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
+	PhoneNumber  string `json:"phone_number" validate:"required,phone_id"`
+}
+
+// ImportRowError points at a problem by sheet (XLSX only), row and column
+type ImportRowError struct {
+	Sheet   string `json:"sheet,omitempty"`
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+type UserImportResponse struct {
+	DryRun      bool             `json:"dry_run"`
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	CreatedRows int              `json:"created_rows"`
+	Errors      []ImportRowError `json:"errors"`
+}
+
+// UserFieldChange is the old and new value of a changed field
+type UserFieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type UserSyncUpdate struct {
+	NIM     string                     `json:"nim"`
+	Changes map[string]UserFieldChange `json:"changes"`
+}
+
+// UserSyncResponse is the diff between the roster file and the stored students
+type UserSyncResponse struct {
+	DryRun            bool             `json:"dry_run"`
+	DeactivateMissing bool             `json:"deactivate_missing"`
+	TotalRows         int              `json:"total_rows"`
+	ValidRows         int              `json:"valid_rows"`
+	Created           []string         `json:"created"`
+	Updated           []UserSyncUpdate `json:"updated"`
+	Reactivated       []string         `json:"reactivated"`
+	Deactivated       []string         `json:"deactivated"`
+	Unchanged         int              `json:"unchanged"`
+	Errors            []ImportRowError `json:"errors"`
+}
@@ -1,18 +1,27 @@
 package web
 
+import "time"
+
 type UserCreateRequest struct {
 	NIM          string `json:"nim" validate:"omitempty,min=4,max=14"`
 	FullName     string `json:"full_name" validate:"required,min=3,max=100"`
 	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
 	Password     string `json:"password" validate:"omitempty,min=6,max=100"`
-	Role         string `json:"role"`
-	PhoneNumber  string `json:"phone_number" validate:"required,numericstr,min=8,max=14"`
+	Role         string `json:"role" validate:"omitempty,oneof=super_admin admin candidate_manager committee_observer student"`
+	PhoneNumber  string `json:"phone_number" validate:"required,phone_id"`
+	Email        string `json:"email" validate:"omitempty,email,max=255"`
 }
 
 type UserUpdateCurrentRequest struct {
 	NIM          string `json:"nim" validate:"omitempty,min=4,max=14"`
 	FullName     string `json:"full_name" validate:"omitempty,min=3,max=100"`
 	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
+	PhoneNumber  string `json:"phone_number" validate:"omitempty,phone_id"`
+	Note         string `json:"note" validate:"omitempty,max=255"`
+}
+
+type ProfileChangeRejectRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
 }
 
 type UserUpdateByIdRequest struct {
@@ -20,6 +29,67 @@ type UserUpdateByIdRequest struct {
 	FullName     string `json:"full_name" validate:"omitempty,min=3,max=100"`
 	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
 	Password     string `json:"password" validate:"omitempty,min=6,max=100"`
-	Role         string `json:"role"`
-	PhoneNumber  string `json:"phone_number" validate:"omitempty,numericstr,min=8,max=14"`
+	Role         string `json:"role" validate:"omitempty,oneof=super_admin admin candidate_manager committee_observer student"`
+	PhoneNumber  string `json:"phone_number" validate:"omitempty,phone_id"`
+	Email        string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type UserChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required,min=6,max=100"`
+	NewPassword string `json:"new_password" validate:"required,min=6,max=100,nefield=OldPassword"`
+}
+
+type UserForgotPasswordRequest struct {
+	NIM string `json:"nim" validate:"required,min=4,max=14"`
+}
+
+type UserResetPasswordRequest struct {
+	NIM         string `json:"nim" validate:"required,min=4,max=14"`
+	OTP         string `json:"otp" validate:"required,numericstr,len=6"`
+	NewPassword string `json:"new_password" validate:"required,min=6,max=100"`
+}
+
+type UserRegeneratePasswordRequest struct {
+	UserIds      []int  `json:"user_ids" validate:"omitempty,max=1000,dive,gt=0"`
+	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
+	NotVoted     bool   `json:"not_voted"`
+}
+
+type UserGenerateVotingTokenRequest struct {
+	UserIds      []int      `json:"user_ids" validate:"omitempty,max=1000,dive,gt=0"`
+	StudyProgram string     `json:"study_program" validate:"omitempty,min=3,max=100"`
+	ExpiresAt    *time.Time `json:"expires_at" validate:"omitempty"`
+}
+
+type UserCredentialSheetRequest struct {
+	UserIds      []int  `json:"user_ids" validate:"omitempty,max=1000,dive,gt=0"`
+	StudyProgram string `json:"study_program" validate:"omitempty,min=3,max=100"`
+	NotVoted     bool   `json:"not_voted"`
+	Format       string `json:"format" validate:"omitempty,oneof=pdf csv"`
+}
+
+type UserVoterRollExportRequest struct {
+	Format string `json:"format" validate:"omitempty,oneof=csv xlsx"`
+	Period string `json:"period" validate:"omitempty,numeric,len=4"`
+}
+
+type UserListRequest struct {
+	Page         int    `json:"page" validate:"omitempty,min=1"`
+	PageSize     int    `json:"page_size" validate:"omitempty,min=1,max=100"`
+	Search       string `json:"search" validate:"omitempty,max=100"`
+	Role         string `json:"role" validate:"omitempty,oneof=super_admin admin candidate_manager committee_observer student"`
+	StudyProgram string `json:"study_program" validate:"omitempty,max=100"`
+	Status       string `json:"status" validate:"omitempty,oneof=active deactivated"`
+	HasPassword  *bool  `json:"has_password"`
+	HasVoted     *bool  `json:"has_voted"`
+	Sort         string `json:"sort" validate:"omitempty,oneof=nim full_name study_program role created_at updated_at"`
+	Order        string `json:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+type UserDeactivateRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type UserRestoreRequest struct {
+	Reason string `json:"reason" validate:"omitempty,min=3,max=255"`
 }
@@ -3,26 +3,30 @@ package web
 import "time"
 
 type UserResponse struct {
-	ID           int       `json:"id"`
-	NIM          string    `json:"nim"`
-	FullName     string    `json:"full_name"`
-	StudyProgram string    `json:"study_program"`
-	Role         string    `json:"role"`
-	PhoneNumber  string    `json:"phone_number"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            int        `json:"id"`
+	NIM           string     `json:"nim"`
+	FullName      string     `json:"full_name"`
+	StudyProgram  string     `json:"study_program"`
+	Role          string     `json:"role"`
+	PhoneNumber   string     `json:"phone_number"`
+	Email         string     `json:"email"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type UserGetByNimResponse struct {
-	ID           int       `json:"id"`
-	NIM          string    `json:"nim"`
-	FullName     string    `json:"full_name"`
-	StudyProgram string    `json:"study_program"`
-	Password     string    `json:"password,omitempty"`
-	Role         string    `json:"role"`
-	PhoneNumber  string    `json:"phone_number"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            int        `json:"id"`
+	NIM           string     `json:"nim"`
+	FullName      string     `json:"full_name"`
+	StudyProgram  string     `json:"study_program"`
+	Password      string     `json:"password,omitempty"`
+	Role          string     `json:"role"`
+	PhoneNumber   string     `json:"phone_number"`
+	Email         string     `json:"email"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type SessionResponse struct {
@@ -40,6 +44,33 @@ type AdminResponse struct {
 	Password     string    `json:"password"`
 	Role         string    `json:"role"`
 	PhoneNumber  string    `json:"phone_number"`
+	Email        string    `json:"email"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+type UserRegeneratePasswordResponse struct {
+	Total   int   `json:"total"`
+	UserIds []int `json:"user_ids"`
+}
+
+type UserGenerateVotingTokenResponse struct {
+	Total     int       `json:"total"`
+	UserIds   []int     `json:"user_ids"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ProfileChangeRequestResponse struct {
+	ID           int                        `json:"id"`
+	UserId       int                        `json:"user_id"`
+	UserNIM      string                     `json:"user_nim"`
+	UserFullName string                     `json:"user_full_name"`
+	Changes      map[string]UserFieldChange `json:"changes"`
+	Note         string                     `json:"note"`
+	Status       string                     `json:"status"`
+	ReviewedBy   *int                       `json:"reviewed_by"`
+	ReviewNote   string                     `json:"review_note"`
+	ReviewedAt   *time.Time                 `json:"reviewed_at"`
+	CreatedAt    time.Time                  `json:"created_at"`
+	UpdatedAt    time.Time                  `json:"updated_at"`
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+)
+
+func NewFonnteNotifier(sendURL, apiKey string) Notifier {
+	return &FonnteNotifier{
+		SendURL: sendURL,
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// FonnteNotifier sends WhatsApp messages through the Fonnte API
+type FonnteNotifier struct {
+	SendURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+func (notifier *FonnteNotifier) Send(ctx context.Context, recipient Recipient, message Message) error {
+	if recipient.PhoneNumber == "" {
+		return fmt.Errorf("%w to WhatsApp: recipient '%s' has no phone number", appError.ErrSendMessage, recipient.Name)
+	}
+
+	payload := map[string]string{
+		// Fonnte expects the country code without the leading +
+		"target":  strings.TrimPrefix(recipient.PhoneNumber, "+"),
+		"message": message.Body,
+	}
+
+	// Convert the payload to JSON
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to convert payload to JSON: %w", err)
+	}
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.SendURL, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set the request header
+	req.Header.Set("Authorization", notifier.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send the request to Fonnte
+	resp, err := notifier.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to Fonnte: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var fonnteResp map[string]interface{}
+	if err := json.Unmarshal(body, &fonnteResp); err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	if status, _ := fonnteResp["status"].(bool); !status {
+		return fmt.Errorf("%w to WhatsApp: %v", appError.ErrSendMessage, fonnteResp["reason"])
+	}
+
+	return nil
+}
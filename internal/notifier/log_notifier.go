@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewLogNotifier writes messages to the file at path, or to stdout when path is empty.
+// It is meant for development so credentials can be read without a WhatsApp account.
+func NewLogNotifier(path string) (Notifier, error) {
+	if path == "" {
+		return &LogNotifier{Writer: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notifier log file: %w", err)
+	}
+
+	return &LogNotifier{Writer: file}, nil
+}
+
+// LogNotifier prints every message instead of delivering it
+type LogNotifier struct {
+	Writer io.Writer
+	mutex  sync.Mutex
+}
+
+func (notifier *LogNotifier) Send(ctx context.Context, recipient Recipient, message Message) error {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	_, err := fmt.Fprintf(
+		notifier.Writer,
+		"----- %s -----\nTo: %s <phone: %s, email: %s>\nSubject: %s\n\n%s\n\n",
+		time.Now().Format("2006-01-02 15:04:05"),
+		recipient.Name,
+		recipient.PhoneNumber,
+		recipient.Email,
+		message.Subject,
+		message.Body,
+	)
+
+	return err
+}
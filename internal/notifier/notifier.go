@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/config"
+)
+
+// Supported notifier providers
+const (
+	ProviderFonnte = "fonnte"
+	ProviderSMTP   = "smtp"
+	ProviderLog    = "log"
+)
+
+// Recipient holds the contact details a provider may deliver to
+type Recipient struct {
+	Name        string
+	PhoneNumber string
+	Email       string
+}
+
+// Message is a rendered notification
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notifier delivers a message to a single recipient
+type Notifier interface {
+	Send(ctx context.Context, recipient Recipient, message Message) error
+}
+
+// NewNotifier builds the notifier selected by NOTIFIER_PROVIDER, defaulting to Fonnte
+func NewNotifier(cfg *config.Config) (Notifier, error) {
+	switch cfg.NotifierProvider {
+	case "", ProviderFonnte:
+		return NewFonnteNotifier(cfg.FonnteSendURL, cfg.FonnteAPIKey), nil
+	case ProviderSMTP:
+		return NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+	case ProviderLog:
+		return NewLogNotifier(cfg.NotifierLogPath)
+	default:
+		return nil, fmt.Errorf("unknown notifier provider '%s'", cfg.NotifierProvider)
+	}
+}
@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+)
+
+func NewSMTPNotifier(host, port, username, password, from string) Notifier {
+	return &SMTPNotifier{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// SMTPNotifier sends plain text emails through an SMTP server
+type SMTPNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (notifier *SMTPNotifier) Send(ctx context.Context, recipient Recipient, message Message) error {
+	if recipient.Email == "" {
+		return fmt.Errorf("%w via email: recipient '%s' has no email address", appError.ErrSendMessage, recipient.Name)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if notifier.Username != "" {
+		auth = smtp.PlainAuth("", notifier.Username, notifier.Password, notifier.Host)
+	}
+
+	var msg strings.Builder
+	msg.WriteString("From: " + notifier.From + "\r\n")
+	msg.WriteString("To: " + recipient.Email + "\r\n")
+	msg.WriteString("Subject: " + message.Subject + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(notifier.Host, notifier.Port)
+	err := smtp.SendMail(addr, auth, notifier.From, []string{recipient.Email}, []byte(msg.String()))
+	if err != nil {
+		return fmt.Errorf("%w via email: %v", appError.ErrSendMessage, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Template names
+const (
+	TemplateCredential       = "credential"
+	TemplatePasswordResetOTP = "password_reset_otp"
+	TemplateVotingToken      = "voting_token"
+)
+
+// CredentialData is rendered by TemplateCredential
+type CredentialData struct {
+	FullName    string
+	NIM         string
+	Password    string
+	FrontendURL string
+}
+
+// PasswordResetOTPData is rendered by TemplatePasswordResetOTP
+type PasswordResetOTPData struct {
+	FullName         string
+	OTP              string
+	ExpiresInMinutes int
+}
+
+// VotingTokenData is rendered by TemplateVotingToken
+type VotingTokenData struct {
+	FullName  string
+	Token     string
+	LoginURL  string
+	ExpiresAt string
+}
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+var (
+	templates      map[string]*template.Template
+	templatesMutex sync.RWMutex
+)
+
+// InitTemplates loads the embedded templates. Files named <template>.tmpl in dir,
+// if dir is not empty, override the embedded ones.
+func InitTemplates(dir string) error {
+	loaded := make(map[string]*template.Template)
+
+	entries, err := defaultTemplates.ReadDir("templates")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded templates: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		tmpl, err := template.ParseFS(defaultTemplates, "templates/"+entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to parse template '%s': %w", name, err)
+		}
+		loaded[name] = tmpl
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return fmt.Errorf("failed to read template directory: %w", err)
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read template '%s': %w", file, err)
+			}
+
+			name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+			tmpl, err := template.New(name).Parse(string(content))
+			if err != nil {
+				return fmt.Errorf("failed to parse template '%s': %w", name, err)
+			}
+			loaded[name] = tmpl
+		}
+	}
+
+	templatesMutex.Lock()
+	templates = loaded
+	templatesMutex.Unlock()
+
+	return nil
+}
+
+// Render executes the "subject" and "body" blocks of the named template
+func Render(name string, data any) (Message, error) {
+	templatesMutex.RLock()
+	tmpl, ok := templates[name]
+	templatesMutex.RUnlock()
+
+	if !ok {
+		return Message{}, fmt.Errorf("template '%s' is not loaded", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject of '%s': %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render body of '%s': %w", name, err)
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()),
+	}, nil
+}
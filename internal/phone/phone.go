@@ -0,0 +1,53 @@
+package phone
+
+import "strings"
+
+// CountryCode is the only country the election accepts phone numbers from
+const CountryCode = "62"
+
+// separators people commonly type inside phone numbers
+var separators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// Normalize converts an Indonesian mobile number such as 0812-3456-7890,
+// 6281234567890 or 81234567890 to its canonical E.164 form +6281234567890.
+// When the number is not a valid Indonesian mobile number the trimmed input
+// is returned with false.
+func Normalize(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	number := separators.Replace(raw)
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+		if !strings.HasPrefix(number, "+"+CountryCode) {
+			return raw, false
+		}
+		number = strings.TrimPrefix(number, "+"+CountryCode)
+	case strings.HasPrefix(number, CountryCode):
+		number = strings.TrimPrefix(number, CountryCode)
+	}
+
+	// Trunk prefix, also when written after the country code (+62 0812...)
+	number = strings.TrimPrefix(number, "0")
+
+	if !isMobile(number) {
+		return raw, false
+	}
+
+	return "+" + CountryCode + number, true
+}
+
+// isMobile checks the national significant number of a mobile line: it
+// starts with 8 and has 9 to 12 digits
+func isMobile(number string) bool {
+	if len(number) < 9 || len(number) > 12 || number[0] != '8' {
+		return false
+	}
+
+	for _, ch := range number {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,39 @@
+package phone
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   string
+		wantOk bool
+	}{
+		{"trunk prefix", "081234567890", "+6281234567890", true},
+		{"country code", "6281234567890", "+6281234567890", true},
+		{"e164", "+6281234567890", "+6281234567890", true},
+		{"spreadsheet number without zero", "81234567890", "+6281234567890", true},
+		{"separators", "+62 812-3456-7890", "+6281234567890", true},
+		{"dots and parentheses", "(0812) 3456.7890", "+6281234567890", true},
+		{"trunk prefix after country code", "+62 0812 3456 7890", "+6281234567890", true},
+		{"surrounding spaces", "  081234567890  ", "+6281234567890", true},
+		{"shortest mobile", "0812345678", "+62812345678", true},
+		{"longest mobile", "0812345678901", "+62812345678901", true},
+		{"empty", "", "", false},
+		{"too short", "081234567", "081234567", false},
+		{"too long", "08123456789012", "08123456789012", false},
+		{"landline", "0215551234", "0215551234", false},
+		{"other country", "+6581234567", "+6581234567", false},
+		{"letters", "0812-3456-789O", "0812-3456-789O", false},
+		{"invalid input is only trimmed", " 12345 ", "12345", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Normalize(tt.raw)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Normalize(%q) = (%q, %v), want (%q, %v)", tt.raw, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type AuditLogRepository interface {
+	SaveBulk(ctx context.Context, tx *sql.Tx, auditLogs []domain.AuditLog) error
+	GetByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) ([]domain.AuditLog, error)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &AuditLogRepositoryImpl{}
+}
+
+type AuditLogRepositoryImpl struct{}
+
+func (repository *AuditLogRepositoryImpl) SaveBulk(ctx context.Context, tx *sql.Tx, auditLogs []domain.AuditLog) error {
+	if len(auditLogs) == 0 {
+		return nil
+	}
+
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString("INSERT INTO audit_logs (actor_id, action, target_type, target_id, details) VALUES ")
+
+	for i, auditLog := range auditLogs {
+		start := i*5 + 1
+
+		queryBuilder.WriteString(fmt.Sprintf("($%d, $%d, $%d, $%d, $%d::jsonb)", start, start+1, start+2, start+3, start+4))
+
+		if i < len(auditLogs)-1 {
+			queryBuilder.WriteString(", ")
+		}
+
+		details := string(auditLog.Details)
+		if details == "" {
+			details = "{}"
+		}
+
+		args = append(args, auditLog.ActorId, auditLog.Action, auditLog.TargetType, auditLog.TargetId, details)
+	}
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), args...)
+	return err
+}
+
+// GetByTarget returns the audit logs of a single record, newest first
+func (repository *AuditLogRepositoryImpl) GetByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) ([]domain.AuditLog, error) {
+	SQL := `
+	SELECT a.id, a.actor_id, a.action, a.target_type, a.target_id, a.details, a.created_at, COALESCE(u.full_name, '')
+	FROM audit_logs a
+	LEFT JOIN users u ON u.id = a.actor_id
+	WHERE a.target_type = $1 AND a.target_id = $2
+	ORDER BY a.created_at DESC, a.id DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, targetType, targetId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []domain.AuditLog
+	for rows.Next() {
+		var (
+			auditLog domain.AuditLog
+			actorId  sql.NullInt64
+			targetId sql.NullInt64
+			details  []byte
+		)
+
+		err := rows.Scan(
+			&auditLog.Id,
+			&actorId,
+			&auditLog.Action,
+			&auditLog.TargetType,
+			&targetId,
+			&details,
+			&auditLog.CreatedAt,
+			&auditLog.ActorName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		auditLog.ActorId = nullIntPtr(actorId)
+		auditLog.TargetId = nullIntPtr(targetId)
+		auditLog.Details = details
+
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs, rows.Err()
+}
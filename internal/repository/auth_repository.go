@@ -10,5 +10,8 @@ import (
 type AuthRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, session domain.Session) (domain.Session, error)
 	GetSessionById(ctx context.Context, tx *sql.Tx, sessionId string) (domain.Session, error)
+	GetSessionWithUserById(ctx context.Context, tx *sql.Tx, sessionId string) (domain.SessionWithUser, error)
 	Delete(ctx context.Context, tx *sql.Tx, sessionId string) error
+	DeleteByUserId(ctx context.Context, tx *sql.Tx, userId int, exceptSessionId string) error
+	DeleteByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error
 }
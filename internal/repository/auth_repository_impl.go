@@ -44,6 +44,76 @@ func (repository *AuthRepositoryImpl) GetSessionById(ctx context.Context, tx *sq
 	return session, nil
 }
 
+func (repository *AuthRepositoryImpl) GetSessionWithUserById(ctx context.Context, tx *sql.Tx, sessionId string) (domain.SessionWithUser, error) {
+	SQL := `
+	SELECT
+		s.session_id,
+		s.user_id,
+		s.created_at,
+		s.max_age_seconds,
+		u.id,
+		u.nim,
+		u.full_name,
+		u.study_program,
+		u.role,
+		u.phone_number,
+		u.email,
+		u.deactivated_at,
+		u.created_at,
+		u.updated_at
+	FROM
+		sessions s
+	INNER JOIN
+		users u ON s.user_id = u.id
+	WHERE
+		s.session_id = $1
+	`
+
+	var (
+		sessionWithUser domain.SessionWithUser
+		nim             sql.NullString
+		studyProgram    sql.NullString
+		email           sql.NullString
+		deactivatedAt   sql.NullTime
+	)
+
+	err := tx.QueryRowContext(ctx, SQL, sessionId).Scan(
+		&sessionWithUser.Session.SessionId,
+		&sessionWithUser.Session.UserId,
+		&sessionWithUser.Session.CreatedAt,
+		&sessionWithUser.Session.MaxAgeSeconds,
+		&sessionWithUser.User.Id,
+		&nim,
+		&sessionWithUser.User.FullName,
+		&studyProgram,
+		&sessionWithUser.User.Role,
+		&sessionWithUser.User.PhoneNumber,
+		&email,
+		&deactivatedAt,
+		&sessionWithUser.User.CreatedAt,
+		&sessionWithUser.User.UpdatedAt,
+	)
+	if err != nil {
+		return domain.SessionWithUser{}, err
+	}
+
+	// Handle null fields
+	if nim.Valid {
+		sessionWithUser.User.NIM = nim.String
+	}
+	if studyProgram.Valid {
+		sessionWithUser.User.StudyProgram = studyProgram.String
+	}
+	if email.Valid {
+		sessionWithUser.User.Email = email.String
+	}
+	if deactivatedAt.Valid {
+		sessionWithUser.User.DeactivatedAt = &deactivatedAt.Time
+	}
+
+	return sessionWithUser, nil
+}
+
 func (repository *AuthRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, sessionId string) error {
 	SQL := `
 	DELETE FROM sessions
@@ -57,3 +127,31 @@ func (repository *AuthRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, se
 
 	return nil
 }
+
+func (repository *AuthRepositoryImpl) DeleteByUserId(ctx context.Context, tx *sql.Tx, userId int, exceptSessionId string) error {
+	SQL := `
+	DELETE FROM sessions
+	WHERE user_id = $1 AND session_id <> $2
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userId, exceptSessionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *AuthRepositoryImpl) DeleteByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error {
+	SQL := `
+	DELETE FROM sessions
+	WHERE user_id = ANY($1)
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
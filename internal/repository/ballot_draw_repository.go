@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type BallotDrawRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, draw domain.BallotDraw) (domain.BallotDraw, error)
+	LockNumbers(ctx context.Context, tx *sql.Tx, period int) error
+	GetByPeriod(ctx context.Context, tx *sql.Tx, period int) ([]domain.BallotDraw, error)
+	GetActiveByPeriodForUpdate(ctx context.Context, tx *sql.Tx, period int) (domain.BallotDraw, error)
+	Reveal(ctx context.Context, tx *sql.Tx, draw domain.BallotDraw) (domain.BallotDraw, error)
+	Cancel(ctx context.Context, tx *sql.Tx, drawId, actorId int) (domain.BallotDraw, error)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewBallotDrawRepository() BallotDrawRepository {
+	return &BallotDrawRepositoryImpl{}
+}
+
+type BallotDrawRepositoryImpl struct{}
+
+const ballotDrawColumns = `id, period, status, candidate_ids, secret_seed, commitment, public_seed_source, public_seed, result, committed_by, revealed_by, cancelled_by, created_at, revealed_at, cancelled_at`
+
+func (repository *BallotDrawRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, draw domain.BallotDraw) (domain.BallotDraw, error) {
+	candidateIds, err := marshalList(draw.CandidateIds)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+
+	SQL := `
+	INSERT INTO ballot_draws (period, status, candidate_ids, secret_seed, commitment, public_seed_source, committed_by)
+	VALUES ($1, $2, $3::jsonb, $4, $5, $6, $7)
+	RETURNING ` + ballotDrawColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, draw.Period, domain.BallotDrawStatusCommitted, candidateIds, draw.SecretSeed, draw.Commitment, draw.PublicSeedSource, draw.CommittedBy)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+	defer rows.Close()
+
+	return scanBallotDraw(rows)
+}
+
+// LockNumbers holds the ballot numbers of the period until the transaction
+// ends, so numbers are handed out by one transaction at a time
+func (repository *BallotDrawRepositoryImpl) LockNumbers(ctx context.Context, tx *sql.Tx, period int) error {
+	SQL := `SELECT pg_advisory_xact_lock(hashtext('ballot_numbers'), $1)`
+
+	_, err := tx.ExecContext(ctx, SQL, period)
+	return err
+}
+
+// GetByPeriod returns every draw of the period, including cancelled ones, newest first
+func (repository *BallotDrawRepositoryImpl) GetByPeriod(ctx context.Context, tx *sql.Tx, period int) ([]domain.BallotDraw, error) {
+	SQL := `SELECT ` + ballotDrawColumns + `
+	FROM ballot_draws
+	WHERE period = $1
+	ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBallotDraws(rows)
+}
+
+// GetActiveByPeriodForUpdate locks the committed or revealed draw of the period
+func (repository *BallotDrawRepositoryImpl) GetActiveByPeriodForUpdate(ctx context.Context, tx *sql.Tx, period int) (domain.BallotDraw, error) {
+	SQL := `SELECT ` + ballotDrawColumns + `
+	FROM ballot_draws
+	WHERE period = $1 AND status <> $2
+	FOR UPDATE
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, period, domain.BallotDrawStatusCancelled)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+	defer rows.Close()
+
+	return scanBallotDraw(rows)
+}
+
+func (repository *BallotDrawRepositoryImpl) Reveal(ctx context.Context, tx *sql.Tx, draw domain.BallotDraw) (domain.BallotDraw, error) {
+	result, err := marshalList(draw.Result)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+
+	SQL := `
+	UPDATE ballot_draws
+	SET status = $1, public_seed = $2, result = $3::jsonb, revealed_by = $4, revealed_at = $5
+	WHERE id = $6
+	RETURNING ` + ballotDrawColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, domain.BallotDrawStatusRevealed, draw.PublicSeed, result, draw.RevealedBy, time.Now(), draw.Id)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+	defer rows.Close()
+
+	return scanBallotDraw(rows)
+}
+
+func (repository *BallotDrawRepositoryImpl) Cancel(ctx context.Context, tx *sql.Tx, drawId, actorId int) (domain.BallotDraw, error) {
+	SQL := `
+	UPDATE ballot_draws
+	SET status = $1, cancelled_by = $2, cancelled_at = $3
+	WHERE id = $4
+	RETURNING ` + ballotDrawColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, domain.BallotDrawStatusCancelled, actorId, time.Now(), drawId)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+	defer rows.Close()
+
+	return scanBallotDraw(rows)
+}
+
+// marshalList stores a missing list as an empty JSON array
+func marshalList[T any](list []T) (string, error) {
+	if list == nil {
+		list = []T{}
+	}
+
+	data, err := json.Marshal(list)
+	return string(data), err
+}
+
+func scanBallotDraw(rows *sql.Rows) (domain.BallotDraw, error) {
+	draws, err := scanBallotDraws(rows)
+	if err != nil {
+		return domain.BallotDraw{}, err
+	}
+	if len(draws) == 0 {
+		return domain.BallotDraw{}, sql.ErrNoRows
+	}
+
+	return draws[0], nil
+}
+
+func scanBallotDraws(rows *sql.Rows) ([]domain.BallotDraw, error) {
+	var draws []domain.BallotDraw
+
+	for rows.Next() {
+		var (
+			draw         domain.BallotDraw
+			candidateIds []byte
+			result       []byte
+			committedBy  sql.NullInt64
+			revealedBy   sql.NullInt64
+			cancelledBy  sql.NullInt64
+			revealedAt   sql.NullTime
+			cancelledAt  sql.NullTime
+		)
+
+		err := rows.Scan(
+			&draw.Id,
+			&draw.Period,
+			&draw.Status,
+			&candidateIds,
+			&draw.SecretSeed,
+			&draw.Commitment,
+			&draw.PublicSeedSource,
+			&draw.PublicSeed,
+			&result,
+			&committedBy,
+			&revealedBy,
+			&cancelledBy,
+			&draw.CreatedAt,
+			&revealedAt,
+			&cancelledAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(candidateIds, &draw.CandidateIds); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(result, &draw.Result); err != nil {
+			return nil, err
+		}
+		draw.CommittedBy = nullIntPtr(committedBy)
+		draw.RevealedBy = nullIntPtr(revealedBy)
+		draw.CancelledBy = nullIntPtr(cancelledBy)
+		if revealedAt.Valid {
+			draw.RevealedAt = &revealedAt.Time
+		}
+		if cancelledAt.Valid {
+			draw.CancelledAt = &cancelledAt.Time
+		}
+
+		draws = append(draws, draw)
+	}
+
+	return draws, rows.Err()
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	id := int(value.Int64)
+	return &id
+}
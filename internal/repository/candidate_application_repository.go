@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type CandidateApplicationRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, application domain.CandidateApplication) (domain.CandidateApplication, error)
+	GetById(ctx context.Context, tx *sql.Tx, applicationId int) (domain.CandidateApplication, error)
+	GetByIdForUpdate(ctx context.Context, tx *sql.Tx, applicationId int) (domain.CandidateApplication, error)
+	GetByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]domain.CandidateApplication, error)
+	GetAll(ctx context.Context, tx *sql.Tx, filter domain.CandidateApplicationFilter) ([]domain.CandidateApplication, error)
+	GetOpenByUserIds(ctx context.Context, tx *sql.Tx, period int, userIds []int) ([]domain.CandidateApplication, error)
+	CountByStatuses(ctx context.Context, tx *sql.Tx, period int, statuses []string) (int, error)
+	UpdateStatus(ctx context.Context, tx *sql.Tx, applicationId int, status string, candidateId *int) (domain.CandidateApplication, error)
+	SaveComment(ctx context.Context, tx *sql.Tx, comment domain.CandidateApplicationComment) (domain.CandidateApplicationComment, error)
+	GetComments(ctx context.Context, tx *sql.Tx, applicationIds []int) ([]domain.CandidateApplicationComment, error)
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewCandidateApplicationRepository() CandidateApplicationRepository {
+	return &CandidateApplicationRepositoryImpl{}
+}
+
+type CandidateApplicationRepositoryImpl struct{}
+
+const candidateApplicationColumns = `a.id, a.period, a.president_id, a.vice_id, a.vision, a.mission, a.status, a.submitted_by, a.candidate_id, a.created_at, a.updated_at,
+	COALESCE(p.nim, ''), p.full_name, p.study_program, COALESCE(v.nim, ''), v.full_name, v.study_program`
+
+const candidateApplicationJoins = `
+	INNER JOIN users p ON p.id = a.president_id
+	INNER JOIN users v ON v.id = a.vice_id`
+
+func (repository *CandidateApplicationRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, application domain.CandidateApplication) (domain.CandidateApplication, error) {
+	mission, err := marshalMission(application.Mission)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+
+	SQL := `
+	WITH a AS (
+		INSERT INTO candidate_applications (period, president_id, vice_id, vision, mission, status, submitted_by)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7)
+		RETURNING *
+	)
+	SELECT ` + candidateApplicationColumns + `
+	FROM a` + candidateApplicationJoins
+
+	rows, err := tx.QueryContext(ctx, SQL, application.Period, application.PresidentId, application.ViceId, application.Vision, mission, domain.CandidateApplicationStatusSubmitted, application.SubmittedBy)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplication(rows)
+}
+
+func (repository *CandidateApplicationRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, applicationId int) (domain.CandidateApplication, error) {
+	SQL := `SELECT ` + candidateApplicationColumns + `
+	FROM candidate_applications a` + candidateApplicationJoins + `
+	WHERE a.id = $1
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, applicationId)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplication(rows)
+}
+
+func (repository *CandidateApplicationRepositoryImpl) GetByIdForUpdate(ctx context.Context, tx *sql.Tx, applicationId int) (domain.CandidateApplication, error) {
+	SQL := `SELECT ` + candidateApplicationColumns + `
+	FROM candidate_applications a` + candidateApplicationJoins + `
+	WHERE a.id = $1
+	FOR UPDATE OF a
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, applicationId)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplication(rows)
+}
+
+// GetByUserId returns the applications the user is the president or vice of, newest first
+func (repository *CandidateApplicationRepositoryImpl) GetByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]domain.CandidateApplication, error) {
+	SQL := `SELECT ` + candidateApplicationColumns + `
+	FROM candidate_applications a` + candidateApplicationJoins + `
+	WHERE a.president_id = $1 OR a.vice_id = $1
+	ORDER BY a.created_at DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplications(rows)
+}
+
+// GetAll returns the oldest applications first so they are reviewed in order
+func (repository *CandidateApplicationRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx, filter domain.CandidateApplicationFilter) ([]domain.CandidateApplication, error) {
+	SQL := `SELECT ` + candidateApplicationColumns + `
+	FROM candidate_applications a` + candidateApplicationJoins + `
+	WHERE ($1 = 0 OR a.period = $1) AND ($2 = '' OR a.status = $2)
+	ORDER BY a.created_at
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, filter.Period, filter.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplications(rows)
+}
+
+// GetOpenByUserIds returns the applications of the period that are not
+// rejected and have one of the users as president or vice
+func (repository *CandidateApplicationRepositoryImpl) GetOpenByUserIds(ctx context.Context, tx *sql.Tx, period int, userIds []int) ([]domain.CandidateApplication, error) {
+	SQL := `SELECT ` + candidateApplicationColumns + `
+	FROM candidate_applications a` + candidateApplicationJoins + `
+	WHERE a.period = $1 AND a.status <> $2 AND (a.president_id = ANY($3) OR a.vice_id = ANY($3))
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, period, domain.CandidateApplicationStatusRejected, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplications(rows)
+}
+
+func (repository *CandidateApplicationRepositoryImpl) CountByStatuses(ctx context.Context, tx *sql.Tx, period int, statuses []string) (int, error) {
+	SQL := `
+	SELECT COUNT(*)
+	FROM candidate_applications
+	WHERE period = $1 AND status = ANY($2)
+	`
+
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, period, statuses).Scan(&count)
+	return count, err
+}
+
+func (repository *CandidateApplicationRepositoryImpl) UpdateStatus(ctx context.Context, tx *sql.Tx, applicationId int, status string, candidateId *int) (domain.CandidateApplication, error) {
+	SQL := `
+	WITH a AS (
+		UPDATE candidate_applications
+		SET status = $1, candidate_id = COALESCE($2, candidate_id), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+		RETURNING *
+	)
+	SELECT ` + candidateApplicationColumns + `
+	FROM a` + candidateApplicationJoins
+
+	rows, err := tx.QueryContext(ctx, SQL, status, candidateId, applicationId)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplication(rows)
+}
+
+func (repository *CandidateApplicationRepositoryImpl) SaveComment(ctx context.Context, tx *sql.Tx, comment domain.CandidateApplicationComment) (domain.CandidateApplicationComment, error) {
+	SQL := `
+	WITH c AS (
+		INSERT INTO candidate_application_comments (application_id, author_id, status, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+	)
+	SELECT c.id, c.application_id, c.author_id, c.status, c.body, c.created_at, COALESCE(u.full_name, '')
+	FROM c
+	LEFT JOIN users u ON u.id = c.author_id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, comment.ApplicationId, comment.AuthorId, comment.Status, comment.Body)
+	if err != nil {
+		return domain.CandidateApplicationComment{}, err
+	}
+	defer rows.Close()
+
+	comments, err := scanCandidateApplicationComments(rows)
+	if err != nil {
+		return domain.CandidateApplicationComment{}, err
+	}
+	if len(comments) == 0 {
+		return domain.CandidateApplicationComment{}, sql.ErrNoRows
+	}
+
+	return comments[0], nil
+}
+
+// GetComments returns the comments of several applications at once, oldest first
+func (repository *CandidateApplicationRepositoryImpl) GetComments(ctx context.Context, tx *sql.Tx, applicationIds []int) ([]domain.CandidateApplicationComment, error) {
+	SQL := `
+	SELECT c.id, c.application_id, c.author_id, c.status, c.body, c.created_at, COALESCE(u.full_name, '')
+	FROM candidate_application_comments c
+	LEFT JOIN users u ON u.id = c.author_id
+	WHERE c.application_id = ANY($1)
+	ORDER BY c.created_at, c.id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, applicationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateApplicationComments(rows)
+}
+
+func scanCandidateApplication(rows *sql.Rows) (domain.CandidateApplication, error) {
+	applications, err := scanCandidateApplications(rows)
+	if err != nil {
+		return domain.CandidateApplication{}, err
+	}
+	if len(applications) == 0 {
+		return domain.CandidateApplication{}, sql.ErrNoRows
+	}
+
+	return applications[0], nil
+}
+
+func scanCandidateApplications(rows *sql.Rows) ([]domain.CandidateApplication, error) {
+	var applications []domain.CandidateApplication
+
+	for rows.Next() {
+		var (
+			application domain.CandidateApplication
+			mission     []byte
+			submittedBy sql.NullInt64
+			candidateId sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&application.Id,
+			&application.Period,
+			&application.PresidentId,
+			&application.ViceId,
+			&application.Vision,
+			&mission,
+			&application.Status,
+			&submittedBy,
+			&candidateId,
+			&application.CreatedAt,
+			&application.UpdatedAt,
+			&application.PresidentNIM,
+			&application.President,
+			&application.PresidentStudyProgram,
+			&application.ViceNIM,
+			&application.Vice,
+			&application.ViceStudyProgram,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(mission, &application.Mission); err != nil {
+			return nil, err
+		}
+		if submittedBy.Valid {
+			id := int(submittedBy.Int64)
+			application.SubmittedBy = &id
+		}
+		if candidateId.Valid {
+			id := int(candidateId.Int64)
+			application.CandidateId = &id
+		}
+
+		applications = append(applications, application)
+	}
+
+	return applications, rows.Err()
+}
+
+func scanCandidateApplicationComments(rows *sql.Rows) ([]domain.CandidateApplicationComment, error) {
+	var comments []domain.CandidateApplicationComment
+
+	for rows.Next() {
+		var (
+			comment  domain.CandidateApplicationComment
+			authorId sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&comment.Id,
+			&comment.ApplicationId,
+			&authorId,
+			&comment.Status,
+			&comment.Body,
+			&comment.CreatedAt,
+			&comment.AuthorName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if authorId.Valid {
+			id := int(authorId.Int64)
+			comment.AuthorId = &id
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
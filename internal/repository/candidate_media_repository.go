@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type CandidateMediaRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, media domain.CandidateMedia) (domain.CandidateMedia, error)
+	GetById(ctx context.Context, tx *sql.Tx, candidateId, mediaId int) (domain.CandidateMedia, error)
+	GetByCandidateId(ctx context.Context, tx *sql.Tx, candidateId int) ([]domain.CandidateMedia, error)
+	GetByCandidateIds(ctx context.Context, tx *sql.Tx, candidateIds []int) ([]domain.CandidateMedia, error)
+	Update(ctx context.Context, tx *sql.Tx, media domain.CandidateMedia) (domain.CandidateMedia, error)
+	DeleteById(ctx context.Context, tx *sql.Tx, mediaId int) error
+	UpdatePositions(ctx context.Context, tx *sql.Tx, candidateId int, mediaIds []int) error
+	GetObjectKeys(ctx context.Context, tx *sql.Tx) ([]string, error)
+}
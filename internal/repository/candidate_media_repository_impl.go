@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewCandidateMediaRepository() CandidateMediaRepository {
+	return &CandidateMediaRepositoryImpl{}
+}
+
+type CandidateMediaRepositoryImpl struct{}
+
+const candidateMediaColumns = `id, candidate_id, type, title, object_key, url, position, created_at, updated_at`
+
+// Save appends the media after the candidate's other media
+func (repository *CandidateMediaRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, media domain.CandidateMedia) (domain.CandidateMedia, error) {
+	SQL := `
+	INSERT INTO candidate_media (candidate_id, type, title, object_key, url, position)
+	VALUES ($1, $2, $3, $4, $5, (SELECT COALESCE(MAX(position), 0) + 1 FROM candidate_media WHERE candidate_id = $1))
+	RETURNING ` + candidateMediaColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, media.CandidateId, media.Type, media.Title, media.ObjectKey, media.URL)
+	if err != nil {
+		return domain.CandidateMedia{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateMedia(rows)
+}
+
+func (repository *CandidateMediaRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, candidateId, mediaId int) (domain.CandidateMedia, error) {
+	SQL := `SELECT ` + candidateMediaColumns + `
+	FROM candidate_media
+	WHERE candidate_id = $1 AND id = $2
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, candidateId, mediaId)
+	if err != nil {
+		return domain.CandidateMedia{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateMedia(rows)
+}
+
+func (repository *CandidateMediaRepositoryImpl) GetByCandidateId(ctx context.Context, tx *sql.Tx, candidateId int) ([]domain.CandidateMedia, error) {
+	return repository.GetByCandidateIds(ctx, tx, []int{candidateId})
+}
+
+// GetByCandidateIds returns the media of several candidates at once, in display order
+func (repository *CandidateMediaRepositoryImpl) GetByCandidateIds(ctx context.Context, tx *sql.Tx, candidateIds []int) ([]domain.CandidateMedia, error) {
+	SQL := `SELECT ` + candidateMediaColumns + `
+	FROM candidate_media
+	WHERE candidate_id = ANY($1)
+	ORDER BY candidate_id, position, id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, candidateIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateMediaList(rows)
+}
+
+func (repository *CandidateMediaRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, media domain.CandidateMedia) (domain.CandidateMedia, error) {
+	SQL := `
+	UPDATE candidate_media
+	SET title = $1, url = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3
+	RETURNING ` + candidateMediaColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, media.Title, media.URL, media.Id)
+	if err != nil {
+		return domain.CandidateMedia{}, err
+	}
+	defer rows.Close()
+
+	return scanCandidateMedia(rows)
+}
+
+func (repository *CandidateMediaRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx, mediaId int) error {
+	SQL := `
+	DELETE FROM candidate_media
+	WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, mediaId)
+	return err
+}
+
+// UpdatePositions sets the position of each media to its index in mediaIds, starting at 1
+func (repository *CandidateMediaRepositoryImpl) UpdatePositions(ctx context.Context, tx *sql.Tx, candidateId int, mediaIds []int) error {
+	SQL := `
+	UPDATE candidate_media m
+	SET position = o.position, updated_at = CURRENT_TIMESTAMP
+	FROM unnest($1::int[]) WITH ORDINALITY AS o(id, position)
+	WHERE m.id = o.id AND m.candidate_id = $2
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, mediaIds, candidateId)
+	return err
+}
+
+// GetObjectKeys returns the object keys of every file media
+func (repository *CandidateMediaRepositoryImpl) GetObjectKeys(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	SQL := `
+	SELECT object_key
+	FROM candidate_media
+	WHERE object_key <> ''
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func scanCandidateMedia(rows *sql.Rows) (domain.CandidateMedia, error) {
+	media, err := scanCandidateMediaList(rows)
+	if err != nil {
+		return domain.CandidateMedia{}, err
+	}
+	if len(media) == 0 {
+		return domain.CandidateMedia{}, sql.ErrNoRows
+	}
+
+	return media[0], nil
+}
+
+func scanCandidateMediaList(rows *sql.Rows) ([]domain.CandidateMedia, error) {
+	var mediaList []domain.CandidateMedia
+
+	for rows.Next() {
+		var media domain.CandidateMedia
+
+		err := rows.Scan(
+			&media.Id,
+			&media.CandidateId,
+			&media.Type,
+			&media.Title,
+			&media.ObjectKey,
+			&media.URL,
+			&media.Position,
+			&media.CreatedAt,
+			&media.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		mediaList = append(mediaList, media)
+	}
+
+	return mediaList, rows.Err()
+}
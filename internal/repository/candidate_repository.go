@@ -12,6 +12,9 @@ type CandidateRepository interface {
 	GetAll(ctx context.Context, tx *sql.Tx) ([]domain.Candidate, error)
 	GetByPeriod(ctx context.Context, tx *sql.Tx, period int) ([]domain.Candidate, error)
 	GetById(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error)
+	GetByIdForUpdate(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error)
 	UpdateById(ctx context.Context, tx *sql.Tx, candidateId int, candidate domain.Candidate) (domain.Candidate, error)
 	DeleteById(ctx context.Context, tx *sql.Tx, candidateId int) error
+	UpdateNumbers(ctx context.Context, tx *sql.Tx, numbers []domain.BallotDrawNumber) error
+	IsObjectKeyUsed(ctx context.Context, tx *sql.Tx, keys []string) (bool, error)
 }
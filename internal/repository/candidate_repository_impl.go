@@ -3,17 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 )
 
-// Variables to handle null values
-var (
-	vision  sql.NullString
-	mission sql.NullString
-)
-
 func NewCandidateRepository() CandidateRepository {
 	return &CandidateRepositoryImpl{}
 }
@@ -21,20 +16,25 @@ func NewCandidateRepository() CandidateRepository {
 type CandidateRepositoryImpl struct{}
 
 func (repository *CandidateRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, candidate domain.Candidate) (domain.Candidate, error) {
+	mission, err := marshalMission(candidate.Mission)
+	if err != nil {
+		return domain.Candidate{}, err
+	}
+
 	SQL := `
 	INSERT INTO candidates (number, president, vice, vision, mission, photo_key, president_study_program, vice_study_program, president_nim, vice_nim)
-	VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10)
+	VALUES ($1, $2, $3, NULLIF($4, ''), $5::jsonb, $6, $7, $8, $9, $10)
 	RETURNING id, created_at, updated_at
 	`
 
-	err := tx.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		SQL,
 		candidate.Number,
 		candidate.President,
 		candidate.Vice,
 		candidate.Vision,
-		candidate.Mission,
+		mission,
 		candidate.PhotoKey,
 		candidate.PresidentStudyProgram,
 		candidate.ViceStudyProgram,
@@ -66,7 +66,11 @@ func (repository *CandidateRepositoryImpl) GetAll(ctx context.Context, tx *sql.T
 
 	var candidates []domain.Candidate
 	for rows.Next() {
-		var candidate domain.Candidate
+		var (
+			candidate domain.Candidate
+			vision    sql.NullString
+			mission   []byte
+		)
 
 		err := rows.Scan(
 			&candidate.Id,
@@ -88,14 +92,15 @@ func (repository *CandidateRepositoryImpl) GetAll(ctx context.Context, tx *sql.T
 		if vision.Valid {
 			candidate.Vision = vision.String
 		}
-		if mission.Valid {
-			candidate.Mission = mission.String
-		}
 
 		if err != nil {
 			return []domain.Candidate{}, err
 		}
 
+		if err := json.Unmarshal(mission, &candidate.Mission); err != nil {
+			return []domain.Candidate{}, err
+		}
+
 		candidates = append(candidates, candidate)
 	}
 
@@ -117,7 +122,11 @@ func (repository *CandidateRepositoryImpl) GetByPeriod(ctx context.Context, tx *
 
 	var candidates []domain.Candidate
 	for rows.Next() {
-		var candidate domain.Candidate
+		var (
+			candidate domain.Candidate
+			vision    sql.NullString
+			mission   []byte
+		)
 
 		err := rows.Scan(
 			&candidate.Id,
@@ -139,14 +148,15 @@ func (repository *CandidateRepositoryImpl) GetByPeriod(ctx context.Context, tx *
 		if vision.Valid {
 			candidate.Vision = vision.String
 		}
-		if mission.Valid {
-			candidate.Mission = mission.String
-		}
 
 		if err != nil {
 			return []domain.Candidate{}, err
 		}
 
+		if err := json.Unmarshal(mission, &candidate.Mission); err != nil {
+			return []domain.Candidate{}, err
+		}
+
 		candidates = append(candidates, candidate)
 	}
 
@@ -154,13 +164,27 @@ func (repository *CandidateRepositoryImpl) GetByPeriod(ctx context.Context, tx *
 }
 
 func (repository *CandidateRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error) {
+	return repository.getById(ctx, tx, candidateId, "")
+}
+
+// GetByIdForUpdate locks the candidate so concurrent updates are applied one
+// after another instead of overwriting each other
+func (repository *CandidateRepositoryImpl) GetByIdForUpdate(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error) {
+	return repository.getById(ctx, tx, candidateId, "FOR UPDATE")
+}
+
+func (repository *CandidateRepositoryImpl) getById(ctx context.Context, tx *sql.Tx, candidateId int, lock string) (domain.Candidate, error) {
 	SQL := `
 	SELECT id, number, president, vice, vision, mission, photo_key, president_study_program, vice_study_program, president_nim, vice_nim, created_at, updated_at
 	FROM candidates
 	WHERE id = $1
-	`
+	` + lock
 
-	var candidate domain.Candidate
+	var (
+		candidate domain.Candidate
+		vision    sql.NullString
+		mission   []byte
+	)
 	err := tx.QueryRowContext(ctx, SQL, candidateId).Scan(
 		&candidate.Id,
 		&candidate.Number,
@@ -181,34 +205,40 @@ func (repository *CandidateRepositoryImpl) GetById(ctx context.Context, tx *sql.
 	if vision.Valid {
 		candidate.Vision = vision.String
 	}
-	if mission.Valid {
-		candidate.Mission = mission.String
-	}
 
 	if err != nil {
 		return domain.Candidate{}, err
 	}
 
+	if err := json.Unmarshal(mission, &candidate.Mission); err != nil {
+		return domain.Candidate{}, err
+	}
+
 	return candidate, nil
 }
 
 func (repository *CandidateRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx, candidateId int, candidate domain.Candidate) (domain.Candidate, error) {
 	SQL := `
 	UPDATE candidates
-	SET number = $1, president = $2, vice = NULLIF($3, ''), vision = NULLIF($4, ''), mission = $5, photo_key = $6, president_study_program = $7, vice_study_program = $8, president_nim = $9, vice_nim = $10, updated_at = $11
+	SET number = $1, president = $2, vice = NULLIF($3, ''), vision = NULLIF($4, ''), mission = $5::jsonb, photo_key = $6, president_study_program = $7, vice_study_program = $8, president_nim = $9, vice_nim = $10, updated_at = $11
 	WHERE id = $12
 	`
 
+	mission, err := marshalMission(candidate.Mission)
+	if err != nil {
+		return domain.Candidate{}, err
+	}
+
 	updatedAt := time.Now()
 
-	_, err := tx.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		SQL,
 		candidate.Number,
 		candidate.President,
 		candidate.Vice,
 		candidate.Vision,
-		candidate.Mission,
+		mission,
 		candidate.PhotoKey,
 		candidate.PresidentStudyProgram,
 		candidate.ViceStudyProgram,
@@ -248,3 +278,53 @@ func (repository *CandidateRepositoryImpl) DeleteById(ctx context.Context, tx *s
 	_, err := tx.ExecContext(ctx, SQL, candidateId)
 	return err
 }
+
+// UpdateNumbers assigns the drawn ballot numbers in a single statement
+func (repository *CandidateRepositoryImpl) UpdateNumbers(ctx context.Context, tx *sql.Tx, numbers []domain.BallotDrawNumber) error {
+	candidateIds := make([]int, 0, len(numbers))
+	values := make([]int, 0, len(numbers))
+	for _, number := range numbers {
+		candidateIds = append(candidateIds, number.CandidateId)
+		values = append(values, number.Number)
+	}
+
+	SQL := `
+	UPDATE candidates c
+	SET number = n.number, updated_at = $3
+	FROM UNNEST($1::int[], $2::int[]) AS n(id, number)
+	WHERE c.id = n.id
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, candidateIds, values, time.Now())
+	return err
+}
+
+// IsObjectKeyUsed reports whether any of the keys is a candidate photo or
+// the file of a candidate media
+func (repository *CandidateRepositoryImpl) IsObjectKeyUsed(ctx context.Context, tx *sql.Tx, keys []string) (bool, error) {
+	SQL := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM candidates
+		WHERE photo_key = ANY($1)
+	) OR EXISTS (
+		SELECT 1
+		FROM candidate_media
+		WHERE object_key = ANY($1)
+	)
+	`
+
+	var used bool
+	err := tx.QueryRowContext(ctx, SQL, keys).Scan(&used)
+	return used, err
+}
+
+// marshalMission stores a missing mission as an empty list
+func marshalMission(mission []string) (string, error) {
+	if mission == nil {
+		mission = []string{}
+	}
+
+	data, err := json.Marshal(mission)
+	return string(data), err
+}
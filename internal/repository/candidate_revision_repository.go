@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type CandidateRevisionRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, revision domain.CandidateRevision) (domain.CandidateRevision, error)
+	GetByCandidateId(ctx context.Context, tx *sql.Tx, candidateId int) ([]domain.CandidateRevision, error)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewCandidateRevisionRepository() CandidateRevisionRepository {
+	return &CandidateRevisionRepositoryImpl{}
+}
+
+type CandidateRevisionRepositoryImpl struct{}
+
+// Save numbers the revision after the latest revision of the candidate. The
+// caller must hold the lock on the candidate row so revisions of a candidate
+// are saved one at a time.
+func (repository *CandidateRevisionRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, revision domain.CandidateRevision) (domain.CandidateRevision, error) {
+	changes := revision.Changes
+	if changes == nil {
+		changes = map[string]domain.CandidateFieldChange{}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return domain.CandidateRevision{}, err
+	}
+
+	SQL := `
+	WITH r AS (
+		INSERT INTO candidate_revisions (candidate_id, revision, changes, reason, actor_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2::jsonb, $3, $4
+		FROM candidate_revisions
+		WHERE candidate_id = $1
+		RETURNING *
+	)
+	SELECT r.id, r.candidate_id, r.revision, r.changes, r.reason, r.actor_id, r.created_at, COALESCE(u.full_name, '')
+	FROM r
+	LEFT JOIN users u ON u.id = r.actor_id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, revision.CandidateId, string(data), revision.Reason, revision.ActorId)
+	if err != nil {
+		return domain.CandidateRevision{}, err
+	}
+	defer rows.Close()
+
+	revisions, err := scanCandidateRevisions(rows)
+	if err != nil {
+		return domain.CandidateRevision{}, err
+	}
+	if len(revisions) == 0 {
+		return domain.CandidateRevision{}, sql.ErrNoRows
+	}
+
+	return revisions[0], nil
+}
+
+// GetByCandidateId returns the revisions of the candidate, newest first
+func (repository *CandidateRevisionRepositoryImpl) GetByCandidateId(ctx context.Context, tx *sql.Tx, candidateId int) ([]domain.CandidateRevision, error) {
+	SQL := `
+	SELECT r.id, r.candidate_id, r.revision, r.changes, r.reason, r.actor_id, r.created_at, COALESCE(u.full_name, '')
+	FROM candidate_revisions r
+	LEFT JOIN users u ON u.id = r.actor_id
+	WHERE r.candidate_id = $1
+	ORDER BY r.revision DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, candidateId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCandidateRevisions(rows)
+}
+
+func scanCandidateRevisions(rows *sql.Rows) ([]domain.CandidateRevision, error) {
+	var revisions []domain.CandidateRevision
+
+	for rows.Next() {
+		var (
+			revision domain.CandidateRevision
+			changes  []byte
+			actorId  sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&revision.Id,
+			&revision.CandidateId,
+			&revision.Revision,
+			&changes,
+			&revision.Reason,
+			&actorId,
+			&revision.CreatedAt,
+			&revision.ActorName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(changes, &revision.Changes); err != nil {
+			return nil, err
+		}
+		revision.ActorId = nullIntPtr(actorId)
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
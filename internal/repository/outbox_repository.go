@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type OutboxRepository interface {
+	SaveBulk(ctx context.Context, tx *sql.Tx, messages []domain.OutboxMessage) error
+	ClaimDue(ctx context.Context, tx *sql.Tx, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkSent(ctx context.Context, tx *sql.Tx, messageId int) error
+	MarkRetry(ctx context.Context, tx *sql.Tx, messageId int, lastError string, delay time.Duration) error
+	MarkFailed(ctx context.Context, tx *sql.Tx, messageId int, lastError string) error
+	CancelByUserIds(ctx context.Context, tx *sql.Tx, userIds []int, template string) error
+	GetFailed(ctx context.Context, tx *sql.Tx) ([]domain.OutboxMessage, error)
+	GetFailedById(ctx context.Context, tx *sql.Tx, messageId int) (domain.OutboxMessage, error)
+	GetLatestByUserId(ctx context.Context, tx *sql.Tx, userId int, template string) (domain.OutboxMessage, error)
+}
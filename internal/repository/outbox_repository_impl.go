@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewOutboxRepository() OutboxRepository {
+	return &OutboxRepositoryImpl{}
+}
+
+type OutboxRepositoryImpl struct{}
+
+const outboxColumns = `id, user_id, template, recipient_name, COALESCE(phone_number, ''), COALESCE(email, ''), subject, body, status, attempts, COALESCE(last_error, ''), next_attempt_at, sent_at, created_at, updated_at`
+
+func (repository *OutboxRepositoryImpl) SaveBulk(ctx context.Context, tx *sql.Tx, messages []domain.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString("INSERT INTO message_outbox (user_id, template, recipient_name, phone_number, email, subject, body) VALUES ")
+
+	for i, message := range messages {
+		start := i*7 + 1
+
+		queryBuilder.WriteString(fmt.Sprintf("($%d, $%d, $%d, NULLIF($%d, ''), NULLIF($%d, ''), $%d, $%d)", start, start+1, start+2, start+3, start+4, start+5, start+6))
+
+		if i < len(messages)-1 {
+			queryBuilder.WriteString(", ")
+		}
+
+		args = append(args, message.UserId, message.Template, message.RecipientName, message.PhoneNumber, message.Email, message.Subject, message.Body)
+	}
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), args...)
+	return err
+}
+
+// ClaimDue locks due messages for the duration of the lease so that concurrent
+// workers skip them. Messages left in processing past their lease are reclaimed.
+func (repository *OutboxRepositoryImpl) ClaimDue(ctx context.Context, tx *sql.Tx, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	SQL := `
+	UPDATE message_outbox
+	SET status = $1, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), updated_at = CURRENT_TIMESTAMP
+	WHERE id IN (
+		SELECT id FROM message_outbox
+		WHERE status IN ($3, $1) AND next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY id
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + outboxColumns
+
+	rows, err := tx.QueryContext(ctx, SQL, domain.OutboxStatusProcessing, lease.Seconds(), domain.OutboxStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOutboxMessages(rows)
+}
+
+func (repository *OutboxRepositoryImpl) MarkSent(ctx context.Context, tx *sql.Tx, messageId int) error {
+	// The body may contain a password or OTP, so it is not kept after delivery
+	SQL := `
+	UPDATE message_outbox
+	SET status = $1, body = '', attempts = attempts + 1, last_error = NULL, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $2 AND status = $3
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.OutboxStatusSent, messageId, domain.OutboxStatusProcessing)
+	return err
+}
+
+func (repository *OutboxRepositoryImpl) MarkRetry(ctx context.Context, tx *sql.Tx, messageId int, lastError string, delay time.Duration) error {
+	SQL := `
+	UPDATE message_outbox
+	SET status = $1, attempts = attempts + 1, last_error = $2, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3), updated_at = CURRENT_TIMESTAMP
+	WHERE id = $4 AND status = $5
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.OutboxStatusPending, lastError, delay.Seconds(), messageId, domain.OutboxStatusProcessing)
+	return err
+}
+
+func (repository *OutboxRepositoryImpl) MarkFailed(ctx context.Context, tx *sql.Tx, messageId int, lastError string) error {
+	// The secret in the body is not kept either, a resend issues a new one
+	SQL := `
+	UPDATE message_outbox
+	SET status = $1, body = '', attempts = attempts + 1, last_error = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3 AND status = $4
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.OutboxStatusFailed, lastError, messageId, domain.OutboxStatusProcessing)
+	return err
+}
+
+// CancelByUserIds stops the undelivered messages of a template for the users,
+// e.g. credentials superseded by a new password. Their body is cleared too.
+// Messages a worker is sending are cancelled as well; the Mark* updates only
+// apply to processing messages, so the worker can't revive them.
+func (repository *OutboxRepositoryImpl) CancelByUserIds(ctx context.Context, tx *sql.Tx, userIds []int, template string) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	SQL := `
+	UPDATE message_outbox
+	SET status = $1, body = '', updated_at = CURRENT_TIMESTAMP
+	WHERE user_id = ANY($2) AND template = $3 AND status IN ($4, $5, $6)
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.OutboxStatusCancelled, userIds, template, domain.OutboxStatusPending, domain.OutboxStatusProcessing, domain.OutboxStatusFailed)
+	return err
+}
+
+func (repository *OutboxRepositoryImpl) GetFailed(ctx context.Context, tx *sql.Tx) ([]domain.OutboxMessage, error) {
+	SQL := `SELECT ` + outboxColumns + `
+	FROM message_outbox
+	WHERE status = $1
+	ORDER BY updated_at DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, domain.OutboxStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOutboxMessages(rows)
+}
+
+func (repository *OutboxRepositoryImpl) GetFailedById(ctx context.Context, tx *sql.Tx, messageId int) (domain.OutboxMessage, error) {
+	SQL := `SELECT ` + outboxColumns + `
+	FROM message_outbox
+	WHERE id = $1 AND status = $2
+	`
+
+	return queryOutboxMessage(ctx, tx, SQL, messageId, domain.OutboxStatusFailed)
+}
+
+func (repository *OutboxRepositoryImpl) GetLatestByUserId(ctx context.Context, tx *sql.Tx, userId int, template string) (domain.OutboxMessage, error) {
+	SQL := `SELECT ` + outboxColumns + `
+	FROM message_outbox
+	WHERE user_id = $1 AND template = $2
+	ORDER BY id DESC
+	LIMIT 1
+	`
+
+	return queryOutboxMessage(ctx, tx, SQL, userId, template)
+}
+
+func queryOutboxMessage(ctx context.Context, tx *sql.Tx, SQL string, args ...interface{}) (domain.OutboxMessage, error) {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	defer rows.Close()
+
+	messages, err := scanOutboxMessages(rows)
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	if len(messages) == 0 {
+		return domain.OutboxMessage{}, sql.ErrNoRows
+	}
+
+	return messages[0], nil
+}
+
+func scanOutboxMessages(rows *sql.Rows) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	for rows.Next() {
+		var (
+			message domain.OutboxMessage
+			userId  sql.NullInt64
+			sentAt  sql.NullTime
+		)
+
+		err := rows.Scan(
+			&message.Id,
+			&userId,
+			&message.Template,
+			&message.RecipientName,
+			&message.PhoneNumber,
+			&message.Email,
+			&message.Subject,
+			&message.Body,
+			&message.Status,
+			&message.Attempts,
+			&message.LastError,
+			&message.NextAttemptAt,
+			&sentAt,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if userId.Valid {
+			id := int(userId.Int64)
+			message.UserId = &id
+		}
+		if sentAt.Valid {
+			message.SentAt = &sentAt.Time
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
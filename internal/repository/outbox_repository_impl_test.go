@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+// testTx opens a transaction on the migrated database in TEST_DATABASE_URL
+// that is rolled back when the test ends
+func testTx(t *testing.T) *sql.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("begin transaction: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+
+	return tx
+}
+
+// TestOutboxCancelWhileProcessing replays a regeneration that cancels a
+// message while the worker is sending it. Whatever the send result, the
+// cancelled message must not come back.
+func TestOutboxCancelWhileProcessing(t *testing.T) {
+	const template = "credential"
+
+	tests := []struct {
+		name   string
+		record func(ctx context.Context, repository OutboxRepository, tx *sql.Tx, messageId int) error
+	}{
+		{"sent", func(ctx context.Context, repository OutboxRepository, tx *sql.Tx, messageId int) error {
+			return repository.MarkSent(ctx, tx, messageId)
+		}},
+		{"retry", func(ctx context.Context, repository OutboxRepository, tx *sql.Tx, messageId int) error {
+			return repository.MarkRetry(ctx, tx, messageId, "timeout", time.Second)
+		}},
+		{"failed", func(ctx context.Context, repository OutboxRepository, tx *sql.Tx, messageId int) error {
+			return repository.MarkFailed(ctx, tx, messageId, "timeout")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tx := testTx(t)
+			repository := NewOutboxRepository()
+
+			// Shadow the table so only this message can be claimed
+			_, err := tx.ExecContext(ctx, `CREATE TEMP TABLE message_outbox (LIKE public.message_outbox INCLUDING DEFAULTS) ON COMMIT DROP`)
+			if err != nil {
+				t.Fatalf("create temp table: %v", err)
+			}
+
+			userId := 1
+			err = repository.SaveBulk(ctx, tx, []domain.OutboxMessage{{
+				UserId:        &userId,
+				Template:      template,
+				RecipientName: "Student",
+				PhoneNumber:   "+6281234567890",
+				Body:          "old password",
+			}})
+			if err != nil {
+				t.Fatalf("SaveBulk() error = %v", err)
+			}
+
+			claimed, err := repository.ClaimDue(ctx, tx, 10, time.Minute)
+			if err != nil || len(claimed) != 1 {
+				t.Fatalf("ClaimDue() = %v, %v, want one message", claimed, err)
+			}
+
+			if err = repository.CancelByUserIds(ctx, tx, []int{userId}, template); err != nil {
+				t.Fatalf("CancelByUserIds() error = %v", err)
+			}
+
+			if err = tt.record(ctx, repository, tx, claimed[0].Id); err != nil {
+				t.Fatalf("recording the send result: %v", err)
+			}
+
+			var status, body string
+			err = tx.QueryRowContext(ctx, `SELECT status, body FROM message_outbox WHERE id = $1`, claimed[0].Id).Scan(&status, &body)
+			if err != nil {
+				t.Fatalf("read message: %v", err)
+			}
+			if status != domain.OutboxStatusCancelled || body != "" {
+				t.Errorf("message is %s with body %q, want cancelled with an empty body", status, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, otp domain.PasswordResetOTP) (domain.PasswordResetOTP, error)
+	GetLatestUnusedByUserIdForUpdate(ctx context.Context, tx *sql.Tx, userId int) (domain.PasswordResetOTP, error)
+	IncrementAttempts(ctx context.Context, tx *sql.Tx, otpId int) error
+	MarkUsed(ctx context.Context, tx *sql.Tx, otpId int) error
+	InvalidateByUserId(ctx context.Context, tx *sql.Tx, userId int) error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewPasswordResetRepository() PasswordResetRepository {
+	return &PasswordResetRepositoryImpl{}
+}
+
+type PasswordResetRepositoryImpl struct{}
+
+func (repository *PasswordResetRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, otp domain.PasswordResetOTP) (domain.PasswordResetOTP, error) {
+	SQL := `
+	INSERT INTO password_reset_otps (user_id, hashed_otp, expires_at)
+	VALUES ($1, $2, $3)
+	RETURNING id, attempts, created_at
+	`
+
+	err := tx.QueryRowContext(ctx, SQL, otp.UserId, otp.HashedOTP, otp.ExpiresAt).Scan(
+		&otp.Id,
+		&otp.Attempts,
+		&otp.CreatedAt,
+	)
+	if err != nil {
+		return domain.PasswordResetOTP{}, err
+	}
+
+	return otp, nil
+}
+
+// GetLatestUnusedByUserIdForUpdate locks the OTP so parallel attempts are
+// counted one after another and can't get past the attempt limit
+func (repository *PasswordResetRepositoryImpl) GetLatestUnusedByUserIdForUpdate(ctx context.Context, tx *sql.Tx, userId int) (domain.PasswordResetOTP, error) {
+	SQL := `
+	SELECT id, user_id, hashed_otp, attempts, expires_at, created_at
+	FROM password_reset_otps
+	WHERE user_id = $1 AND used_at IS NULL
+	ORDER BY created_at DESC
+	LIMIT 1
+	FOR UPDATE
+	`
+
+	var otp domain.PasswordResetOTP
+	err := tx.QueryRowContext(ctx, SQL, userId).Scan(
+		&otp.Id,
+		&otp.UserId,
+		&otp.HashedOTP,
+		&otp.Attempts,
+		&otp.ExpiresAt,
+		&otp.CreatedAt,
+	)
+	if err != nil {
+		return domain.PasswordResetOTP{}, err
+	}
+
+	return otp, nil
+}
+
+func (repository *PasswordResetRepositoryImpl) IncrementAttempts(ctx context.Context, tx *sql.Tx, otpId int) error {
+	SQL := `
+	UPDATE password_reset_otps
+	SET attempts = attempts + 1
+	WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, otpId)
+	return err
+}
+
+func (repository *PasswordResetRepositoryImpl) MarkUsed(ctx context.Context, tx *sql.Tx, otpId int) error {
+	SQL := `
+	UPDATE password_reset_otps
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, otpId)
+	return err
+}
+
+func (repository *PasswordResetRepositoryImpl) InvalidateByUserId(ctx context.Context, tx *sql.Tx, userId int) error {
+	SQL := `
+	UPDATE password_reset_otps
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userId)
+	return err
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type ProfileChangeRepository interface {
+	SavePending(ctx context.Context, tx *sql.Tx, request domain.ProfileChangeRequest) (domain.ProfileChangeRequest, error)
+	GetByIdForUpdate(ctx context.Context, tx *sql.Tx, requestId int) (domain.ProfileChangeRequest, error)
+	GetByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]domain.ProfileChangeRequest, error)
+	GetByStatus(ctx context.Context, tx *sql.Tx, status string) ([]domain.ProfileChangeRequest, error)
+	Review(ctx context.Context, tx *sql.Tx, requestId int, status string, reviewerId int, reviewNote string) (domain.ProfileChangeRequest, error)
+}
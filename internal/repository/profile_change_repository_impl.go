@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewProfileChangeRepository() ProfileChangeRepository {
+	return &ProfileChangeRepositoryImpl{}
+}
+
+type ProfileChangeRepositoryImpl struct{}
+
+const profileChangeColumns = `p.id, p.user_id, p.changes, p.note, p.status, p.reviewed_by, p.review_note, p.reviewed_at, p.created_at, p.updated_at, COALESCE(u.nim, ''), u.full_name`
+
+// SavePending stores the request, replacing the changes of a request the
+// user still has pending
+func (repository *ProfileChangeRepositoryImpl) SavePending(ctx context.Context, tx *sql.Tx, request domain.ProfileChangeRequest) (domain.ProfileChangeRequest, error) {
+	changes, err := json.Marshal(request.Changes)
+	if err != nil {
+		return domain.ProfileChangeRequest{}, err
+	}
+
+	SQL := `
+	WITH p AS (
+		INSERT INTO profile_change_requests (user_id, changes, note, status)
+		VALUES ($1, $2::jsonb, $3, $4)
+		ON CONFLICT (user_id) WHERE status = 'pending'
+		DO UPDATE SET changes = EXCLUDED.changes, note = EXCLUDED.note, created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		RETURNING *
+	)
+	SELECT ` + profileChangeColumns + `
+	FROM p
+	INNER JOIN users u ON u.id = p.user_id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, request.UserId, string(changes), request.Note, domain.ProfileChangeStatusPending)
+	if err != nil {
+		return domain.ProfileChangeRequest{}, err
+	}
+	defer rows.Close()
+
+	return scanProfileChangeRequest(rows)
+}
+
+func (repository *ProfileChangeRepositoryImpl) GetByIdForUpdate(ctx context.Context, tx *sql.Tx, requestId int) (domain.ProfileChangeRequest, error) {
+	SQL := `SELECT ` + profileChangeColumns + `
+	FROM profile_change_requests p
+	INNER JOIN users u ON u.id = p.user_id
+	WHERE p.id = $1
+	FOR UPDATE OF p
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, requestId)
+	if err != nil {
+		return domain.ProfileChangeRequest{}, err
+	}
+	defer rows.Close()
+
+	return scanProfileChangeRequest(rows)
+}
+
+func (repository *ProfileChangeRepositoryImpl) GetByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]domain.ProfileChangeRequest, error) {
+	SQL := `SELECT ` + profileChangeColumns + `
+	FROM profile_change_requests p
+	INNER JOIN users u ON u.id = p.user_id
+	WHERE p.user_id = $1
+	ORDER BY p.created_at DESC
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanProfileChangeRequests(rows)
+}
+
+// GetByStatus returns the oldest requests first so they are reviewed in order.
+// An empty status returns every request.
+func (repository *ProfileChangeRepositoryImpl) GetByStatus(ctx context.Context, tx *sql.Tx, status string) ([]domain.ProfileChangeRequest, error) {
+	SQL := `SELECT ` + profileChangeColumns + `
+	FROM profile_change_requests p
+	INNER JOIN users u ON u.id = p.user_id
+	WHERE $1 = '' OR p.status = $1
+	ORDER BY p.created_at
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanProfileChangeRequests(rows)
+}
+
+// Review closes a pending request as approved or rejected
+func (repository *ProfileChangeRepositoryImpl) Review(ctx context.Context, tx *sql.Tx, requestId int, status string, reviewerId int, reviewNote string) (domain.ProfileChangeRequest, error) {
+	SQL := `
+	WITH p AS (
+		UPDATE profile_change_requests
+		SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = $5
+		RETURNING *
+	)
+	SELECT ` + profileChangeColumns + `
+	FROM p
+	INNER JOIN users u ON u.id = p.user_id
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, status, reviewerId, reviewNote, requestId, domain.ProfileChangeStatusPending)
+	if err != nil {
+		return domain.ProfileChangeRequest{}, err
+	}
+	defer rows.Close()
+
+	return scanProfileChangeRequest(rows)
+}
+
+func scanProfileChangeRequest(rows *sql.Rows) (domain.ProfileChangeRequest, error) {
+	requests, err := scanProfileChangeRequests(rows)
+	if err != nil {
+		return domain.ProfileChangeRequest{}, err
+	}
+	if len(requests) == 0 {
+		return domain.ProfileChangeRequest{}, sql.ErrNoRows
+	}
+
+	return requests[0], nil
+}
+
+func scanProfileChangeRequests(rows *sql.Rows) ([]domain.ProfileChangeRequest, error) {
+	var requests []domain.ProfileChangeRequest
+
+	for rows.Next() {
+		var (
+			request    domain.ProfileChangeRequest
+			changes    []byte
+			reviewedBy sql.NullInt64
+			reviewedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&request.Id,
+			&request.UserId,
+			&changes,
+			&request.Note,
+			&request.Status,
+			&reviewedBy,
+			&request.ReviewNote,
+			&reviewedAt,
+			&request.CreatedAt,
+			&request.UpdatedAt,
+			&request.UserNIM,
+			&request.UserFullName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(changes, &request.Changes); err != nil {
+			return nil, err
+		}
+		if reviewedBy.Valid {
+			id := int(reviewedBy.Int64)
+			request.ReviewedBy = &id
+		}
+		if reviewedAt.Valid {
+			request.ReviewedAt = &reviewedAt.Time
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
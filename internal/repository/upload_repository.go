@@ -1,4 +1,17 @@
 package repository
 
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
 type UploadRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, upload domain.CandidateUpload) (domain.CandidateUpload, error)
+	GetByUploadKey(ctx context.Context, tx *sql.Tx, uploadKey string) (domain.CandidateUpload, error)
+	UpdateStatus(ctx context.Context, tx *sql.Tx, upload domain.CandidateUpload) error
+	ExpireIssued(ctx context.Context, tx *sql.Tx, gracePeriod time.Duration) (int64, error)
+	MarkDeleted(ctx context.Context, tx *sql.Tx, keys []string) error
 }
@@ -1,7 +1,107 @@
 package repository
 
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
 func NewUploadRepository() UploadRepository {
 	return &UploadRepositoryImpl{}
 }
 
 type UploadRepositoryImpl struct{}
+
+func (repository *UploadRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, upload domain.CandidateUpload) (domain.CandidateUpload, error) {
+	SQL := `
+	INSERT INTO candidate_uploads (upload_key, status, issued_by)
+	VALUES ($1, $2, $3)
+	RETURNING id, created_at, updated_at
+	`
+
+	err := tx.QueryRowContext(ctx, SQL, upload.UploadKey, upload.Status, upload.IssuedBy).Scan(&upload.Id, &upload.CreatedAt, &upload.UpdatedAt)
+	if err != nil {
+		return domain.CandidateUpload{}, err
+	}
+
+	return upload, nil
+}
+
+func (repository *UploadRepositoryImpl) GetByUploadKey(ctx context.Context, tx *sql.Tx, uploadKey string) (domain.CandidateUpload, error) {
+	SQL := `
+	SELECT id, upload_key, object_key, status, issued_by, created_at, updated_at
+	FROM candidate_uploads
+	WHERE upload_key = $1
+	`
+
+	var (
+		upload   domain.CandidateUpload
+		issuedBy sql.NullInt64
+	)
+
+	err := tx.QueryRowContext(ctx, SQL, uploadKey).Scan(
+		&upload.Id,
+		&upload.UploadKey,
+		&upload.ObjectKey,
+		&upload.Status,
+		&issuedBy,
+		&upload.CreatedAt,
+		&upload.UpdatedAt,
+	)
+	if err != nil {
+		return domain.CandidateUpload{}, err
+	}
+
+	if issuedBy.Valid {
+		id := int(issuedBy.Int64)
+		upload.IssuedBy = &id
+	}
+
+	return upload, nil
+}
+
+func (repository *UploadRepositoryImpl) UpdateStatus(ctx context.Context, tx *sql.Tx, upload domain.CandidateUpload) error {
+	SQL := `
+	UPDATE candidate_uploads
+	SET status = $1, object_key = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, upload.Status, upload.ObjectKey, upload.Id)
+	return err
+}
+
+// ExpireIssued marks uploads that were not confirmed within the grace period as expired
+func (repository *UploadRepositoryImpl) ExpireIssued(ctx context.Context, tx *sql.Tx, gracePeriod time.Duration) (int64, error) {
+	SQL := `
+	UPDATE candidate_uploads
+	SET status = $1, updated_at = CURRENT_TIMESTAMP
+	WHERE status = $2 AND created_at < CURRENT_TIMESTAMP - make_interval(secs => $3)
+	`
+
+	result, err := tx.ExecContext(ctx, SQL, domain.CandidateUploadStatusExpired, domain.CandidateUploadStatusIssued, gracePeriod.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// MarkDeleted marks the uploads whose original or confirmed object is one of
+// the given keys as deleted
+func (repository *UploadRepositoryImpl) MarkDeleted(ctx context.Context, tx *sql.Tx, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	SQL := `
+	UPDATE candidate_uploads
+	SET status = $1, updated_at = CURRENT_TIMESTAMP
+	WHERE upload_key = ANY($2) OR object_key = ANY($2)
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.CandidateUploadStatusDeleted, keys)
+	return err
+}
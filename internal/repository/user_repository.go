@@ -14,7 +14,8 @@ type UserRepository interface {
 	GetUserBySession(ctx context.Context, tx *sql.Tx, sessionId string) (domain.User, error)
 	GetAdmins(ctx context.Context, tx *sql.Tx) ([]domain.User, error)
 	GetById(ctx context.Context, tx *sql.Tx, userId int) (domain.User, error)
-	GetAll(ctx context.Context, tx *sql.Tx) ([]domain.User, error)
+	GetAll(ctx context.Context, tx *sql.Tx, filter domain.UserListFilter) ([]domain.User, error)
+	CountAll(ctx context.Context, tx *sql.Tx, filter domain.UserListFilter) (int, error)
 	GetByIdWithPassword(ctx context.Context, tx *sql.Tx, userId int) (domain.User, error)
 	UpdateById(ctx context.Context, tx *sql.Tx, userId int, user domain.User) (domain.User, error)
 	DeleteById(ctx context.Context, tx *sql.Tx, userId int) error
@@ -22,4 +23,16 @@ type UserRepository interface {
 	SaveBulk(ctx context.Context, tx *sql.Tx, users []domain.User) ([]domain.User, error)
 	GetUsersWithNullPassword(ctx context.Context, tx *sql.Tx) ([]domain.User, error)
 	UpdateBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error
+	GetExistingNIMs(ctx context.Context, tx *sql.Tx, nims []string) ([]string, error)
+	GetExistingPhoneNumbers(ctx context.Context, tx *sql.Tx, phoneNumbers []string) ([]string, error)
+	GetByCredentialFilter(ctx context.Context, tx *sql.Tx, filter domain.UserCredentialFilter) ([]domain.User, error)
+	GetByNIMs(ctx context.Context, tx *sql.Tx, nims []string) ([]domain.User, error)
+	GetByPhoneNumbers(ctx context.Context, tx *sql.Tx, phoneNumbers []string) ([]domain.User, error)
+	GetActiveStudentsExcept(ctx context.Context, tx *sql.Tx, nims []string) ([]domain.User, error)
+	UpdateProfileBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error
+	DeactivateBulk(ctx context.Context, tx *sql.Tx, userIds []int) error
+	RestoreBulk(ctx context.Context, tx *sql.Tx, userIds []int) error
+	GetAllUnpaginated(ctx context.Context, tx *sql.Tx) ([]domain.User, error)
+	UpdatePhoneNumberBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error
+	IterateVoterRoll(ctx context.Context, tx *sql.Tx, period int, fn func(entry domain.VoterRollEntry) error) error
 }
@@ -6,18 +6,12 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 )
 
-// Variables to handle null values
-var (
-	nim          sql.NullString
-	studyProgram sql.NullString
-	password     sql.NullString
-)
-
 func NewUserRepository() UserRepository {
 	return &UserRepositoryImpl{}
 }
@@ -29,12 +23,12 @@ func (repository *UserRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, user
 
 	if user.Role != "" {
 		SQL = `
-		INSERT INTO users (nim, full_name, study_program, password, role, phone_number)
-		VALUES (NULLIF($1, ''), $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6)
+		INSERT INTO users (nim, full_name, study_program, password, role, phone_number, email)
+		VALUES (NULLIF($1, ''), $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, NULLIF($7, ''))
 		RETURNING id, created_at, updated_at
 		`
 
-		err := tx.QueryRowContext(ctx, SQL, user.NIM, user.FullName, user.StudyProgram, user.Password, user.Role, user.PhoneNumber).Scan(
+		err := tx.QueryRowContext(ctx, SQL, user.NIM, user.FullName, user.StudyProgram, user.Password, user.Role, user.PhoneNumber, user.Email).Scan(
 			&user.Id,
 			&user.CreatedAt,
 			&user.UpdatedAt,
@@ -44,12 +38,12 @@ func (repository *UserRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, user
 		}
 	} else {
 		SQL = `
-		INSERT INTO users (nim, full_name, study_program, password, phone_number)
-		VALUES (NULLIF($1, ''), $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+		INSERT INTO users (nim, full_name, study_program, password, phone_number, email)
+		VALUES (NULLIF($1, ''), $2, NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, ''))
 		RETURNING id, role, created_at, updated_at
 		`
 
-		err := tx.QueryRowContext(ctx, SQL, user.NIM, user.FullName, user.StudyProgram, user.Password, user.PhoneNumber).Scan(
+		err := tx.QueryRowContext(ctx, SQL, user.NIM, user.FullName, user.StudyProgram, user.Password, user.PhoneNumber, user.Email).Scan(
 			&user.Id,
 			&user.Role,
 			&user.CreatedAt,
@@ -77,7 +71,11 @@ func (repository *UserRepositoryImpl) UpdatePartial(ctx context.Context, tx *sql
 	RETURNING id, nim, full_name, study_program, role, created_at, updated_at
 	`, setQuery, len(args))
 
-	var user domain.User
+	var (
+		user         domain.User
+		nim          sql.NullString
+		studyProgram sql.NullString
+	)
 	err := tx.QueryRowContext(ctx, SQL, args...).Scan(
 		&user.Id,
 		&nim,
@@ -105,12 +103,18 @@ func (repository *UserRepositoryImpl) UpdatePartial(ctx context.Context, tx *sql
 
 func (repository *UserRepositoryImpl) GetByNIM(ctx context.Context, tx *sql.Tx, nim string) (domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, password, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, password, role, phone_number, email, deactivated_at, created_at, updated_at
 	FROM users
 	WHERE nim = $1
 	`
 
-	var user domain.User
+	var (
+		user         domain.User
+		studyProgram sql.NullString
+		password     sql.NullString
+		email        sql.NullString
+		deactivated  sql.NullTime
+	)
 	err := tx.QueryRowContext(ctx, SQL, nim).Scan(
 		&user.Id,
 		&user.NIM,
@@ -119,6 +123,8 @@ func (repository *UserRepositoryImpl) GetByNIM(ctx context.Context, tx *sql.Tx,
 		&password,
 		&user.Role,
 		&user.PhoneNumber,
+		&email,
+		&deactivated,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -127,9 +133,16 @@ func (repository *UserRepositoryImpl) GetByNIM(ctx context.Context, tx *sql.Tx,
 	if studyProgram.Valid {
 		user.StudyProgram = studyProgram.String
 	}
+	if email.Valid {
+		user.Email = email.String
+	}
 	if password.Valid {
 		user.Password = password.String
 	}
+	if deactivated.Valid {
+		deactivatedAt := deactivated.Time
+		user.DeactivatedAt = &deactivatedAt
+	}
 
 	if err != nil {
 		return domain.User{}, err
@@ -147,6 +160,7 @@ func (repository *UserRepositoryImpl) GetUserBySession(ctx context.Context, tx *
 		u.study_program,
 		u.role,
 		u.phone_number,
+		u.email,
 		u.created_at,
 		u.updated_at
 	FROM
@@ -157,7 +171,12 @@ func (repository *UserRepositoryImpl) GetUserBySession(ctx context.Context, tx *
 		s.session_id = $1
 	`
 
-	var user domain.User
+	var (
+		user         domain.User
+		nim          sql.NullString
+		studyProgram sql.NullString
+		email        sql.NullString
+	)
 	err := tx.QueryRowContext(ctx, SQL, sessionId).Scan(
 		&user.Id,
 		&nim,
@@ -165,6 +184,7 @@ func (repository *UserRepositoryImpl) GetUserBySession(ctx context.Context, tx *
 		&studyProgram,
 		&user.Role,
 		&user.PhoneNumber,
+		&email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -176,6 +196,9 @@ func (repository *UserRepositoryImpl) GetUserBySession(ctx context.Context, tx *
 	if studyProgram.Valid {
 		user.StudyProgram = studyProgram.String
 	}
+	if email.Valid {
+		user.Email = email.String
+	}
 
 	if err != nil {
 		return domain.User{}, err
@@ -184,22 +207,29 @@ func (repository *UserRepositoryImpl) GetUserBySession(ctx context.Context, tx *
 	return user, nil
 }
 
+// GetAdmins returns the staff accounts that can log in, so deactivated staff are left out
 func (repository *UserRepositoryImpl) GetAdmins(ctx context.Context, tx *sql.Tx) ([]domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, password, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, password, role, phone_number, email, created_at, updated_at
 	FROM users
-	WHERE nim is null and role = $1
+	WHERE nim is null and role <> $1 AND deactivated_at IS NULL
 	`
 
 	var users []domain.User
-	rows, err := tx.QueryContext(ctx, SQL, "admin")
+	rows, err := tx.QueryContext(ctx, SQL, domain.RoleStudent)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var user domain.User
+		var (
+			user         domain.User
+			nim          sql.NullString
+			studyProgram sql.NullString
+			password     sql.NullString
+			email        sql.NullString
+		)
 
 		err := rows.Scan(
 			&user.Id,
@@ -209,6 +239,7 @@ func (repository *UserRepositoryImpl) GetAdmins(ctx context.Context, tx *sql.Tx)
 			&password,
 			&user.Role,
 			&user.PhoneNumber,
+			&email,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -220,6 +251,9 @@ func (repository *UserRepositoryImpl) GetAdmins(ctx context.Context, tx *sql.Tx)
 		if studyProgram.Valid {
 			user.StudyProgram = studyProgram.String
 		}
+		if email.Valid {
+			user.Email = email.String
+		}
 		if password.Valid {
 			user.Password = password.String
 		}
@@ -240,12 +274,18 @@ func (repository *UserRepositoryImpl) GetAdmins(ctx context.Context, tx *sql.Tx)
 
 func (repository *UserRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, userId int) (domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, role, phone_number, email, deactivated_at, created_at, updated_at
 	FROM users
 	WHERE id = $1
 	`
 
-	var user domain.User
+	var (
+		user         domain.User
+		nim          sql.NullString
+		studyProgram sql.NullString
+		email        sql.NullString
+		deactivated  sql.NullTime
+	)
 	err := tx.QueryRowContext(ctx, SQL, userId).Scan(
 		&user.Id,
 		&nim,
@@ -253,6 +293,8 @@ func (repository *UserRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, u
 		&studyProgram,
 		&user.Role,
 		&user.PhoneNumber,
+		&email,
+		&deactivated,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -264,6 +306,13 @@ func (repository *UserRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, u
 	if studyProgram.Valid {
 		user.StudyProgram = studyProgram.String
 	}
+	if email.Valid {
+		user.Email = email.String
+	}
+	if deactivated.Valid {
+		deactivatedAt := deactivated.Time
+		user.DeactivatedAt = &deactivatedAt
+	}
 
 	if err != nil {
 		return domain.User{}, err
@@ -272,21 +321,53 @@ func (repository *UserRepositoryImpl) GetById(ctx context.Context, tx *sql.Tx, u
 	return user, nil
 }
 
-func (repository *UserRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx) ([]domain.User, error) {
-	SQL := `
-	SELECT id, nim, full_name, study_program, role, phone_number, created_at, updated_at
-	FROM users
-	`
+// userSortColumns whitelists the columns a user list can be sorted by
+var userSortColumns = map[string]string{
+	"nim":           "nim",
+	"full_name":     "full_name",
+	"study_program": "study_program",
+	"role":          "role",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+func (repository *UserRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx, filter domain.UserListFilter) ([]domain.User, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(`
+	SELECT id, nim, full_name, study_program, role, phone_number, email, deactivated_at, created_at, updated_at
+	FROM users u
+	`)
+	args := writeUserListWhere(&queryBuilder, filter)
+
+	sortColumn, ok := userSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = "id"
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY u.%s %s NULLS LAST, u.id %s", sortColumn, direction, direction))
+
+	args = append(args, filter.Limit, filter.Offset)
+	queryBuilder.WriteString(fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args)))
 
 	var users []domain.User
-	rows, err := tx.QueryContext(ctx, SQL)
+	rows, err := tx.QueryContext(ctx, queryBuilder.String(), args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var user domain.User
+		var (
+			user         domain.User
+			nim          sql.NullString
+			studyProgram sql.NullString
+			email        sql.NullString
+			deactivated  sql.NullTime
+		)
 
 		err := rows.Scan(
 			&user.Id,
@@ -295,6 +376,8 @@ func (repository *UserRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx) ([
 			&studyProgram,
 			&user.Role,
 			&user.PhoneNumber,
+			&email,
+			&deactivated,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -306,6 +389,13 @@ func (repository *UserRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx) ([
 		if studyProgram.Valid {
 			user.StudyProgram = studyProgram.String
 		}
+		if email.Valid {
+			user.Email = email.String
+		}
+		if deactivated.Valid {
+			deactivatedAt := deactivated.Time
+			user.DeactivatedAt = &deactivatedAt
+		}
 
 		if err != nil {
 			return nil, err
@@ -321,14 +411,126 @@ func (repository *UserRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx) ([
 	return users, nil
 }
 
+func (repository *UserRepositoryImpl) CountAll(ctx context.Context, tx *sql.Tx, filter domain.UserListFilter) (int, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString("SELECT COUNT(*) FROM users u")
+	args := writeUserListWhere(&queryBuilder, filter)
+
+	var total int
+	err := tx.QueryRowContext(ctx, queryBuilder.String(), args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// writeUserListWhere appends the WHERE clause of a user list and returns its args
+func writeUserListWhere(queryBuilder *strings.Builder, filter domain.UserListFilter) []interface{} {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.Search != "" {
+		// NIM prefix, or every word as a prefix of a word in the full name
+		args = append(args, likeEscaper.Replace(filter.Search))
+		condition := fmt.Sprintf("u.nim LIKE $%d || '%%'", len(args))
+
+		if tsQuery := toPrefixTSQuery(filter.Search); tsQuery != "" {
+			args = append(args, tsQuery)
+			condition += fmt.Sprintf(" OR to_tsvector('simple', u.full_name) @@ to_tsquery('simple', $%d)", len(args))
+		}
+
+		conditions = append(conditions, "("+condition+")")
+	}
+
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("u.role = $%d", len(args)))
+	}
+
+	if filter.StudyProgram != "" {
+		args = append(args, filter.StudyProgram)
+		conditions = append(conditions, fmt.Sprintf("u.study_program = $%d", len(args)))
+	}
+
+	switch filter.Status {
+	case domain.UserStatusActive:
+		conditions = append(conditions, "u.deactivated_at IS NULL")
+	case domain.UserStatusDeactivated:
+		conditions = append(conditions, "u.deactivated_at IS NOT NULL")
+	}
+
+	if filter.HasPassword != nil {
+		if *filter.HasPassword {
+			conditions = append(conditions, "u.password IS NOT NULL")
+		} else {
+			conditions = append(conditions, "u.password IS NULL")
+		}
+	}
+
+	if filter.HasVoted != nil {
+		hasVoted := `EXISTS (
+			SELECT 1
+			FROM votes v
+			JOIN voting_access va ON v.hashed_nim = va.hashed
+			WHERE va.user_id = u.id
+				AND EXTRACT(YEAR FROM v.created_at) = EXTRACT(YEAR FROM CURRENT_DATE)
+		)`
+		if *filter.HasVoted {
+			conditions = append(conditions, hasVoted)
+		} else {
+			conditions = append(conditions, "NOT "+hasVoted)
+		}
+	}
+
+	if len(conditions) > 0 {
+		queryBuilder.WriteString(" WHERE ")
+		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	return args
+}
+
+// likeEscaper escapes the LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// toPrefixTSQuery turns free text into a tsquery matching every word as a prefix,
+// e.g. "john do" becomes "john:* & do:*". Characters with a meaning in tsquery are dropped.
+func toPrefixTSQuery(search string) string {
+	var terms []string
+	for _, word := range strings.Fields(search) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, word)
+
+		if word != "" {
+			terms = append(terms, word+":*")
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
+
 func (repository *UserRepositoryImpl) GetByIdWithPassword(ctx context.Context, tx *sql.Tx, userId int) (domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, password, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, password, role, phone_number, email, created_at, updated_at
 	FROM users
 	WHERE id = $1
 	`
 
-	var user domain.User
+	var (
+		user         domain.User
+		nim          sql.NullString
+		studyProgram sql.NullString
+		password     sql.NullString
+		email        sql.NullString
+	)
 	err := tx.QueryRowContext(ctx, SQL, userId).Scan(
 		&user.Id,
 		&nim,
@@ -337,6 +539,7 @@ func (repository *UserRepositoryImpl) GetByIdWithPassword(ctx context.Context, t
 		&password,
 		&user.Role,
 		&user.PhoneNumber,
+		&email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -348,6 +551,9 @@ func (repository *UserRepositoryImpl) GetByIdWithPassword(ctx context.Context, t
 	if studyProgram.Valid {
 		user.StudyProgram = studyProgram.String
 	}
+	if email.Valid {
+		user.Email = email.String
+	}
 	if password.Valid {
 		user.Password = password.String
 	}
@@ -362,8 +568,8 @@ func (repository *UserRepositoryImpl) GetByIdWithPassword(ctx context.Context, t
 func (repository *UserRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx, userId int, user domain.User) (domain.User, error) {
 	SQL := `
 	UPDATE users
-	SET nim = NULLIF($1, ''), full_name = $2, study_program = NULLIF($3, ''), password = NULLIF($4, ''), role = $5, phone_number = $6, updated_at = $7
-	WHERE id = $8
+	SET nim = NULLIF($1, ''), full_name = $2, study_program = NULLIF($3, ''), password = NULLIF($4, ''), role = $5, phone_number = $6, email = NULLIF($7, ''), updated_at = $8
+	WHERE id = $9
 	`
 
 	updatedAt := time.Now()
@@ -377,6 +583,7 @@ func (repository *UserRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx
 		user.Password,
 		user.Role,
 		user.PhoneNumber,
+		user.Email,
 		updatedAt,
 		userId,
 	)
@@ -393,6 +600,7 @@ func (repository *UserRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx
 		Password:     user.Password,
 		Role:         user.Role,
 		PhoneNumber:  user.PhoneNumber,
+		Email:        user.Email,
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    updatedAt,
 	}, nil
@@ -414,12 +622,18 @@ func (repository *UserRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx
 
 func (repository *UserRepositoryImpl) GetByPhoneNumber(ctx context.Context, tx *sql.Tx, phoneNumber string) (domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, password, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, password, role, phone_number, email, created_at, updated_at
 	FROM users
 	WHERE phone_number = $1
 	`
 
-	var user domain.User
+	var (
+		user         domain.User
+		nim          sql.NullString
+		studyProgram sql.NullString
+		password     sql.NullString
+		email        sql.NullString
+	)
 	err := tx.QueryRowContext(ctx, SQL, phoneNumber).Scan(
 		&user.Id,
 		&nim,
@@ -428,6 +642,7 @@ func (repository *UserRepositoryImpl) GetByPhoneNumber(ctx context.Context, tx *
 		&password,
 		&user.Role,
 		&user.PhoneNumber,
+		&email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -439,6 +654,9 @@ func (repository *UserRepositoryImpl) GetByPhoneNumber(ctx context.Context, tx *
 	if studyProgram.Valid {
 		user.StudyProgram = studyProgram.String
 	}
+	if email.Valid {
+		user.Email = email.String
+	}
 	if password.Valid {
 		user.Password = password.String
 	}
@@ -513,9 +731,9 @@ func (repository *UserRepositoryImpl) SaveBulk(ctx context.Context, tx *sql.Tx,
 
 func (repository *UserRepositoryImpl) GetUsersWithNullPassword(ctx context.Context, tx *sql.Tx) ([]domain.User, error) {
 	SQL := `
-	SELECT id, nim, full_name, study_program, role, phone_number, created_at, updated_at
+	SELECT id, nim, full_name, study_program, role, phone_number, email, created_at, updated_at
 	FROM users
-	WHERE password IS NULL
+	WHERE password IS NULL AND deactivated_at IS NULL
 	`
 
 	var users []domain.User
@@ -526,7 +744,12 @@ func (repository *UserRepositoryImpl) GetUsersWithNullPassword(ctx context.Conte
 	defer rows.Close()
 
 	for rows.Next() {
-		var user domain.User
+		var (
+			user         domain.User
+			nim          sql.NullString
+			studyProgram sql.NullString
+			email        sql.NullString
+		)
 
 		err := rows.Scan(
 			&user.Id,
@@ -535,6 +758,7 @@ func (repository *UserRepositoryImpl) GetUsersWithNullPassword(ctx context.Conte
 			&studyProgram,
 			&user.Role,
 			&user.PhoneNumber,
+			&email,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -546,11 +770,100 @@ func (repository *UserRepositoryImpl) GetUsersWithNullPassword(ctx context.Conte
 		if studyProgram.Valid {
 			user.StudyProgram = studyProgram.String
 		}
+		if email.Valid {
+			user.Email = email.String
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (repository *UserRepositoryImpl) GetByCredentialFilter(ctx context.Context, tx *sql.Tx, filter domain.UserCredentialFilter) ([]domain.User, error) {
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString(`
+	SELECT u.id, u.nim, u.full_name, u.study_program, u.role, u.phone_number, u.email, u.created_at, u.updated_at
+	FROM users u
+	WHERE u.role = $1 AND u.deactivated_at IS NULL
+	`)
+	args = append(args, domain.RoleStudent)
+
+	if len(filter.Ids) > 0 {
+		args = append(args, filter.Ids)
+		queryBuilder.WriteString(fmt.Sprintf(" AND u.id = ANY($%d)", len(args)))
+	}
+
+	if filter.StudyProgram != "" {
+		args = append(args, filter.StudyProgram)
+		queryBuilder.WriteString(fmt.Sprintf(" AND u.study_program = $%d", len(args)))
+	}
+
+	if filter.HasVotingAccess {
+		queryBuilder.WriteString(" AND EXISTS (SELECT 1 FROM voting_access va WHERE va.user_id = u.id)")
+	}
+
+	if filter.NotVoted {
+		queryBuilder.WriteString(`
+		AND NOT EXISTS (
+			SELECT 1
+			FROM votes v
+			JOIN voting_access va ON v.hashed_nim = va.hashed
+			WHERE va.user_id = u.id
+				AND EXTRACT(YEAR FROM v.created_at) = EXTRACT(YEAR FROM CURRENT_DATE)
+		)
+		`)
+	}
+
+	queryBuilder.WriteString(" ORDER BY u.id")
+
+	rows, err := tx.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var (
+			user      domain.User
+			userNIM   sql.NullString
+			userStudy sql.NullString
+			userEmail sql.NullString
+		)
 
+		err := rows.Scan(
+			&user.Id,
+			&userNIM,
+			&user.FullName,
+			&userStudy,
+			&user.Role,
+			&user.PhoneNumber,
+			&userEmail,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
 		if err != nil {
 			return nil, err
 		}
 
+		// Handle null fields
+		user.NIM = userNIM.String
+		user.StudyProgram = userStudy.String
+		user.Email = userEmail.String
+
 		users = append(users, user)
 	}
 
@@ -561,6 +874,40 @@ func (repository *UserRepositoryImpl) GetUsersWithNullPassword(ctx context.Conte
 	return users, nil
 }
 
+func (repository *UserRepositoryImpl) GetExistingNIMs(ctx context.Context, tx *sql.Tx, nims []string) ([]string, error) {
+	return repository.getExistingValues(ctx, tx, "nim", nims)
+}
+
+func (repository *UserRepositoryImpl) GetExistingPhoneNumbers(ctx context.Context, tx *sql.Tx, phoneNumbers []string) ([]string, error) {
+	return repository.getExistingValues(ctx, tx, "phone_number", phoneNumbers)
+}
+
+// getExistingValues returns which of the values are already stored in column
+func (repository *UserRepositoryImpl) getExistingValues(ctx context.Context, tx *sql.Tx, column string, values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	SQL := fmt.Sprintf(`SELECT %[1]s FROM users WHERE %[1]s = ANY($1)`, column)
+
+	rows, err := tx.QueryContext(ctx, SQL, values)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		existing = append(existing, value)
+	}
+
+	return existing, rows.Err()
+}
+
 func (repository *UserRepositoryImpl) UpdateBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error {
 	var (
 		queryBuilder strings.Builder
@@ -600,3 +947,271 @@ func (repository *UserRepositoryImpl) UpdateBulk(ctx context.Context, tx *sql.Tx
 
 	return nil
 }
+
+func (repository *UserRepositoryImpl) GetByNIMs(ctx context.Context, tx *sql.Tx, nims []string) ([]domain.User, error) {
+	return repository.getByValues(ctx, tx, "nim", nims)
+}
+
+func (repository *UserRepositoryImpl) GetByPhoneNumbers(ctx context.Context, tx *sql.Tx, phoneNumbers []string) ([]domain.User, error) {
+	return repository.getByValues(ctx, tx, "phone_number", phoneNumbers)
+}
+
+// getByValues returns the users whose column matches any of the values
+func (repository *UserRepositoryImpl) getByValues(ctx context.Context, tx *sql.Tx, column string, values []string) ([]domain.User, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	SQL := fmt.Sprintf(`
+	SELECT id, nim, full_name, study_program, role, phone_number, email, deactivated_at, created_at, updated_at
+	FROM users
+	WHERE %s = ANY($1)
+	ORDER BY id
+	`, column)
+
+	return repository.queryUsersWithStatus(ctx, tx, SQL, values)
+}
+
+// GetActiveStudentsExcept returns the active students whose NIM is not in nims
+func (repository *UserRepositoryImpl) GetActiveStudentsExcept(ctx context.Context, tx *sql.Tx, nims []string) ([]domain.User, error) {
+	SQL := `
+	SELECT id, nim, full_name, study_program, role, phone_number, email, deactivated_at, created_at, updated_at
+	FROM users
+	WHERE role = $1
+		AND deactivated_at IS NULL
+		AND NOT (nim = ANY($2))
+	ORDER BY id
+	`
+
+	if nims == nil {
+		nims = []string{}
+	}
+
+	return repository.queryUsersWithStatus(ctx, tx, SQL, domain.RoleStudent, nims)
+}
+
+// GetAllUnpaginated returns every user, deactivated ones included, ordered by id
+func (repository *UserRepositoryImpl) GetAllUnpaginated(ctx context.Context, tx *sql.Tx) ([]domain.User, error) {
+	SQL := `
+	SELECT id, nim, full_name, study_program, role, phone_number, email, deactivated_at, created_at, updated_at
+	FROM users
+	ORDER BY id
+	`
+
+	return repository.queryUsersWithStatus(ctx, tx, SQL)
+}
+
+func (repository *UserRepositoryImpl) queryUsersWithStatus(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]domain.User, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var (
+			user          domain.User
+			userNIM       sql.NullString
+			userStudy     sql.NullString
+			userEmail     sql.NullString
+			deactivatedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&user.Id,
+			&userNIM,
+			&user.FullName,
+			&userStudy,
+			&user.Role,
+			&user.PhoneNumber,
+			&userEmail,
+			&deactivatedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Handle null fields
+		user.NIM = userNIM.String
+		user.StudyProgram = userStudy.String
+		user.Email = userEmail.String
+		if deactivatedAt.Valid {
+			user.DeactivatedAt = &deactivatedAt.Time
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UpdateProfileBulk updates full_name, study_program and phone_number by id
+func (repository *UserRepositoryImpl) UpdateProfileBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error {
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString(`
+	UPDATE users AS u
+	SET full_name = v.full_name,
+		study_program = NULLIF(v.study_program, ''),
+		phone_number = v.phone_number,
+		updated_at = CURRENT_TIMESTAMP
+	FROM (VALUES
+	`)
+
+	for i, user := range users {
+		start := i*4 + 1
+
+		queryBuilder.WriteString(fmt.Sprintf("($%d::integer, $%d, $%d, $%d)", start, start+1, start+2, start+3))
+
+		if i < len(users)-1 {
+			queryBuilder.WriteString(", ")
+		}
+
+		args = append(args, user.Id, user.FullName, user.StudyProgram, user.PhoneNumber)
+	}
+
+	queryBuilder.WriteString(`
+	) AS v(id, full_name, study_program, phone_number)
+	WHERE u.id = v.id
+	`)
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePhoneNumberBulk updates phone_number by id
+func (repository *UserRepositoryImpl) UpdatePhoneNumberBulk(ctx context.Context, tx *sql.Tx, users []domain.User) error {
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString(`
+	UPDATE users AS u
+	SET phone_number = v.phone_number,
+		updated_at = CURRENT_TIMESTAMP
+	FROM (VALUES
+	`)
+
+	for i, user := range users {
+		start := i*2 + 1
+
+		queryBuilder.WriteString(fmt.Sprintf("($%d::integer, $%d)", start, start+1))
+
+		if i < len(users)-1 {
+			queryBuilder.WriteString(", ")
+		}
+
+		args = append(args, user.Id, user.PhoneNumber)
+	}
+
+	queryBuilder.WriteString(`
+	) AS v(id, phone_number)
+	WHERE u.id = v.id
+	`)
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *UserRepositoryImpl) DeactivateBulk(ctx context.Context, tx *sql.Tx, userIds []int) error {
+	SQL := `
+	UPDATE users
+	SET deactivated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = ANY($1) AND deactivated_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *UserRepositoryImpl) RestoreBulk(ctx context.Context, tx *sql.Tx, userIds []int) error {
+	SQL := `
+	UPDATE users
+	SET deactivated_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = ANY($1) AND deactivated_at IS NOT NULL
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IterateVoterRoll calls fn for every active student with voting access, ordered
+// by study program and NIM, without loading the whole roll into memory
+func (repository *UserRepositoryImpl) IterateVoterRoll(ctx context.Context, tx *sql.Tx, period int, fn func(entry domain.VoterRollEntry) error) error {
+	SQL := `
+	SELECT u.id, u.nim, u.full_name, u.study_program,
+		EXISTS (
+			SELECT 1
+			FROM votes v
+			WHERE v.hashed_nim = va.hashed
+				AND EXTRACT(YEAR FROM v.created_at) = $2
+		) AS has_voted
+	FROM users u
+	JOIN voting_access va ON va.user_id = u.id
+	WHERE u.role = $1
+		AND u.deactivated_at IS NULL
+	ORDER BY u.study_program NULLS LAST, u.nim
+	`
+
+	rows, err := tx.QueryContext(ctx, SQL, domain.RoleStudent, period)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry     domain.VoterRollEntry
+			userNIM   sql.NullString
+			userStudy sql.NullString
+		)
+
+		err := rows.Scan(
+			&entry.UserId,
+			&userNIM,
+			&entry.FullName,
+			&userStudy,
+			&entry.HasVoted,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Handle null fields
+		entry.NIM = userNIM.String
+		entry.StudyProgram = userStudy.String
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -14,4 +14,5 @@ type VotingAccessRepository interface {
 	IsUserEverVoted(ctx context.Context, tx *sql.Tx, userId int) (bool, error)
 	DeleteByUserId(ctx context.Context, tx *sql.Tx, userId int) error
 	CreateBulk(ctx context.Context, tx *sql.Tx, votingAccesses []domain.VotingAccess) error
+	DeleteByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error
 }
@@ -124,3 +124,17 @@ func (repository *VotingAccessRepositoryImpl) CreateBulk(ctx context.Context, tx
 
 	return nil
 }
+
+func (repository *VotingAccessRepositoryImpl) DeleteByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error {
+	SQL := `
+	DELETE FROM voting_access
+	WHERE user_id = ANY($1)
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type VotingPeriodRepository interface {
+	GetByPeriod(ctx context.Context, tx *sql.Tx, period int) (domain.VotingPeriod, error)
+	Save(ctx context.Context, tx *sql.Tx, votingPeriod domain.VotingPeriod) (domain.VotingPeriod, error)
+	IsVotingStarted(ctx context.Context, tx *sql.Tx, period int) (bool, error)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewVotingPeriodRepository() VotingPeriodRepository {
+	return &VotingPeriodRepositoryImpl{}
+}
+
+type VotingPeriodRepositoryImpl struct{}
+
+func (repository *VotingPeriodRepositoryImpl) GetByPeriod(ctx context.Context, tx *sql.Tx, period int) (domain.VotingPeriod, error) {
+	SQL := `
+	SELECT period, opens_at, closes_at, updated_by, created_at, updated_at
+	FROM voting_periods
+	WHERE period = $1
+	`
+
+	return scanVotingPeriod(tx.QueryRowContext(ctx, SQL, period))
+}
+
+// Save creates the schedule of the period or replaces it
+func (repository *VotingPeriodRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, votingPeriod domain.VotingPeriod) (domain.VotingPeriod, error) {
+	SQL := `
+	INSERT INTO voting_periods (period, opens_at, closes_at, updated_by)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (period) DO UPDATE
+	SET opens_at = EXCLUDED.opens_at, closes_at = EXCLUDED.closes_at, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+	RETURNING period, opens_at, closes_at, updated_by, created_at, updated_at
+	`
+
+	return scanVotingPeriod(tx.QueryRowContext(ctx, SQL, votingPeriod.Period, votingPeriod.OpensAt, votingPeriod.ClosesAt, votingPeriod.UpdatedBy))
+}
+
+// IsVotingStarted reports whether the scheduled opening of the period has
+// passed or a vote has already been cast for one of its candidates
+func (repository *VotingPeriodRepositoryImpl) IsVotingStarted(ctx context.Context, tx *sql.Tx, period int) (bool, error) {
+	SQL := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM voting_periods
+		WHERE period = $1 AND opens_at <= CURRENT_TIMESTAMP
+	) OR EXISTS (
+		SELECT 1
+		FROM votes v
+		JOIN candidates c ON v.candidate_id = c.id
+		WHERE EXTRACT(YEAR FROM c.created_at) = $1
+	)
+	`
+
+	var started bool
+	err := tx.QueryRowContext(ctx, SQL, period).Scan(&started)
+	return started, err
+}
+
+func scanVotingPeriod(row *sql.Row) (domain.VotingPeriod, error) {
+	var (
+		votingPeriod domain.VotingPeriod
+		updatedBy    sql.NullInt64
+	)
+
+	err := row.Scan(
+		&votingPeriod.Period,
+		&votingPeriod.OpensAt,
+		&votingPeriod.ClosesAt,
+		&updatedBy,
+		&votingPeriod.CreatedAt,
+		&votingPeriod.UpdatedAt,
+	)
+	if err != nil {
+		return domain.VotingPeriod{}, err
+	}
+
+	votingPeriod.UpdatedBy = nullIntPtr(updatedBy)
+
+	return votingPeriod, nil
+}
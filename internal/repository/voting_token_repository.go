@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+type VotingTokenRepository interface {
+	SaveBulk(ctx context.Context, tx *sql.Tx, votingTokens []domain.VotingToken) error
+	GetByHashedToken(ctx context.Context, tx *sql.Tx, hashedToken string) (domain.VotingToken, error)
+	MarkUsed(ctx context.Context, tx *sql.Tx, votingTokenId int) error
+	InvalidateByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error
+}
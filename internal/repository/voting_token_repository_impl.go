@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func NewVotingTokenRepository() VotingTokenRepository {
+	return &VotingTokenRepositoryImpl{}
+}
+
+type VotingTokenRepositoryImpl struct{}
+
+func (repository *VotingTokenRepositoryImpl) SaveBulk(ctx context.Context, tx *sql.Tx, votingTokens []domain.VotingToken) error {
+	if len(votingTokens) == 0 {
+		return nil
+	}
+
+	var (
+		queryBuilder strings.Builder
+		args         []interface{}
+	)
+
+	queryBuilder.WriteString("INSERT INTO voting_tokens (user_id, hashed_token, expires_at) VALUES ")
+
+	for i, votingToken := range votingTokens {
+		start := i*3 + 1
+
+		queryBuilder.WriteString(fmt.Sprintf("($%d, $%d, $%d)", start, start+1, start+2))
+
+		if i < len(votingTokens)-1 {
+			queryBuilder.WriteString(", ")
+		}
+
+		args = append(args, votingToken.UserId, votingToken.HashedToken, votingToken.ExpiresAt)
+	}
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), args...)
+	return err
+}
+
+// GetByHashedToken locks the token row so it can only be used by one login
+func (repository *VotingTokenRepositoryImpl) GetByHashedToken(ctx context.Context, tx *sql.Tx, hashedToken string) (domain.VotingToken, error) {
+	SQL := `
+	SELECT id, user_id, hashed_token, expires_at, used_at, created_at
+	FROM voting_tokens
+	WHERE hashed_token = $1
+	FOR UPDATE
+	`
+
+	var (
+		votingToken domain.VotingToken
+		usedAt      sql.NullTime
+	)
+
+	err := tx.QueryRowContext(ctx, SQL, hashedToken).Scan(
+		&votingToken.Id,
+		&votingToken.UserId,
+		&votingToken.HashedToken,
+		&votingToken.ExpiresAt,
+		&usedAt,
+		&votingToken.CreatedAt,
+	)
+	if err != nil {
+		return domain.VotingToken{}, err
+	}
+
+	if usedAt.Valid {
+		votingToken.UsedAt = &usedAt.Time
+	}
+
+	return votingToken, nil
+}
+
+func (repository *VotingTokenRepositoryImpl) MarkUsed(ctx context.Context, tx *sql.Tx, votingTokenId int) error {
+	SQL := `
+	UPDATE voting_tokens
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, votingTokenId)
+	return err
+}
+
+func (repository *VotingTokenRepositoryImpl) InvalidateByUserIds(ctx context.Context, tx *sql.Tx, userIds []int) error {
+	SQL := `
+	UPDATE voting_tokens
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE user_id = ANY($1) AND used_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, SQL, userIds)
+	return err
+}
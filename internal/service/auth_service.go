@@ -3,13 +3,15 @@ package service
 import (
 	"context"
 
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 )
 
 type AuthService interface {
 	LoginUser(ctx context.Context, maxAge int, request web.LoginRequest) (web.LoginResponse, string, error)
 	LoginAdmin(ctx context.Context, maxAge int, request web.LoginRequest) (web.LoginResponse, string, error)
+	LoginToken(ctx context.Context, maxAge int, request web.LoginTokenRequest) (web.LoginResponse, string, int, error)
 	Logout(ctx context.Context, sessionId string) error
-	UserValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, error)
-	AdminValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, error)
+	UserValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, web.UserResponse, error)
+	PermissionValidateSession(ctx context.Context, sessionId string, permission domain.Permission) (web.SessionResponse, web.UserResponse, error)
 }
@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
@@ -15,20 +18,26 @@ import (
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
 )
 
-func NewAuthService(authRepository repository.AuthRepository, userService UserService, db *sql.DB, validate *validator.Validate) AuthService {
+func NewAuthService(authRepository repository.AuthRepository, userRepository repository.UserRepository, votingTokenRepository repository.VotingTokenRepository, userService UserService, sessionCache *cache.SessionCache, db *sql.DB, validate *validator.Validate) AuthService {
 	return &AuthServiceImpl{
-		AuthRepository: authRepository,
-		UserService:    userService,
-		DB:             db,
-		Validate:       validate,
+		AuthRepository:        authRepository,
+		UserRepository:        userRepository,
+		VotingTokenRepository: votingTokenRepository,
+		UserService:           userService,
+		SessionCache:          sessionCache,
+		DB:                    db,
+		Validate:              validate,
 	}
 }
 
 type AuthServiceImpl struct {
-	AuthRepository repository.AuthRepository
-	UserService    UserService
-	DB             *sql.DB
-	Validate       *validator.Validate
+	AuthRepository        repository.AuthRepository
+	UserRepository        repository.UserRepository
+	VotingTokenRepository repository.VotingTokenRepository
+	UserService           UserService
+	SessionCache          *cache.SessionCache
+	DB                    *sql.DB
+	Validate              *validator.Validate
 }
 
 func (service *AuthServiceImpl) LoginUser(ctx context.Context, maxAge int, request web.LoginRequest) (web.LoginResponse, string, error) {
@@ -76,6 +85,16 @@ func (service *AuthServiceImpl) LoginUser(ctx context.Context, maxAge int, reque
 		)
 	}
 
+	// Deactivated users keep their data but can't log in
+	if user.DeactivatedAt != nil {
+		return web.LoginResponse{}, "", appError.NewAppError(
+			http.StatusForbidden,
+			"Account deactivated",
+			"Your account has been deactivated. Please contact the committee.",
+			fmt.Errorf("%w: user with NIM %s", appError.ErrUserDeactivated, request.NIM),
+		)
+	}
+
 	// Save to sessions db
 	session, err := service.AuthRepository.Create(ctx, tx, domain.Session{
 		SessionId:     helper.Base64SessionId(),
@@ -202,11 +221,22 @@ func (service *AuthServiceImpl) LoginAdmin(ctx context.Context, maxAge int, requ
 	)
 }
 
-func (service *AuthServiceImpl) Logout(ctx context.Context, sessionId string) error {
+func (service *AuthServiceImpl) LoginToken(ctx context.Context, maxAge int, request web.LoginTokenRequest) (web.LoginResponse, string, int, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return appError.NewAppError(
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -215,67 +245,90 @@ func (service *AuthServiceImpl) Logout(ctx context.Context, sessionId string) er
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Delete session
-	err = service.AuthRepository.Delete(ctx, tx, sessionId)
+	// Get and lock the token
+	votingToken, err := service.VotingTokenRepository.GetByHashedToken(ctx, tx, helper.HashVotingToken(strings.ToUpper(strings.TrimSpace(request.Token))))
 	if err != nil {
-		return appError.NewAppError(
+		if errors.Is(err, sql.ErrNoRows) {
+			return web.LoginResponse{}, "", 0, appError.NewAppError(
+				http.StatusUnauthorized,
+				"Invalid credentials",
+				"The voting token is invalid, used or expired",
+				fmt.Errorf("%w: voting token not found", appError.ErrInvalidCredentials),
+			)
+		}
+
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to delete session: %w", err),
+			fmt.Errorf("failed to get voting token: %w", err),
 		)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("transaction commit failed: %w", err),
+	// A token can only create one session and only before it expires
+	now := time.Now()
+	if votingToken.UsedAt != nil || !votingToken.ExpiresAt.After(now) {
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
+			http.StatusUnauthorized,
+			"Invalid credentials",
+			"The voting token is invalid, used or expired",
+			fmt.Errorf("%w: voting token with id '%v' is used or expired", appError.ErrInvalidCredentials, votingToken.Id),
 		)
 	}
 
-	return nil
-}
-
-func (service *AuthServiceImpl) UserValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, error) {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
+	err = service.VotingTokenRepository.MarkUsed(ctx, tx, votingToken.Id)
 	if err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("failed to mark voting token as used: %w", err),
 		)
 	}
-	defer helper.RollbackQuietly(tx)
 
-	// Get session
-	session, err := service.AuthRepository.GetSessionById(ctx, tx, sessionId)
+	user, err := service.UserRepository.GetById(ctx, tx, votingToken.UserId)
 	if err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
 			http.StatusUnauthorized,
-			"Invalid session data",
-			"Session data may be corrupted, missing or expired",
-			fmt.Errorf("%w: session with id '%v' is not found", appError.ErrSessionNotFound, sessionId),
+			"Invalid credentials",
+			"The voting token is invalid, used or expired",
+			fmt.Errorf("%w: user with id '%v' of voting token: %v", appError.ErrUserNotFound, votingToken.UserId, err),
 		)
 	}
 
-	// Validate session
-	if session.CreatedAt.Add(time.Second * time.Duration(session.MaxAgeSeconds)).Before(time.Now()) {
-		return web.SessionResponse{}, appError.NewAppError(
-			http.StatusUnauthorized,
-			"Invalid session data",
-			"Session data may be corrupted, missing or expired",
-			fmt.Errorf("%w: session with id '%v' is expired", appError.ErrSessionExpired, sessionId),
+	// Deactivated users keep their data but can't log in
+	if user.DeactivatedAt != nil {
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
+			http.StatusForbidden,
+			"Account deactivated",
+			"Your account has been deactivated. Please contact the committee.",
+			fmt.Errorf("%w: user with id '%v' of voting token", appError.ErrUserDeactivated, user.Id),
+		)
+	}
+
+	// The session never outlives the token
+	if untilExpiry := int(votingToken.ExpiresAt.Sub(now).Seconds()); untilExpiry < maxAge {
+		maxAge = untilExpiry
+	}
+
+	// Save to sessions db
+	session, err := service.AuthRepository.Create(ctx, tx, domain.Session{
+		SessionId:     helper.Base64SessionId(),
+		UserId:        user.Id,
+		MaxAgeSeconds: maxAge,
+	})
+	if err != nil {
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to create session: %w", err),
 		)
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
+		return web.LoginResponse{}, "", 0, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -283,14 +336,14 @@ func (service *AuthServiceImpl) UserValidateSession(ctx context.Context, session
 		)
 	}
 
-	return helper.ToSessionResponse(session), nil
+	return helper.ToLoginResponse(user), session.SessionId, maxAge, nil
 }
 
-func (service *AuthServiceImpl) AdminValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, error) {
+func (service *AuthServiceImpl) Logout(ctx context.Context, sessionId string) error {
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
+		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -299,32 +352,106 @@ func (service *AuthServiceImpl) AdminValidateSession(ctx context.Context, sessio
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Get session
-	session, err := service.AuthRepository.GetSessionById(ctx, tx, sessionId)
+	// Delete session
+	err = service.AuthRepository.Delete(ctx, tx, sessionId)
 	if err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
-			http.StatusUnauthorized,
-			"Invalid session data",
-			"Session data may be corrupted, missing or expired",
-			fmt.Errorf("%w: session with id '%v' is not found", appError.ErrSessionNotFound, sessionId),
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to delete session: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
 		)
 	}
 
-	// Check if user is admin
-	user, _ := service.UserService.GetCurrent(ctx, sessionId)
+	// Invalidate cached session
+	service.SessionCache.Delete(sessionId)
 
-	if user.Role != "admin" {
-		return web.SessionResponse{}, appError.NewAppError(
+	return nil
+}
+
+func (service *AuthServiceImpl) UserValidateSession(ctx context.Context, sessionId string) (web.SessionResponse, web.UserResponse, error) {
+	sessionWithUser, err := service.validateSession(ctx, sessionId)
+	if err != nil {
+		return web.SessionResponse{}, web.UserResponse{}, err
+	}
+
+	return helper.ToSessionResponse(sessionWithUser.Session), helper.ToUserResponse(sessionWithUser.User), nil
+}
+
+func (service *AuthServiceImpl) PermissionValidateSession(ctx context.Context, sessionId string, permission domain.Permission) (web.SessionResponse, web.UserResponse, error) {
+	sessionWithUser, err := service.validateSession(ctx, sessionId)
+	if err != nil {
+		return web.SessionResponse{}, web.UserResponse{}, err
+	}
+
+	// Check if user's role is granted the permission
+	if !helper.HasPermission(sessionWithUser.User.Role, permission) {
+		return web.SessionResponse{}, web.UserResponse{}, appError.NewAppError(
 			http.StatusForbidden,
 			"Forbidden access",
 			"You do not have permission to access this resource",
-			fmt.Errorf("%w: user with id '%v' is not an admin", appError.ErrForbiddenAccess, user.ID),
+			fmt.Errorf("%w: user with id '%v' and role '%v' lacks permission '%v'", appError.ErrForbiddenAccess, sessionWithUser.User.Id, sessionWithUser.User.Role, permission),
 		)
 	}
 
+	return helper.ToSessionResponse(sessionWithUser.Session), helper.ToUserResponse(sessionWithUser.User), nil
+}
+
+// validateSession resolves the session and its user with a single query,
+// serving repeated lookups from the session cache
+func (service *AuthServiceImpl) validateSession(ctx context.Context, sessionId string) (domain.SessionWithUser, error) {
+	sessionWithUser, ok := service.SessionCache.Get(sessionId)
+	if !ok {
+		// Open Transaction
+		tx, err := service.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return domain.SessionWithUser{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			)
+		}
+		defer helper.RollbackQuietly(tx)
+
+		// Get session together with its user
+		sessionWithUser, err = service.AuthRepository.GetSessionWithUserById(ctx, tx, sessionId)
+		if err != nil {
+			return domain.SessionWithUser{}, appError.NewAppError(
+				http.StatusUnauthorized,
+				"Invalid session data",
+				"Session data may be corrupted, missing or expired",
+				fmt.Errorf("%w: session with id '%v' is not found", appError.ErrSessionNotFound, sessionId),
+			)
+		}
+
+		// Commit transaction
+		if err = tx.Commit(); err != nil {
+			return domain.SessionWithUser{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("transaction commit failed: %w", err),
+			)
+		}
+	}
+
 	// Validate session
+	session := sessionWithUser.Session
 	if session.CreatedAt.Add(time.Second * time.Duration(session.MaxAgeSeconds)).Before(time.Now()) {
-		return web.SessionResponse{}, appError.NewAppError(
+		service.SessionCache.Delete(sessionId)
+
+		return domain.SessionWithUser{}, appError.NewAppError(
 			http.StatusUnauthorized,
 			"Invalid session data",
 			"Session data may be corrupted, missing or expired",
@@ -332,15 +459,21 @@ func (service *AuthServiceImpl) AdminValidateSession(ctx context.Context, sessio
 		)
 	}
 
-	// Commit transcation
-	if err = tx.Commit(); err != nil {
-		return web.SessionResponse{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to commit transaction",
-			fmt.Errorf("transaction commit failed: %w", err),
+	// Sessions of deactivated users are rejected even if they were not deleted
+	if sessionWithUser.User.DeactivatedAt != nil {
+		service.SessionCache.Delete(sessionId)
+
+		return domain.SessionWithUser{}, appError.NewAppError(
+			http.StatusForbidden,
+			"Account deactivated",
+			"Your account has been deactivated. Please contact the committee.",
+			fmt.Errorf("%w: user with id '%v' of session", appError.ErrUserDeactivated, sessionWithUser.User.Id),
 		)
 	}
 
-	return helper.ToSessionResponse(session), nil
+	if !ok {
+		service.SessionCache.Set(sessionId, sessionWithUser)
+	}
+
+	return sessionWithUser, nil
 }
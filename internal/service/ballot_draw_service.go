@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+type BallotDrawService interface {
+	GetVotingPeriod(ctx context.Context, period string) (web.VotingPeriodResponse, error)
+	SetVotingPeriod(ctx context.Context, actor web.UserResponse, period string, request web.VotingPeriodRequest) (web.VotingPeriodResponse, error)
+	GetDraws(ctx context.Context, period string) ([]web.BallotDrawResponse, error)
+	Commit(ctx context.Context, actor web.UserResponse, period string, request web.BallotDrawCommitRequest) (web.BallotDrawResponse, error)
+	Reveal(ctx context.Context, actor web.UserResponse, period string, request web.BallotDrawRevealRequest) (web.BallotDrawResponse, error)
+	Cancel(ctx context.Context, actor web.UserResponse, period string) (web.BallotDrawResponse, error)
+}
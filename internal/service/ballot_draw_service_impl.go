@@ -0,0 +1,652 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/ballot"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+)
+
+func NewBallotDrawService(ballotDrawRepository repository.BallotDrawRepository, votingPeriodRepository repository.VotingPeriodRepository, candidateRepository repository.CandidateRepository, candidateRevisionRepository repository.CandidateRevisionRepository, candidateListCache *cache.CandidateListCache, db *sql.DB, validate *validator.Validate) BallotDrawService {
+	return &BallotDrawServiceImpl{
+		BallotDrawRepository:        ballotDrawRepository,
+		VotingPeriodRepository:      votingPeriodRepository,
+		CandidateRepository:         candidateRepository,
+		CandidateRevisionRepository: candidateRevisionRepository,
+		CandidateListCache:          candidateListCache,
+		DB:                          db,
+		Validate:                    validate,
+	}
+}
+
+type BallotDrawServiceImpl struct {
+	BallotDrawRepository        repository.BallotDrawRepository
+	VotingPeriodRepository      repository.VotingPeriodRepository
+	CandidateRepository         repository.CandidateRepository
+	CandidateRevisionRepository repository.CandidateRevisionRepository
+	CandidateListCache          *cache.CandidateListCache
+	DB                          *sql.DB
+	Validate                    *validator.Validate
+}
+
+func (service *BallotDrawServiceImpl) GetVotingPeriod(ctx context.Context, period string) (web.VotingPeriodResponse, error) {
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return web.VotingPeriodResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	votingPeriod, err := service.VotingPeriodRepository.GetByPeriod(ctx, tx, periodInt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return web.VotingPeriodResponse{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Voting period not found",
+				fmt.Sprintf("Voting of period %v is not scheduled", periodInt),
+				fmt.Errorf("voting period %v not found: %w", periodInt, err),
+			)
+		}
+
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get voting period %v: %w", periodInt, err),
+		)
+	}
+
+	started, err := service.VotingPeriodRepository.IsVotingStarted(ctx, tx, periodInt)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", periodInt, err),
+		)
+	}
+
+	return helper.ToVotingPeriodResponse(votingPeriod, started), nil
+}
+
+func (service *BallotDrawServiceImpl) SetVotingPeriod(ctx context.Context, actor web.UserResponse, period string, request web.VotingPeriodRequest) (web.VotingPeriodResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return web.VotingPeriodResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	started, err := service.VotingPeriodRepository.IsVotingStarted(ctx, tx, periodInt)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", periodInt, err),
+		)
+	}
+
+	// Once voting has started only the closing time may be moved
+	if started {
+		current, err := service.VotingPeriodRepository.GetByPeriod(ctx, tx, periodInt)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return web.VotingPeriodResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to get voting period %v: %w", periodInt, err),
+			)
+		}
+
+		if err != nil || !current.OpensAt.Equal(request.OpensAt) {
+			return web.VotingPeriodResponse{}, appError.NewAppError(
+				http.StatusConflict,
+				"Voting has started",
+				fmt.Sprintf("Voting of period %v has started, only the closing time can be changed", periodInt),
+				fmt.Errorf("%w: period %v", appError.ErrVotingStarted, periodInt),
+			)
+		}
+	}
+
+	votingPeriod, err := service.VotingPeriodRepository.Save(ctx, tx, domain.VotingPeriod{
+		Period:    periodInt,
+		OpensAt:   request.OpensAt,
+		ClosesAt:  request.ClosesAt,
+		UpdatedBy: &actor.ID,
+	})
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save voting period %v: %w", periodInt, err),
+		)
+	}
+
+	// The new opening time may already have passed
+	started, err = service.VotingPeriodRepository.IsVotingStarted(ctx, tx, periodInt)
+	if err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", periodInt, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.VotingPeriodResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToVotingPeriodResponse(votingPeriod, started), nil
+}
+
+func (service *BallotDrawServiceImpl) GetDraws(ctx context.Context, period string) ([]web.BallotDrawResponse, error) {
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	draws, err := service.BallotDrawRepository.GetByPeriod(ctx, tx, periodInt)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get ballot draws of period %v: %w", periodInt, err),
+		)
+	}
+
+	return helper.ToBallotDrawsResponse(draws), nil
+}
+
+func (service *BallotDrawServiceImpl) Commit(ctx context.Context, actor web.UserResponse, period string, request web.BallotDrawCommitRequest) (web.BallotDrawResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	if err = checkBallotNumbersOpen(ctx, tx, service.VotingPeriodRepository, service.BallotDrawRepository, periodInt); err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	candidateIds, err := service.candidateIds(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+	if len(candidateIds) == 0 {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"No candidates",
+			fmt.Sprintf("Period %v has no candidates to draw numbers for", periodInt),
+			fmt.Errorf("no candidates in period %v", periodInt),
+		)
+	}
+
+	secretSeed, err := ballot.NewSecretSeed()
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to generate ballot draw seed: %w", err),
+		)
+	}
+
+	draw, err := service.BallotDrawRepository.Save(ctx, tx, domain.BallotDraw{
+		Period:           periodInt,
+		CandidateIds:     candidateIds,
+		SecretSeed:       secretSeed,
+		Commitment:       ballot.Commitment(secretSeed),
+		PublicSeedSource: request.PublicSeedSource,
+		CommittedBy:      &actor.ID,
+	})
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save ballot draw of period %v: %w", periodInt, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToBallotDrawResponse(draw), nil
+}
+
+func (service *BallotDrawServiceImpl) Reveal(ctx context.Context, actor web.UserResponse, period string, request web.BallotDrawRevealRequest) (web.BallotDrawResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	draw, err := service.getCommittedDraw(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	// The user who knows the secret seed must not also pick the public seed
+	if draw.CommittedBy != nil && *draw.CommittedBy == actor.ID {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden",
+			"A ballot draw must be revealed by a different user than the one who committed it",
+			fmt.Errorf("%w: user %v revealing own ballot draw %v", appError.ErrForbiddenAccess, actor.ID, draw.Id),
+		)
+	}
+
+	started, err := service.VotingPeriodRepository.IsVotingStarted(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", periodInt, err),
+		)
+	}
+	if started {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Voting has started",
+			fmt.Sprintf("Ballot numbers of period %v are locked because voting has started", periodInt),
+			fmt.Errorf("%w: period %v", appError.ErrVotingStarted, periodInt),
+		)
+	}
+
+	// The committed order is only valid for the committed candidates
+	candidateIds, err := service.candidateIds(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+	previousNumbers, err := service.candidateNumbers(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+	if !slices.Equal(candidateIds, draw.CandidateIds) {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Candidates changed",
+			"Candidates were added or removed after the draw was committed, cancel it and commit a new draw",
+			fmt.Errorf("candidates of period %v are %v, ballot draw %v committed %v", periodInt, candidateIds, draw.Id, draw.CandidateIds),
+		)
+	}
+
+	finalSeed := ballot.FinalSeed(draw.SecretSeed, request.PublicSeed)
+	for i, candidateId := range ballot.Order(finalSeed, draw.CandidateIds) {
+		draw.Result = append(draw.Result, domain.BallotDrawNumber{
+			CandidateId: candidateId,
+			Number:      i + 1,
+		})
+	}
+	draw.PublicSeed = request.PublicSeed
+	draw.RevealedBy = &actor.ID
+
+	if err = service.CandidateRepository.UpdateNumbers(ctx, tx, draw.Result); err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update candidate numbers of period %v: %w", periodInt, err),
+		)
+	}
+
+	// Every renumbered candidate gets a revision pointing at the draw
+	for _, number := range draw.Result {
+		if previousNumbers[number.CandidateId] == number.Number {
+			continue
+		}
+
+		err = saveCandidateRevision(ctx, tx, service.CandidateRevisionRepository, domain.CandidateRevision{
+			CandidateId: number.CandidateId,
+			Changes: map[string]domain.CandidateFieldChange{
+				"number": {From: previousNumbers[number.CandidateId], To: number.Number},
+			},
+			Reason:  fmt.Sprintf("Ballot draw %d", draw.Id),
+			ActorId: &actor.ID,
+		})
+		if err != nil {
+			return web.BallotDrawResponse{}, err
+		}
+	}
+
+	draw, err = service.BallotDrawRepository.Reveal(ctx, tx, draw)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to reveal ballot draw %v: %w", draw.Id, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.CandidateListCache.Clear()
+
+	return helper.ToBallotDrawResponse(draw), nil
+}
+
+func (service *BallotDrawServiceImpl) Cancel(ctx context.Context, actor web.UserResponse, period string) (web.BallotDrawResponse, error) {
+	periodInt, err := parsePeriod(period)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	draw, err := service.getCommittedDraw(ctx, tx, periodInt)
+	if err != nil {
+		return web.BallotDrawResponse{}, err
+	}
+
+	draw, err = service.BallotDrawRepository.Cancel(ctx, tx, draw.Id, actor.ID)
+	if err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to cancel ballot draw %v: %w", draw.Id, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.BallotDrawResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToBallotDrawResponse(draw), nil
+}
+
+// getCommittedDraw locks the draw of the period that is waiting to be revealed
+func (service *BallotDrawServiceImpl) getCommittedDraw(ctx context.Context, tx *sql.Tx, period int) (domain.BallotDraw, error) {
+	draw, err := service.BallotDrawRepository.GetActiveByPeriodForUpdate(ctx, tx, period)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.BallotDraw{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Ballot draw not found",
+				fmt.Sprintf("Period %v has no committed ballot draw", period),
+				fmt.Errorf("no committed ballot draw in period %v: %w", period, err),
+			)
+		}
+
+		return domain.BallotDraw{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get ballot draw of period %v: %w", period, err),
+		)
+	}
+
+	if draw.Status == domain.BallotDrawStatusRevealed {
+		return domain.BallotDraw{}, appError.NewAppError(
+			http.StatusConflict,
+			"Ballot numbers are drawn",
+			fmt.Sprintf("The ballot draw of period %v is revealed and final", period),
+			fmt.Errorf("%w: ballot draw %v", appError.ErrBallotNumbersDrawn, draw.Id),
+		)
+	}
+
+	return draw, nil
+}
+
+// candidateIds returns the ids of the candidates of the period in ascending order
+func (service *BallotDrawServiceImpl) candidateIds(ctx context.Context, tx *sql.Tx, period int) ([]int, error) {
+	candidates, err := service.CandidateRepository.GetByPeriod(ctx, tx, period)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidates of period %v: %w", period, err),
+		)
+	}
+
+	candidateIds := make([]int, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidateIds = append(candidateIds, candidate.Id)
+	}
+	slices.Sort(candidateIds)
+
+	return candidateIds, nil
+}
+
+// candidateNumbers returns the current number of every candidate of the period by id
+func (service *BallotDrawServiceImpl) candidateNumbers(ctx context.Context, tx *sql.Tx, period int) (map[int]int, error) {
+	candidates, err := service.CandidateRepository.GetByPeriod(ctx, tx, period)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidates of period %v: %w", period, err),
+		)
+	}
+
+	numbers := make(map[int]int, len(candidates))
+	for _, candidate := range candidates {
+		numbers[candidate.Id] = candidate.Number
+	}
+
+	return numbers, nil
+}
+
+// checkBallotNumbersOpen returns a conflict once ballot numbers of the period
+// may no longer be assigned by hand: voting has started, or a draw is
+// committed or revealed. Otherwise the numbers of the period stay locked
+// until the transaction ends, so the numbers the caller reads are still free
+// when it saves.
+func checkBallotNumbersOpen(ctx context.Context, tx *sql.Tx, votingPeriodRepository repository.VotingPeriodRepository, ballotDrawRepository repository.BallotDrawRepository, period int) error {
+	if err := ballotDrawRepository.LockNumbers(ctx, tx, period); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to lock ballot numbers of period %v: %w", period, err),
+		)
+	}
+
+	started, err := votingPeriodRepository.IsVotingStarted(ctx, tx, period)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", period, err),
+		)
+	}
+	if started {
+		return appError.NewAppError(
+			http.StatusConflict,
+			"Voting has started",
+			fmt.Sprintf("Ballot numbers of period %v are locked because voting has started", period),
+			fmt.Errorf("%w: period %v", appError.ErrVotingStarted, period),
+		)
+	}
+
+	draws, err := ballotDrawRepository.GetByPeriod(ctx, tx, period)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get ballot draws of period %v: %w", period, err),
+		)
+	}
+
+	for _, draw := range draws {
+		switch draw.Status {
+		case domain.BallotDrawStatusCommitted:
+			return appError.NewAppError(
+				http.StatusConflict,
+				"Ballot draw in progress",
+				fmt.Sprintf("A ballot draw of period %v is committed, reveal or cancel it first", period),
+				fmt.Errorf("ballot draw %v of period %v is committed", draw.Id, period),
+			)
+		case domain.BallotDrawStatusRevealed:
+			return appError.NewAppError(
+				http.StatusConflict,
+				"Ballot numbers are drawn",
+				fmt.Sprintf("Ballot numbers of period %v were drawn and can't be changed", period),
+				fmt.Errorf("%w: ballot draw %v", appError.ErrBallotNumbersDrawn, draw.Id),
+			)
+		}
+	}
+
+	return nil
+}
+
+// parsePeriod validates a period path parameter
+func parsePeriod(period string) (int, error) {
+	periodInt, err := strconv.Atoi(period)
+	if err != nil || periodInt <= 0 || len(period) > 4 {
+		return 0, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request",
+			"Period must be a positive number and can't exceed 4 digits",
+			fmt.Errorf("%w: %s", appError.ErrInvalidPeriodRange, period),
+		)
+	}
+
+	return periodInt, nil
+}
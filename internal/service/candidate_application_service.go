@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+type CandidateApplicationService interface {
+	Submit(ctx context.Context, current web.UserResponse, request web.CandidateApplicationCreateRequest) (web.CandidateApplicationResponse, error)
+	GetCurrent(ctx context.Context, userId int) ([]web.CandidateApplicationResponse, error)
+	GetAll(ctx context.Context, period, status string) ([]web.CandidateApplicationResponse, error)
+	GetById(ctx context.Context, applicationId int) (web.CandidateApplicationResponse, error)
+	Comment(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationCommentRequest) (web.CandidateApplicationResponse, error)
+	Verify(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationReviewRequest) (web.CandidateApplicationResponse, error)
+	Reject(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationCommentRequest) (web.CandidateApplicationResponse, error)
+	Approve(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationApproveRequest) (web.CandidateApplicationResponse, error)
+}
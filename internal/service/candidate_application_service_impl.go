@@ -0,0 +1,766 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/storage"
+)
+
+func NewCandidateApplicationService(candidateApplicationRepository repository.CandidateApplicationRepository, candidateRepository repository.CandidateRepository, userRepository repository.UserRepository, votingPeriodRepository repository.VotingPeriodRepository, ballotDrawRepository repository.BallotDrawRepository, candidateRevisionRepository repository.CandidateRevisionRepository, objectStore storage.ObjectStore, candidateListCache *cache.CandidateListCache, db *sql.DB, validate *validator.Validate) CandidateApplicationService {
+	return &CandidateApplicationServiceImpl{
+		CandidateApplicationRepository: candidateApplicationRepository,
+		CandidateRepository:            candidateRepository,
+		UserRepository:                 userRepository,
+		VotingPeriodRepository:         votingPeriodRepository,
+		BallotDrawRepository:           ballotDrawRepository,
+		CandidateRevisionRepository:    candidateRevisionRepository,
+		ObjectStore:                    objectStore,
+		CandidateListCache:             candidateListCache,
+		DB:                             db,
+		Validate:                       validate,
+	}
+}
+
+type CandidateApplicationServiceImpl struct {
+	CandidateApplicationRepository repository.CandidateApplicationRepository
+	CandidateRepository            repository.CandidateRepository
+	UserRepository                 repository.UserRepository
+	VotingPeriodRepository         repository.VotingPeriodRepository
+	BallotDrawRepository           repository.BallotDrawRepository
+	CandidateRevisionRepository    repository.CandidateRevisionRepository
+	ObjectStore                    storage.ObjectStore
+	CandidateListCache             *cache.CandidateListCache
+	DB                             *sql.DB
+	Validate                       *validator.Validate
+}
+
+// Submit files a candidacy application for the current period. The current
+// user must be the president or vice of the pair.
+func (service *CandidateApplicationServiceImpl) Submit(ctx context.Context, current web.UserResponse, request web.CandidateApplicationCreateRequest) (web.CandidateApplicationResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	if request.PresidentNIM == request.ViceNIM {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"President NIM and vice NIM must be different",
+			fmt.Errorf("%w: president and vice NIM are both %s", appError.ErrValidation, request.PresidentNIM),
+		)
+	}
+
+	if current.NIM != request.PresidentNIM && current.NIM != request.ViceNIM {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden",
+			"You can only submit an application where you are the president or the vice",
+			fmt.Errorf("%w: user %v applied for another pair", appError.ErrForbiddenAccess, current.ID),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Both NIMs must belong to active students
+	users, err := service.UserRepository.GetByNIMs(ctx, tx, []string{request.PresidentNIM, request.ViceNIM})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by NIM: %w", err),
+		)
+	}
+
+	usersByNIM := make(map[string]domain.User, len(users))
+	for _, user := range users {
+		usersByNIM[user.NIM] = user
+	}
+
+	for _, nim := range []string{request.PresidentNIM, request.ViceNIM} {
+		user, ok := usersByNIM[nim]
+		if !ok || user.Role != domain.RoleStudent || user.DeactivatedAt != nil {
+			return web.CandidateApplicationResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("NIM '%s' does not belong to an active student", nim),
+				fmt.Errorf("%w: %s", appError.ErrNIMNotFound, nim),
+			)
+		}
+	}
+	president, vice := usersByNIM[request.PresidentNIM], usersByNIM[request.ViceNIM]
+
+	// Candidates keep their NIM, so they can't apply again
+	candidates, err := service.CandidateRepository.GetAll(ctx, tx)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get all candidates: %w", err),
+		)
+	}
+
+	for _, candidate := range candidates {
+		for _, nim := range []string{request.PresidentNIM, request.ViceNIM} {
+			if nim == candidate.PresidentNIM || nim == candidate.ViceNIM {
+				return web.CandidateApplicationResponse{}, appError.NewAppError(
+					http.StatusConflict,
+					"Already a candidate",
+					fmt.Sprintf("NIM '%s' is already a candidate", nim),
+					fmt.Errorf("%w: %s", appError.ErrNIMAlreadyExists, nim),
+				)
+			}
+		}
+	}
+
+	// A student can be in one open application per period
+	period := time.Now().Year()
+
+	openApplications, err := service.CandidateApplicationRepository.GetOpenByUserIds(ctx, tx, period, []int{president.Id, vice.Id})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get open candidate applications: %w", err),
+		)
+	}
+
+	if len(openApplications) > 0 {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Application already exists",
+			fmt.Sprintf("The president or vice already has an application in period %v (id %v)", period, openApplications[0].Id),
+			fmt.Errorf("open candidate application %v exists", openApplications[0].Id),
+		)
+	}
+
+	application, err := service.CandidateApplicationRepository.Save(ctx, tx, domain.CandidateApplication{
+		Period:      period,
+		PresidentId: president.Id,
+		ViceId:      vice.Id,
+		Vision:      request.Vision,
+		Mission:     request.Mission,
+		SubmittedBy: &current.ID,
+	})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save candidate application: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToCandidateApplicationResponse(application, nil), nil
+}
+
+func (service *CandidateApplicationServiceImpl) GetCurrent(ctx context.Context, userId int) ([]web.CandidateApplicationResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	applications, err := service.CandidateApplicationRepository.GetByUserId(ctx, tx, userId)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate applications of user %v: %w", userId, err),
+		)
+	}
+
+	return service.toResponses(ctx, tx, applications)
+}
+
+func (service *CandidateApplicationServiceImpl) GetAll(ctx context.Context, period, status string) ([]web.CandidateApplicationResponse, error) {
+	filter := domain.CandidateApplicationFilter{Status: status}
+
+	switch status {
+	case "", domain.CandidateApplicationStatusSubmitted, domain.CandidateApplicationStatusVerified,
+		domain.CandidateApplicationStatusRejected, domain.CandidateApplicationStatusApproved:
+	default:
+		return nil, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request",
+			fmt.Sprintf("Status must be one of submitted, verified, rejected or approved, got '%s'", status),
+			fmt.Errorf("%w: invalid candidate application status %s", appError.ErrValidation, status),
+		)
+	}
+
+	if period != "" {
+		periodInt, err := strconv.Atoi(period)
+		if err != nil || periodInt <= 0 || len(period) > 4 {
+			return nil, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request",
+				"Period must be a positive number and can't exceed 4 digits",
+				fmt.Errorf("%w: %s", appError.ErrInvalidPeriodRange, period),
+			)
+		}
+		filter.Period = periodInt
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	applications, err := service.CandidateApplicationRepository.GetAll(ctx, tx, filter)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate applications: %w", err),
+		)
+	}
+
+	return service.toResponses(ctx, tx, applications)
+}
+
+func (service *CandidateApplicationServiceImpl) GetById(ctx context.Context, applicationId int) (web.CandidateApplicationResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	application, err := service.CandidateApplicationRepository.GetById(ctx, tx, applicationId)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, applicationLookupError(applicationId, err)
+	}
+
+	return service.toResponse(ctx, tx, application)
+}
+
+// Comment adds a committee note without changing the status
+func (service *CandidateApplicationServiceImpl) Comment(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationCommentRequest) (web.CandidateApplicationResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	application, err := service.CandidateApplicationRepository.GetById(ctx, tx, applicationId)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, applicationLookupError(applicationId, err)
+	}
+
+	_, err = service.CandidateApplicationRepository.SaveComment(ctx, tx, domain.CandidateApplicationComment{
+		ApplicationId: applicationId,
+		AuthorId:      &actor.ID,
+		Body:          request.Comment,
+	})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save comment on candidate application %v: %w", applicationId, err),
+		)
+	}
+
+	response, err := service.toResponse(ctx, tx, application)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return response, nil
+}
+
+// Verify marks a submitted application as checked by the committee
+func (service *CandidateApplicationServiceImpl) Verify(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationReviewRequest) (web.CandidateApplicationResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	application, err := service.getApplicationForReview(ctx, tx, actor, applicationId, domain.CandidateApplicationStatusSubmitted)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	response, err := service.moveTo(ctx, tx, actor, application, domain.CandidateApplicationStatusVerified, nil, request.Comment)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return response, nil
+}
+
+// Reject closes a submitted or verified application. The comment tells the
+// students why.
+func (service *CandidateApplicationServiceImpl) Reject(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationCommentRequest) (web.CandidateApplicationResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	application, err := service.getApplicationForReview(ctx, tx, actor, applicationId, domain.CandidateApplicationStatusSubmitted, domain.CandidateApplicationStatusVerified)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	response, err := service.moveTo(ctx, tx, actor, application, domain.CandidateApplicationStatusRejected, nil, request.Comment)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return response, nil
+}
+
+// Approve turns a verified application into a candidate of the current
+// period with a randomly drawn ballot number
+func (service *CandidateApplicationServiceImpl) Approve(ctx context.Context, actor web.UserResponse, applicationId int, request web.CandidateApplicationApproveRequest) (web.CandidateApplicationResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Photo must be confirmed through the upload API
+	if err = checkCandidatePhotoKey(ctx, service.ObjectStore, request.PhotoKey); err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	application, err := service.getApplicationForReview(ctx, tx, actor, applicationId, domain.CandidateApplicationStatusVerified)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// The period of a candidate is the year it is created in
+	currentPeriod := time.Now().Year()
+	if application.Period != currentPeriod {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Application period has ended",
+			fmt.Sprintf("Application with id %v is for period %v and can't be approved in period %v", applicationId, application.Period, currentPeriod),
+			fmt.Errorf("candidate application %v is for period %v", applicationId, application.Period),
+		)
+	}
+
+	// Approving gives out a number, which is no longer possible once numbers
+	// are drawn or voting has started. This also locks the numbers of the
+	// period, so concurrent approvals can't draw the same one.
+	if err = checkBallotNumbersOpen(ctx, tx, service.VotingPeriodRepository, service.BallotDrawRepository, currentPeriod); err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Get all candidates for the shake of validation
+	candidates, err := service.CandidateRepository.GetAll(ctx, tx)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get all candidates: %w", err),
+		)
+	}
+
+	takenNumbers := make(map[int]bool)
+	for _, candidate := range candidates {
+		if candidate.CreatedAt.Year() == currentPeriod {
+			takenNumbers[candidate.Number] = true
+		}
+
+		// The users may have been added as a candidate by an admin in the meantime
+		for _, nim := range []string{application.PresidentNIM, application.ViceNIM} {
+			if nim == candidate.PresidentNIM || nim == candidate.ViceNIM {
+				return web.CandidateApplicationResponse{}, appError.NewAppError(
+					http.StatusConflict,
+					"Already a candidate",
+					fmt.Sprintf("NIM '%s' is already a candidate", nim),
+					fmt.Errorf("%w: %s", appError.ErrNIMAlreadyExists, nim),
+				)
+			}
+		}
+
+		// Photo_key must be unique
+		if request.PhotoKey == candidate.PhotoKey {
+			return web.CandidateApplicationResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Photo key '%s' is already used, please choose another photo key", request.PhotoKey),
+				fmt.Errorf("%w", appError.ErrPhotoKeyIsUsed),
+			)
+		}
+	}
+
+	// Numbers are drawn from as many slots as there are pairs that can still
+	// be approved, so the order of approval doesn't decide the number. Pairs
+	// rejected later leave gaps, which the ballot draw closes by numbering
+	// every candidate of the period from 1.
+	pairs, err := service.CandidateApplicationRepository.CountByStatuses(ctx, tx, currentPeriod, []string{domain.CandidateApplicationStatusVerified, domain.CandidateApplicationStatusApproved})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to count candidate applications: %w", err),
+		)
+	}
+
+	number, err := drawBallotNumber(takenNumbers, max(pairs, len(takenNumbers)+1))
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to draw ballot number: %w", err),
+		)
+	}
+
+	candidate, err := service.CandidateRepository.Save(ctx, tx, domain.Candidate{
+		Number:                number,
+		President:             application.President,
+		Vice:                  application.Vice,
+		Vision:                application.Vision,
+		Mission:               application.Mission,
+		PhotoKey:              request.PhotoKey,
+		PresidentStudyProgram: application.PresidentStudyProgram,
+		ViceStudyProgram:      application.ViceStudyProgram,
+		PresidentNIM:          application.PresidentNIM,
+		ViceNIM:               application.ViceNIM,
+	})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to create candidate: %w", err),
+		)
+	}
+
+	// The first revision holds the initial values, including the drawn number
+	err = saveCandidateRevision(ctx, tx, service.CandidateRevisionRepository, domain.CandidateRevision{
+		CandidateId: candidate.Id,
+		Changes:     candidateChanges(domain.Candidate{}, candidate),
+		Reason:      fmt.Sprintf("Candidate application %d approved", application.Id),
+		ActorId:     &actor.ID,
+	})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	response, err := service.moveTo(ctx, tx, actor, application, domain.CandidateApplicationStatusApproved, &candidate.Id, request.Comment)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.CandidateListCache.Clear()
+
+	return response, nil
+}
+
+// getApplicationForReview locks an application that is in one of the given
+// statuses. Reviewers can't review an application they are part of.
+func (service *CandidateApplicationServiceImpl) getApplicationForReview(ctx context.Context, tx *sql.Tx, actor web.UserResponse, applicationId int, statuses ...string) (domain.CandidateApplication, error) {
+	application, err := service.CandidateApplicationRepository.GetByIdForUpdate(ctx, tx, applicationId)
+	if err != nil {
+		return domain.CandidateApplication{}, applicationLookupError(applicationId, err)
+	}
+
+	if !slices.Contains(statuses, application.Status) {
+		return domain.CandidateApplication{}, appError.NewAppError(
+			http.StatusConflict,
+			"Invalid application status",
+			fmt.Sprintf("Application with id %v is %s", applicationId, application.Status),
+			fmt.Errorf("candidate application %v is %s, expected one of %v", applicationId, application.Status, statuses),
+		)
+	}
+
+	if application.PresidentId == actor.ID || application.ViceId == actor.ID {
+		return domain.CandidateApplication{}, appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden",
+			"You can't review your own application",
+			fmt.Errorf("%w: user %v reviewed own candidate application %v", appError.ErrForbiddenAccess, actor.ID, applicationId),
+		)
+	}
+
+	return application, nil
+}
+
+// moveTo changes the status of the application and records it as a comment
+func (service *CandidateApplicationServiceImpl) moveTo(ctx context.Context, tx *sql.Tx, actor web.UserResponse, application domain.CandidateApplication, status string, candidateId *int, comment string) (web.CandidateApplicationResponse, error) {
+	application, err := service.CandidateApplicationRepository.UpdateStatus(ctx, tx, application.Id, status, candidateId)
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update candidate application %v to %s: %w", application.Id, status, err),
+		)
+	}
+
+	_, err = service.CandidateApplicationRepository.SaveComment(ctx, tx, domain.CandidateApplicationComment{
+		ApplicationId: application.Id,
+		AuthorId:      &actor.ID,
+		Status:        status,
+		Body:          comment,
+	})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save comment on candidate application %v: %w", application.Id, err),
+		)
+	}
+
+	return service.toResponse(ctx, tx, application)
+}
+
+func (service *CandidateApplicationServiceImpl) toResponse(ctx context.Context, tx *sql.Tx, application domain.CandidateApplication) (web.CandidateApplicationResponse, error) {
+	responses, err := service.toResponses(ctx, tx, []domain.CandidateApplication{application})
+	if err != nil {
+		return web.CandidateApplicationResponse{}, err
+	}
+
+	return responses[0], nil
+}
+
+// toResponses attaches the comments of every application with a single query
+func (service *CandidateApplicationServiceImpl) toResponses(ctx context.Context, tx *sql.Tx, applications []domain.CandidateApplication) ([]web.CandidateApplicationResponse, error) {
+	applicationIds := make([]int, 0, len(applications))
+	for _, application := range applications {
+		applicationIds = append(applicationIds, application.Id)
+	}
+
+	comments, err := service.CandidateApplicationRepository.GetComments(ctx, tx, applicationIds)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate application comments: %w", err),
+		)
+	}
+
+	commentsByApplication := make(map[int][]domain.CandidateApplicationComment, len(applications))
+	for _, comment := range comments {
+		commentsByApplication[comment.ApplicationId] = append(commentsByApplication[comment.ApplicationId], comment)
+	}
+
+	responses := make([]web.CandidateApplicationResponse, 0, len(applications))
+	for _, application := range applications {
+		responses = append(responses, helper.ToCandidateApplicationResponse(application, commentsByApplication[application.Id]))
+	}
+
+	return responses, nil
+}
+
+func applicationLookupError(applicationId int, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return appError.NewAppError(
+			http.StatusNotFound,
+			"Application not found",
+			fmt.Sprintf("Candidate application with id %v does not exist", applicationId),
+			fmt.Errorf("candidate application with id %v not found: %w", applicationId, err),
+		)
+	}
+
+	return appError.NewAppError(
+		http.StatusInternalServerError,
+		"Internal Server Error",
+		"Failed to process your request due to an unexpected error. Please try again later.",
+		fmt.Errorf("failed to get candidate application %v: %w", applicationId, err),
+	)
+}
+
+// drawBallotNumber picks a free number between 1 and slots uniformly at
+// random. slots must be larger than the number of taken numbers.
+func drawBallotNumber(taken map[int]bool, slots int) (int, error) {
+	free := []int{}
+	for number := 1; number <= slots; number++ {
+		if !taken[number] {
+			free = append(free, number)
+		}
+	}
+	if len(free) == 0 {
+		return 0, fmt.Errorf("no free ballot number in 1..%d", slots)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(free))))
+	if err != nil {
+		return 0, err
+	}
+
+	return free[n.Int64()], nil
+}
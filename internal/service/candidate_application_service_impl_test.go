@@ -0,0 +1,63 @@
+package service
+
+import "testing"
+
+func TestDrawBallotNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken map[int]bool
+		slots int
+	}{
+		{"nothing taken", map[int]bool{}, 4},
+		{"nil taken", nil, 3},
+		{"some taken", map[int]bool{1: true, 3: true}, 4},
+		{"one free", map[int]bool{1: true, 2: true, 4: true}, 4},
+		{"taken beyond slots", map[int]bool{2: true, 7: true}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Draw often enough that a taken or out of range number would show up
+			seen := map[int]bool{}
+			for i := 0; i < 200; i++ {
+				number, err := drawBallotNumber(tt.taken, tt.slots)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if number < 1 || number > tt.slots {
+					t.Fatalf("drew %d, want a number between 1 and %d", number, tt.slots)
+				}
+				if tt.taken[number] {
+					t.Fatalf("drew taken number %d", number)
+				}
+				seen[number] = true
+			}
+
+			// Every free number can be drawn
+			for number := 1; number <= tt.slots; number++ {
+				if !tt.taken[number] && !seen[number] {
+					t.Errorf("free number %d was never drawn", number)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawBallotNumberNoFreeNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken map[int]bool
+		slots int
+	}{
+		{"all taken", map[int]bool{1: true, 2: true}, 2},
+		{"no slots", map[int]bool{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if number, err := drawBallotNumber(tt.taken, tt.slots); err == nil {
+				t.Errorf("drawBallotNumber = %d, want an error", number)
+			}
+		})
+	}
+}
@@ -7,9 +7,16 @@ import (
 )
 
 type CandidateService interface {
-	Create(ctx context.Context, request web.CandidateCreateRequest) (web.CandidateResponse, error)
+	Create(ctx context.Context, actor web.UserResponse, request web.CandidateCreateRequest) (web.CandidateResponse, error)
 	GetCandidates(ctx context.Context, period string) ([]web.CandidateResponseWithURL, error)
 	GetCandidateById(ctx context.Context, candidateId int) (web.CandidateResponseWithURL, error)
-	UpdateCandidateById(ctx context.Context, candidateId int, request web.CandidateUpdateRequest) (web.CandidateResponse, error)
+	UpdateCandidateById(ctx context.Context, actor web.UserResponse, candidateId int, request web.CandidateUpdateRequest) (web.CandidateResponse, error)
 	DeleteCandidateById(ctx context.Context, candidateId int) error
+	GetHistory(ctx context.Context, candidateId int) ([]web.CandidateRevisionResponse, error)
+	GetAuditLogs(ctx context.Context, candidateId int) ([]web.AuditLogResponse, error)
+	GetMedia(ctx context.Context, candidateId int) ([]web.CandidateMediaResponse, error)
+	CreateMedia(ctx context.Context, candidateId int, request web.CandidateMediaCreateRequest) (web.CandidateMediaResponse, error)
+	UpdateMedia(ctx context.Context, candidateId, mediaId int, request web.CandidateMediaUpdateRequest) (web.CandidateMediaResponse, error)
+	DeleteMedia(ctx context.Context, candidateId, mediaId int) error
+	ReorderMedia(ctx context.Context, candidateId int, request web.CandidateMediaOrderRequest) ([]web.CandidateMediaResponse, error)
 }
@@ -3,46 +3,69 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
 	envConfig "github.com/mhaatha/HIMA-TI-e-Election/internal/config"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/storage"
 )
 
-func NewCandidateService(candidateRepository repository.CandidateRepository, envConfig *envConfig.Config, voteService VoteService, db *sql.DB, validate *validator.Validate) CandidateService {
+// Candidate photo URL rules. Presigned URLs are reused until an hour before
+// they expire, so browsers can cache photos between requests.
+const (
+	CandidatePhotoURLExpiry   = 24 * time.Hour
+	CandidatePhotoURLCacheTTL = CandidatePhotoURLExpiry - time.Hour
+)
+
+func NewCandidateService(candidateRepository repository.CandidateRepository, candidateMediaRepository repository.CandidateMediaRepository, votingPeriodRepository repository.VotingPeriodRepository, ballotDrawRepository repository.BallotDrawRepository, auditLogRepository repository.AuditLogRepository, candidateRevisionRepository repository.CandidateRevisionRepository, objectStore storage.ObjectStore, photoURLCache *cache.PresignedURLCache, candidateListCache *cache.CandidateListCache, envConfig *envConfig.Config, voteService VoteService, db *sql.DB, validate *validator.Validate) CandidateService {
 	return &CandidateServiceImpl{
-		CandidateRepository: candidateRepository,
-		EnvConfig:           envConfig,
-		VoteService:         voteService,
-		DB:                  db,
-		Validate:            validate,
+		CandidateRepository:         candidateRepository,
+		CandidateMediaRepository:    candidateMediaRepository,
+		VotingPeriodRepository:      votingPeriodRepository,
+		BallotDrawRepository:        ballotDrawRepository,
+		AuditLogRepository:          auditLogRepository,
+		CandidateRevisionRepository: candidateRevisionRepository,
+		ObjectStore:                 objectStore,
+		PhotoURLCache:               photoURLCache,
+		CandidateListCache:          candidateListCache,
+		EnvConfig:                   envConfig,
+		VoteService:                 voteService,
+		DB:                          db,
+		Validate:                    validate,
 	}
 }
 
 type CandidateServiceImpl struct {
-	CandidateRepository repository.CandidateRepository
-	EnvConfig           *envConfig.Config
-	VoteService         VoteService
-	DB                  *sql.DB
-	Validate            *validator.Validate
+	CandidateRepository         repository.CandidateRepository
+	CandidateMediaRepository    repository.CandidateMediaRepository
+	VotingPeriodRepository      repository.VotingPeriodRepository
+	BallotDrawRepository        repository.BallotDrawRepository
+	AuditLogRepository          repository.AuditLogRepository
+	CandidateRevisionRepository repository.CandidateRevisionRepository
+	ObjectStore                 storage.ObjectStore
+	PhotoURLCache               *cache.PresignedURLCache
+	CandidateListCache          *cache.CandidateListCache
+	EnvConfig                   *envConfig.Config
+	VoteService                 VoteService
+	DB                          *sql.DB
+	Validate                    *validator.Validate
 }
 
-func (service *CandidateServiceImpl) Create(ctx context.Context, request web.CandidateCreateRequest) (web.CandidateResponse, error) {
+func (service *CandidateServiceImpl) Create(ctx context.Context, actor web.UserResponse, request web.CandidateCreateRequest) (web.CandidateResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
@@ -54,6 +77,11 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 		)
 	}
 
+	// Photo must be confirmed through the upload API
+	if err = checkCandidatePhotoKey(ctx, service.ObjectStore, request.PhotoKey); err != nil {
+		return web.CandidateResponse{}, err
+	}
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -66,6 +94,14 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 	}
 	defer helper.RollbackQuietly(tx)
 
+	// currentPeriod is the current year or period
+	currentPeriod := time.Now().Year()
+
+	// Numbers can't be given out by hand once they are drawn or voting has started
+	if err = checkBallotNumbersOpen(ctx, tx, service.VotingPeriodRepository, service.BallotDrawRepository, currentPeriod); err != nil {
+		return web.CandidateResponse{}, err
+	}
+
 	// Get all candidates for the shake of validation
 	candidates, err := service.CandidateRepository.GetAll(ctx, tx)
 	if err != nil {
@@ -77,9 +113,6 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 		)
 	}
 
-	// currentPeriod is the current year or period
-	currentPeriod := time.Now().Year()
-
 	for _, candidate := range candidates {
 		// currentCandidatePeriod is the year or period of the current candidate
 		currentCandidatePeriod := candidate.CreatedAt.Year()
@@ -125,23 +158,12 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 		}
 	}
 
-	// Create a variable to store the separated values of mission
-	var asterixSeparatedValuesOfMission string
-	// Loop through mission and join it with a asterix(*)
-	for i, mission := range request.Mission {
-		if i == len(request.Mission)-1 {
-			asterixSeparatedValuesOfMission += mission
-		} else {
-			asterixSeparatedValuesOfMission += mission + "*"
-		}
-	}
-
 	candidate := domain.Candidate{
 		Number:                request.Number,
 		President:             request.President,
 		Vice:                  request.Vice,
 		Vision:                request.Vision,
-		Mission:               asterixSeparatedValuesOfMission,
+		Mission:               request.Mission,
 		PhotoKey:              request.PhotoKey,
 		PresidentStudyProgram: request.PresidentStudyProgram,
 		ViceStudyProgram:      request.ViceStudyProgram,
@@ -160,6 +182,16 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 		)
 	}
 
+	// The first revision holds the initial values, including the number
+	err = saveCandidateRevision(ctx, tx, service.CandidateRevisionRepository, domain.CandidateRevision{
+		CandidateId: candidate.Id,
+		Changes:     candidateChanges(domain.Candidate{}, candidate),
+		ActorId:     &actor.ID,
+	})
+	if err != nil {
+		return web.CandidateResponse{}, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return web.CandidateResponse{}, appError.NewAppError(
@@ -170,11 +202,19 @@ func (service *CandidateServiceImpl) Create(ctx context.Context, request web.Can
 		)
 	}
 
+	service.CandidateListCache.Clear()
+
 	// Return candidate with mission as a slice of string for the user
 	return helper.ToCandidateResponse(candidate), nil
 }
 
 func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period string) ([]web.CandidateResponseWithURL, error) {
+	// Every voter loads the list, so it is served from memory until a candidate changes
+	if candidates, ok := service.CandidateListCache.Get(period); ok {
+		return candidates, nil
+	}
+	generation := service.CandidateListCache.Generation()
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -187,28 +227,6 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Create presigned URL for GET
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(service.EnvConfig.S3AccessKeyId, service.EnvConfig.S3SecretAccessKey, "")),
-		config.WithRegion("auto"),
-	)
-	if err != nil {
-		return []web.CandidateResponseWithURL{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err),
-		)
-	}
-
-	// Create S3 client
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(service.EnvConfig.S3URL)
-	})
-
-	// Presigned URL client
-	presignClient := s3.NewPresignClient(client)
-
 	// Validate query params
 	// If period is nil, it means get all candidates
 	// If period is not nil, it means get candidates by specific period
@@ -258,18 +276,10 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 		candidatesWithURL := []domain.CandidateWithURL{}
 
 		for _, candidate := range candidates {
-			// Create presigned URL for GetObject in 24 hours
-			presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-				Bucket: aws.String(service.EnvConfig.S3Bucket),
-				Key:    aws.String(candidate.PhotoKey),
-			}, s3.WithPresignExpires(24*time.Hour))
+			// Get photo URLs, presigned URLs are reused from the cache
+			photoURL, thumbnailURL, err := service.presignPhoto(ctx, candidate.PhotoKey)
 			if err != nil {
-				return []web.CandidateResponseWithURL{}, appError.NewAppError(
-					http.StatusInternalServerError,
-					"Internal Server Error",
-					"Failed to process your request due to an unexpected error. Please try again later.",
-					fmt.Errorf("%w: %v", appError.ErrCreatePresignedPut, err),
-				)
+				return []web.CandidateResponseWithURL{}, err
 			}
 
 			currentCandidateWithURL := domain.CandidateWithURL{
@@ -279,7 +289,8 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 				Vice:                  candidate.Vice,
 				Vision:                candidate.Vision,
 				Mission:               candidate.Mission,
-				PhotoURL:              presignResult.URL,
+				PhotoURL:              photoURL,
+				ThumbnailURL:          thumbnailURL,
 				PresidentStudyProgram: candidate.PresidentStudyProgram,
 				ViceStudyProgram:      candidate.ViceStudyProgram,
 				PresidentNIM:          candidate.PresidentNIM,
@@ -291,7 +302,13 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 			candidatesWithURL = append(candidatesWithURL, currentCandidateWithURL)
 		}
 
-		return helper.ToCandidatesResponseWithURL(candidatesWithURL), nil
+		response := helper.ToCandidatesResponseWithURL(candidatesWithURL)
+		if err = service.attachMedia(ctx, tx, response); err != nil {
+			return []web.CandidateResponseWithURL{}, err
+		}
+		service.CandidateListCache.Set(period, generation, response)
+
+		return response, nil
 	} else {
 		// Get all candidates
 		candidates, err := service.CandidateRepository.GetAll(ctx, tx)
@@ -308,18 +325,10 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 		candidatesWithURL := []domain.CandidateWithURL{}
 
 		for _, candidate := range candidates {
-			// Create presigned URL for GetObject in 24 hours
-			presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-				Bucket: aws.String(service.EnvConfig.S3Bucket),
-				Key:    aws.String(candidate.PhotoKey),
-			}, s3.WithPresignExpires(24*time.Hour))
+			// Get photo URLs, presigned URLs are reused from the cache
+			photoURL, thumbnailURL, err := service.presignPhoto(ctx, candidate.PhotoKey)
 			if err != nil {
-				return []web.CandidateResponseWithURL{}, appError.NewAppError(
-					http.StatusInternalServerError,
-					"Internal Server Error",
-					"Failed to process your request due to an unexpected error. Please try again later.",
-					fmt.Errorf("%w: %v", appError.ErrCreatePresignedPut, err),
-				)
+				return []web.CandidateResponseWithURL{}, err
 			}
 
 			currentCandidateWithURL := domain.CandidateWithURL{
@@ -329,7 +338,8 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 				Vice:                  candidate.Vice,
 				Vision:                candidate.Vision,
 				Mission:               candidate.Mission,
-				PhotoURL:              presignResult.URL,
+				PhotoURL:              photoURL,
+				ThumbnailURL:          thumbnailURL,
 				PresidentStudyProgram: candidate.PresidentStudyProgram,
 				ViceStudyProgram:      candidate.ViceStudyProgram,
 				PresidentNIM:          candidate.PresidentNIM,
@@ -341,7 +351,13 @@ func (service *CandidateServiceImpl) GetCandidates(ctx context.Context, period s
 			candidatesWithURL = append(candidatesWithURL, currentCandidateWithURL)
 		}
 
-		return helper.ToCandidatesResponseWithURL(candidatesWithURL), nil
+		response := helper.ToCandidatesResponseWithURL(candidatesWithURL)
+		if err = service.attachMedia(ctx, tx, response); err != nil {
+			return []web.CandidateResponseWithURL{}, err
+		}
+		service.CandidateListCache.Set(period, generation, response)
+
+		return response, nil
 	}
 }
 
@@ -358,28 +374,6 @@ func (service *CandidateServiceImpl) GetCandidateById(ctx context.Context, candi
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Create presigned URL for GET
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(service.EnvConfig.S3AccessKeyId, service.EnvConfig.S3SecretAccessKey, "")),
-		config.WithRegion("auto"),
-	)
-	if err != nil {
-		return web.CandidateResponseWithURL{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err),
-		)
-	}
-
-	// Create S3 client
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(service.EnvConfig.S3URL)
-	})
-
-	// Presigned URL client
-	presignClient := s3.NewPresignClient(client)
-
 	// Get candidate by id
 	candidate, err := service.CandidateRepository.GetById(ctx, tx, candidateId)
 	if err != nil {
@@ -401,18 +395,10 @@ func (service *CandidateServiceImpl) GetCandidateById(ctx context.Context, candi
 		)
 	}
 
-	// Create presigned URL for GetObject in 24 hours
-	presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(service.EnvConfig.S3Bucket),
-		Key:    aws.String(candidate.PhotoKey),
-	}, s3.WithPresignExpires(24*time.Hour))
+	// Get photo URLs, presigned URLs are reused from the cache
+	photoURL, thumbnailURL, err := service.presignPhoto(ctx, candidate.PhotoKey)
 	if err != nil {
-		return web.CandidateResponseWithURL{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrCreatePresignedPut, err),
-		)
+		return web.CandidateResponseWithURL{}, err
 	}
 
 	candidateWithURL := domain.CandidateWithURL{
@@ -422,7 +408,8 @@ func (service *CandidateServiceImpl) GetCandidateById(ctx context.Context, candi
 		Vice:                  candidate.Vice,
 		Vision:                candidate.Vision,
 		Mission:               candidate.Mission,
-		PhotoURL:              presignResult.URL,
+		PhotoURL:              photoURL,
+		ThumbnailURL:          thumbnailURL,
 		PresidentStudyProgram: candidate.PresidentStudyProgram,
 		ViceStudyProgram:      candidate.ViceStudyProgram,
 		PresidentNIM:          candidate.PresidentNIM,
@@ -431,10 +418,15 @@ func (service *CandidateServiceImpl) GetCandidateById(ctx context.Context, candi
 		UpdatedAt:             candidate.UpdatedAt,
 	}
 
-	return helper.ToCandidateResponseWithURL(candidateWithURL), nil
+	response := []web.CandidateResponseWithURL{helper.ToCandidateResponseWithURL(candidateWithURL)}
+	if err = service.attachMedia(ctx, tx, response); err != nil {
+		return web.CandidateResponseWithURL{}, err
+	}
+
+	return response[0], nil
 }
 
-func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, candidateId int, request web.CandidateUpdateRequest) (web.CandidateResponse, error) {
+func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, actor web.UserResponse, candidateId int, request web.CandidateUpdateRequest) (web.CandidateResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
@@ -468,8 +460,8 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		)
 	}
 
-	// Is candidateId exists in database
-	candidate, err := service.CandidateRepository.GetById(ctx, tx, candidateId)
+	// Is candidateId exists in database, locked so the diff below matches what is written
+	candidate, err := service.CandidateRepository.GetByIdForUpdate(ctx, tx, candidateId)
 	if err != nil {
 		// If candidate not found
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -489,28 +481,22 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		)
 	}
 
-	// Check whether candidate's record is already on votes table
-	votes, err := service.VoteService.GetByCandidateId(ctx, candidate.Id)
+	// Candidates are frozen once voting of their period has started, which
+	// also covers candidates that already have votes
+	started, err := service.votingStarted(ctx, tx, candidate)
 	if err != nil {
-		return web.CandidateResponse{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get votes by candidate id: %v", err),
-		)
+		return web.CandidateResponse{}, err
 	}
 
-	// If votes has data, candidate cannot be deleted
-	if len(votes) > 0 {
-		return web.CandidateResponse{}, appError.NewAppError(
-			http.StatusConflict,
-			"Candidate has votes",
-			"Cannot delete candidate because votes have already submitted",
-			fmt.Errorf("%w", appError.ErrCandidateHasVotes),
-		)
+	// Numbers can't be changed by hand once they are drawn or voting has started
+	if request.Number != 0 && request.Number != candidate.Number {
+		if err = checkBallotNumbersOpen(ctx, tx, service.VotingPeriodRepository, service.BallotDrawRepository, candidate.CreatedAt.Year()); err != nil {
+			return web.CandidateResponse{}, err
+		}
 	}
 
 	// Check the request body, if exists, swap the candidate to the request body
+	before := candidate
 	if request.Number != 0 {
 		candidate.Number = request.Number
 	}
@@ -524,7 +510,7 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		candidate.Vision = request.Vision
 	}
 	if request.Mission != nil {
-		candidate.Mission = strings.Join(request.Mission, "*")
+		candidate.Mission = request.Mission
 	}
 	if request.PhotoKey != "" {
 		candidate.PhotoKey = request.PhotoKey
@@ -542,6 +528,23 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		candidate.ViceNIM = request.ViceNIM
 	}
 
+	// After the lock only typo-level fields may be corrected, with a reason
+	changes := candidateChanges(before, candidate)
+	if started && len(changes) > 0 {
+		if err = checkCandidateCorrection(candidateId, changes, request.OverrideReason); err != nil {
+			return web.CandidateResponse{}, err
+		}
+	}
+
+	// A new photo must be confirmed through the upload API. Checked after the
+	// lock so a frozen candidate is refused without reaching the object storage.
+	_, photoChanged := changes["photo_key"]
+	if photoChanged {
+		if err = checkCandidatePhotoKey(ctx, service.ObjectStore, candidate.PhotoKey); err != nil {
+			return web.CandidateResponse{}, err
+		}
+	}
+
 	// Get all candidates for the shake of validation
 	candidates, err := service.CandidateRepository.GetAll(ctx, tx)
 	if err != nil {
@@ -553,8 +556,8 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		)
 	}
 
-	// currentPeriod is the current year or period
-	currentPeriod := time.Now().Year()
+	// The period of the candidate being updated
+	candidatePeriod := candidate.CreatedAt.Year()
 
 	for _, c := range candidates {
 		if c.Id == candidateId {
@@ -565,7 +568,7 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		currentCandidatePeriod := c.CreatedAt.Year()
 
 		// Numbers cannot be the same in the same period
-		if request.Number == c.Number && currentPeriod == currentCandidatePeriod {
+		if request.Number != 0 && request.Number == c.Number && candidatePeriod == currentCandidatePeriod {
 			return web.CandidateResponse{}, appError.NewAppError(
 				http.StatusBadRequest,
 				"Invalid request payload",
@@ -616,6 +619,26 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		)
 	}
 
+	// Every change after the lock is kept in the candidate's audit history
+	if started && len(changes) > 0 {
+		if err = service.auditCorrection(ctx, tx, actor, candidate, changes, request.OverrideReason); err != nil {
+			return web.CandidateResponse{}, err
+		}
+	}
+
+	// Every update keeps the previous values so the history can't be rewritten
+	if len(changes) > 0 {
+		err = saveCandidateRevision(ctx, tx, service.CandidateRevisionRepository, domain.CandidateRevision{
+			CandidateId: candidateId,
+			Changes:     changes,
+			Reason:      request.OverrideReason,
+			ActorId:     &actor.ID,
+		})
+		if err != nil {
+			return web.CandidateResponse{}, err
+		}
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return web.CandidateResponse{}, appError.NewAppError(
@@ -626,6 +649,17 @@ func (service *CandidateServiceImpl) UpdateCandidateById(ctx context.Context, ca
 		)
 	}
 
+	service.CandidateListCache.Clear()
+
+	// The replaced photo and its thumbnail are no longer used
+	if photoChanged {
+		oldKeys := candidateFileKeys(before.PhotoKey)
+		service.deleteObjects(ctx, oldKeys)
+		for _, key := range oldKeys {
+			service.PhotoURLCache.Delete(key)
+		}
+	}
+
 	return helper.ToCandidateResponse(candidate), nil
 }
 
@@ -684,39 +718,28 @@ func (service *CandidateServiceImpl) DeleteCandidateById(ctx context.Context, ca
 		)
 	}
 
-	// Setup S3
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(service.EnvConfig.S3AccessKeyId, service.EnvConfig.S3SecretAccessKey, "")),
-		config.WithRegion("auto"),
-	)
-	if err != nil {
-		return appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err),
-		)
+	// Candidates can't withdraw once voting of their period has started
+	if err = service.checkUnlocked(ctx, tx, candidate); err != nil {
+		return err
 	}
 
-	// Create S3 client
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(service.EnvConfig.S3URL)
-	})
-
-	// Delete candidate photo
-	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(service.EnvConfig.S3Bucket),
-		Key:    aws.String(candidate.PhotoKey),
-	})
+	// Media rows are deleted together with the candidate, their files after commit
+	mediaList, err := service.CandidateMediaRepository.GetByCandidateId(ctx, tx, candidateId)
 	if err != nil {
 		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrCreatePresignedPut, err),
+			fmt.Errorf("failed to get media of candidate with id %v: %v", candidateId, err),
 		)
 	}
 
+	// Candidate photo, media files and their thumbnails
+	keys := candidateFileKeys(candidate.PhotoKey)
+	for _, media := range mediaList {
+		keys = append(keys, candidateFileKeys(media.ObjectKey)...)
+	}
+
 	// Delete candidate by id
 	err = service.CandidateRepository.DeleteById(ctx, tx, candidateId)
 	if err != nil {
@@ -738,5 +761,874 @@ func (service *CandidateServiceImpl) DeleteCandidateById(ctx context.Context, ca
 		)
 	}
 
+	service.deleteObjects(ctx, keys)
+
+	service.CandidateListCache.Clear()
+	for _, key := range keys {
+		service.PhotoURLCache.Delete(key)
+	}
+
+	return nil
+}
+
+// GetHistory returns every change of the candidate, newest first. The history
+// of a deleted candidate is kept and can still be read.
+func (service *CandidateServiceImpl) GetHistory(ctx context.Context, candidateId int) ([]web.CandidateRevisionResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	revisions, err := service.CandidateRevisionRepository.GetByCandidateId(ctx, tx, candidateId)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get revisions of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	// A candidate without revisions must exist
+	if len(revisions) == 0 {
+		if _, err = service.getCandidate(ctx, tx, candidateId); err != nil {
+			return nil, err
+		}
+	}
+
+	return helper.ToCandidateRevisionsResponse(revisions), nil
+}
+
+// GetAuditLogs returns the changes made to the candidate after it was locked
+func (service *CandidateServiceImpl) GetAuditLogs(ctx context.Context, candidateId int) ([]web.AuditLogResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Candidate must exist
+	if _, err = service.getCandidate(ctx, tx, candidateId); err != nil {
+		return nil, err
+	}
+
+	auditLogs, err := service.AuditLogRepository.GetByTarget(ctx, tx, domain.AuditTargetCandidate, candidateId)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get audit logs of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	return helper.ToAuditLogsResponse(auditLogs), nil
+}
+
+// checkCandidatePhotoKey makes sure the photo went through POST /api/upload/candidates/confirm,
+// so it was validated and has a thumbnail
+func checkCandidatePhotoKey(ctx context.Context, objectStore storage.ObjectStore, photoKey string) error {
+	if !helper.IsCandidatePhotoKey(photoKey) {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"Photo key must be the 'photo_key' returned by POST /api/upload/candidates/confirm",
+			fmt.Errorf("%w: '%s' is not a confirmed candidate photo key", appError.ErrValidation, photoKey),
+		)
+	}
+
+	_, err := objectStore.Head(ctx, photoKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Photo key '%s' does not exist", photoKey),
+				fmt.Errorf("%w: %v", appError.ErrValidation, err),
+			)
+		}
+
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to head candidate photo: %v", appError.ErrObjectStorage, err),
+		)
+	}
+
 	return nil
 }
+
+// presignPhoto returns the URLs of the photo and its thumbnail. Photos
+// uploaded before thumbnails existed use the photo itself as thumbnail.
+func (service *CandidateServiceImpl) presignPhoto(ctx context.Context, photoKey string) (string, string, error) {
+	photoURL, err := service.photoURL(ctx, photoKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	thumbnailKey := helper.CandidateThumbnailKey(photoKey)
+	if thumbnailKey == "" {
+		return photoURL, photoURL, nil
+	}
+
+	thumbnailURL, err := service.photoURL(ctx, thumbnailKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return photoURL, thumbnailURL, nil
+}
+
+// photoURL returns the public URL of a photo or document key when
+// STORAGE_PUBLIC_URL is set, otherwise a presigned URL that is cached to be reused
+func (service *CandidateServiceImpl) photoURL(ctx context.Context, key string) (string, error) {
+	if service.EnvConfig.StoragePublicURL != "" {
+		return storage.PublicURL(service.EnvConfig.StoragePublicURL, key), nil
+	}
+
+	if url, ok := service.PhotoURLCache.Get(key); ok {
+		return url, nil
+	}
+
+	url, err := service.ObjectStore.PresignGet(ctx, key, CandidatePhotoURLExpiry)
+	if err != nil {
+		return "", appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrCreatePresignedGet, err),
+		)
+	}
+	service.PhotoURLCache.Set(key, url)
+
+	return url, nil
+}
+
+func (service *CandidateServiceImpl) GetMedia(ctx context.Context, candidateId int) ([]web.CandidateMediaResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Candidate must exist
+	if _, err = service.getCandidate(ctx, tx, candidateId); err != nil {
+		return []web.CandidateMediaResponse{}, err
+	}
+
+	mediaList, err := service.CandidateMediaRepository.GetByCandidateId(ctx, tx, candidateId)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get media of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	return service.mediaResponses(ctx, mediaList)
+}
+
+func (service *CandidateServiceImpl) CreateMedia(ctx context.Context, candidateId int, request web.CandidateMediaCreateRequest) (web.CandidateMediaResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	media := domain.CandidateMedia{
+		CandidateId: candidateId,
+		Type:        request.Type,
+		Title:       request.Title,
+		ObjectKey:   request.ObjectKey,
+		URL:         request.URL,
+	}
+
+	// Files need a confirmed upload, links need a URL
+	if media.IsFile() {
+		if request.URL != "" {
+			return web.CandidateMediaResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Media of type '%s' is a file and can't have a url", request.Type),
+				fmt.Errorf("%w: url given for %s media", appError.ErrValidation, request.Type),
+			)
+		}
+
+		if err = service.checkMediaObjectKey(ctx, media); err != nil {
+			return web.CandidateMediaResponse{}, err
+		}
+	} else {
+		if request.URL == "" || request.ObjectKey != "" {
+			return web.CandidateMediaResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Media of type '%s' is a link and needs a url instead of an object key", request.Type),
+				fmt.Errorf("%w: %s media needs a url only", appError.ErrValidation, request.Type),
+			)
+		}
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Candidate must exist and not be locked
+	if _, err = service.getUnlockedCandidate(ctx, tx, candidateId); err != nil {
+		return web.CandidateMediaResponse{}, err
+	}
+
+	// A file can only be attached once, otherwise deleting one media would
+	// delete the file of another
+	if media.IsFile() {
+		keys, err := service.CandidateMediaRepository.GetObjectKeys(ctx, tx)
+		if err != nil {
+			return web.CandidateMediaResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to get candidate media keys: %v", err),
+			)
+		}
+
+		if slices.Contains(keys, media.ObjectKey) {
+			return web.CandidateMediaResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Object key '%s' is already used, please upload the file again", media.ObjectKey),
+				fmt.Errorf("%w: object key %s is used", appError.ErrValidation, media.ObjectKey),
+			)
+		}
+	}
+
+	media, err = service.CandidateMediaRepository.Save(ctx, tx, media)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to create media for candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.CandidateListCache.Clear()
+
+	return service.mediaResponse(ctx, media)
+}
+
+func (service *CandidateServiceImpl) UpdateMedia(ctx context.Context, candidateId, mediaId int, request web.CandidateMediaUpdateRequest) (web.CandidateMediaResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Request body cannot be empty validation
+	if helper.IsEmptyStruct(request) {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"Request body cannot be empty",
+			fmt.Errorf("%w: empty media update", appError.ErrValidation),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	if _, err = service.getUnlockedCandidate(ctx, tx, candidateId); err != nil {
+		return web.CandidateMediaResponse{}, err
+	}
+
+	media, err := service.getMedia(ctx, tx, candidateId, mediaId)
+	if err != nil {
+		return web.CandidateMediaResponse{}, err
+	}
+
+	// Files are replaced by deleting the media and attaching a new upload
+	if request.URL != "" && media.IsFile() {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			fmt.Sprintf("Media of type '%s' is a file and can't have a url", media.Type),
+			fmt.Errorf("%w: url given for %s media", appError.ErrValidation, media.Type),
+		)
+	}
+
+	if request.Title != "" {
+		media.Title = request.Title
+	}
+	if request.URL != "" {
+		media.URL = request.URL
+	}
+
+	media, err = service.CandidateMediaRepository.Update(ctx, tx, media)
+	if err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update media with id %v: %v", mediaId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.CandidateListCache.Clear()
+
+	return service.mediaResponse(ctx, media)
+}
+
+func (service *CandidateServiceImpl) DeleteMedia(ctx context.Context, candidateId, mediaId int) error {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	if _, err = service.getUnlockedCandidate(ctx, tx, candidateId); err != nil {
+		return err
+	}
+
+	media, err := service.getMedia(ctx, tx, candidateId, mediaId)
+	if err != nil {
+		return err
+	}
+
+	// The file and its thumbnail are deleted after commit
+	keys := candidateFileKeys(media.ObjectKey)
+
+	err = service.CandidateMediaRepository.DeleteById(ctx, tx, mediaId)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to delete media with id %v: %v", mediaId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.deleteObjects(ctx, keys)
+
+	service.CandidateListCache.Clear()
+	for _, key := range keys {
+		service.PhotoURLCache.Delete(key)
+	}
+
+	return nil
+}
+
+func (service *CandidateServiceImpl) ReorderMedia(ctx context.Context, candidateId int, request web.CandidateMediaOrderRequest) ([]web.CandidateMediaResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Candidate must exist and not be locked
+	if _, err = service.getUnlockedCandidate(ctx, tx, candidateId); err != nil {
+		return []web.CandidateMediaResponse{}, err
+	}
+
+	mediaList, err := service.CandidateMediaRepository.GetByCandidateId(ctx, tx, candidateId)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get media of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	// The order must list every media of the candidate exactly once
+	currentIds := make([]int, 0, len(mediaList))
+	for _, media := range mediaList {
+		currentIds = append(currentIds, media.Id)
+	}
+	requestIds := slices.Clone(request.MediaIds)
+	slices.Sort(currentIds)
+	slices.Sort(requestIds)
+
+	if !slices.Equal(currentIds, requestIds) {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"Media ids must list every media of the candidate exactly once",
+			fmt.Errorf("%w: media ids %v don't match %v", appError.ErrValidation, request.MediaIds, currentIds),
+		)
+	}
+
+	err = service.CandidateMediaRepository.UpdatePositions(ctx, tx, candidateId, request.MediaIds)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to reorder media of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	mediaList, err = service.CandidateMediaRepository.GetByCandidateId(ctx, tx, candidateId)
+	if err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get media of candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return []web.CandidateMediaResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.CandidateListCache.Clear()
+
+	return service.mediaResponses(ctx, mediaList)
+}
+
+// getCandidate returns the candidate or a not found error
+func (service *CandidateServiceImpl) getCandidate(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error) {
+	candidate, err := service.CandidateRepository.GetById(ctx, tx, candidateId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Candidate{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Candidate not found",
+				fmt.Sprintf("Candidate with id %v does not exist", candidateId),
+				fmt.Errorf("candidate with id %v not found: %v", candidateId, err),
+			)
+		}
+
+		return domain.Candidate{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate with id %v: %v", candidateId, err),
+		)
+	}
+
+	return candidate, nil
+}
+
+// getUnlockedCandidate returns the candidate when voting of its period
+// hasn't started yet
+func (service *CandidateServiceImpl) getUnlockedCandidate(ctx context.Context, tx *sql.Tx, candidateId int) (domain.Candidate, error) {
+	candidate, err := service.getCandidate(ctx, tx, candidateId)
+	if err != nil {
+		return domain.Candidate{}, err
+	}
+
+	if err = service.checkUnlocked(ctx, tx, candidate); err != nil {
+		return domain.Candidate{}, err
+	}
+
+	return candidate, nil
+}
+
+// checkUnlocked returns a conflict once voting of the candidate's period has started
+func (service *CandidateServiceImpl) checkUnlocked(ctx context.Context, tx *sql.Tx, candidate domain.Candidate) error {
+	started, err := service.votingStarted(ctx, tx, candidate)
+	if err != nil {
+		return err
+	}
+
+	if started {
+		return appError.NewAppError(
+			http.StatusConflict,
+			"Candidate is locked",
+			fmt.Sprintf("Voting of period %v has started, candidate with id %v can no longer be changed", candidate.CreatedAt.Year(), candidate.Id),
+			fmt.Errorf("%w: candidate %v", appError.ErrVotingStarted, candidate.Id),
+		)
+	}
+
+	return nil
+}
+
+func (service *CandidateServiceImpl) votingStarted(ctx context.Context, tx *sql.Tx, candidate domain.Candidate) (bool, error) {
+	started, err := service.VotingPeriodRepository.IsVotingStarted(ctx, tx, candidate.CreatedAt.Year())
+	if err != nil {
+		return false, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check whether voting of period %v started: %w", candidate.CreatedAt.Year(), err),
+		)
+	}
+
+	return started, nil
+}
+
+// auditCorrection records a change made after the candidate was locked
+func (service *CandidateServiceImpl) auditCorrection(ctx context.Context, tx *sql.Tx, actor web.UserResponse, candidate domain.Candidate, changes map[string]domain.CandidateFieldChange, reason string) error {
+	details, err := json.Marshal(map[string]interface{}{
+		"period":  candidate.CreatedAt.Year(),
+		"reason":  reason,
+		"changes": changes,
+	})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
+	}
+
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, []domain.AuditLog{{
+		ActorId:    &actor.ID,
+		Action:     domain.AuditActionCandidateCorrected,
+		TargetType: domain.AuditTargetCandidate,
+		TargetId:   &candidate.Id,
+		Details:    details,
+	}})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit log: %w", err),
+		)
+	}
+
+	return nil
+}
+
+// getMedia returns a media of the candidate or a not found error
+func (service *CandidateServiceImpl) getMedia(ctx context.Context, tx *sql.Tx, candidateId, mediaId int) (domain.CandidateMedia, error) {
+	media, err := service.CandidateMediaRepository.GetById(ctx, tx, candidateId, mediaId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CandidateMedia{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Media not found",
+				fmt.Sprintf("Media with id %v does not exist for candidate with id %v", mediaId, candidateId),
+				fmt.Errorf("media with id %v of candidate %v not found: %v", mediaId, candidateId, err),
+			)
+		}
+
+		return domain.CandidateMedia{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get media with id %v: %v", mediaId, err),
+		)
+	}
+
+	return media, nil
+}
+
+// checkMediaObjectKey makes sure a poster went through POST /api/upload/candidates/confirm
+// and a document through POST /api/upload/candidates/documents/confirm
+func (service *CandidateServiceImpl) checkMediaObjectKey(ctx context.Context, media domain.CandidateMedia) error {
+	if media.Type == domain.CandidateMediaPoster {
+		return checkCandidatePhotoKey(ctx, service.ObjectStore, media.ObjectKey)
+	}
+
+	if !helper.IsCandidateDocumentKey(media.ObjectKey) {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"Object key must be the 'document_key' returned by POST /api/upload/candidates/documents/confirm",
+			fmt.Errorf("%w: '%s' is not a confirmed candidate document key", appError.ErrValidation, media.ObjectKey),
+		)
+	}
+
+	_, err := service.ObjectStore.Head(ctx, media.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid request payload",
+				fmt.Sprintf("Object key '%s' does not exist", media.ObjectKey),
+				fmt.Errorf("%w: %v", appError.ErrValidation, err),
+			)
+		}
+
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to head candidate document: %v", appError.ErrObjectStorage, err),
+		)
+	}
+
+	return nil
+}
+
+// attachMedia fills the media of each candidate with a single query
+func (service *CandidateServiceImpl) attachMedia(ctx context.Context, tx *sql.Tx, candidates []web.CandidateResponseWithURL) error {
+	candidateIds := make([]int, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidateIds = append(candidateIds, candidate.Id)
+	}
+
+	mediaList, err := service.CandidateMediaRepository.GetByCandidateIds(ctx, tx, candidateIds)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate media: %v", err),
+		)
+	}
+
+	responses, err := service.mediaResponses(ctx, mediaList)
+	if err != nil {
+		return err
+	}
+
+	mediaByCandidate := make(map[int][]web.CandidateMediaResponse, len(candidates))
+	for _, response := range responses {
+		mediaByCandidate[response.CandidateId] = append(mediaByCandidate[response.CandidateId], response)
+	}
+
+	for i := range candidates {
+		candidates[i].Media = mediaByCandidate[candidates[i].Id]
+		if candidates[i].Media == nil {
+			candidates[i].Media = []web.CandidateMediaResponse{}
+		}
+	}
+
+	return nil
+}
+
+func (service *CandidateServiceImpl) mediaResponses(ctx context.Context, mediaList []domain.CandidateMedia) ([]web.CandidateMediaResponse, error) {
+	responses := make([]web.CandidateMediaResponse, 0, len(mediaList))
+	for _, media := range mediaList {
+		response, err := service.mediaResponse(ctx, media)
+		if err != nil {
+			return []web.CandidateMediaResponse{}, err
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// mediaResponse resolves the URL of file media, links are returned as they are
+func (service *CandidateServiceImpl) mediaResponse(ctx context.Context, media domain.CandidateMedia) (web.CandidateMediaResponse, error) {
+	url, thumbnailURL := media.URL, ""
+
+	switch media.Type {
+	case domain.CandidateMediaPoster:
+		var err error
+		url, thumbnailURL, err = service.presignPhoto(ctx, media.ObjectKey)
+		if err != nil {
+			return web.CandidateMediaResponse{}, err
+		}
+	case domain.CandidateMediaDocument:
+		var err error
+		url, err = service.photoURL(ctx, media.ObjectKey)
+		if err != nil {
+			return web.CandidateMediaResponse{}, err
+		}
+	}
+
+	return helper.ToCandidateMediaResponse(media, url, thumbnailURL), nil
+}
+
+// deleteObjects deletes files from the object storage once their rows are
+// committed as deleted. A failure is only logged, the upload reconciler
+// removes the file later because nothing uses it anymore.
+func (service *CandidateServiceImpl) deleteObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := service.ObjectStore.Delete(ctx, key); err != nil {
+			appError.LogError(fmt.Errorf("%w: %v", appError.ErrObjectStorage, err), fmt.Sprintf("failed to delete candidate file '%s'", key))
+		}
+	}
+}
+
+// candidateFileKeys returns key and its thumbnail, if any
+func candidateFileKeys(key string) []string {
+	if key == "" {
+		return nil
+	}
+
+	if thumbnailKey := helper.CandidateThumbnailKey(key); thumbnailKey != "" {
+		return []string{key, thumbnailKey}
+	}
+
+	return []string{key}
+}
+
+// candidateCorrectableFields are the typo-level fields an admin may still
+// correct once voting has started
+var candidateCorrectableFields = []string{"president", "vice", "president_study_program", "vice_study_program"}
+
+// checkCandidateCorrection allows changes after the lock when they only touch
+// correctable fields and come with a reason
+func checkCandidateCorrection(candidateId int, changes map[string]domain.CandidateFieldChange, reason string) error {
+	var locked []string
+	for field := range changes {
+		if !slices.Contains(candidateCorrectableFields, field) {
+			locked = append(locked, field)
+		}
+	}
+	slices.Sort(locked)
+
+	if len(locked) > 0 {
+		return appError.NewAppError(
+			http.StatusConflict,
+			"Candidate is locked",
+			fmt.Sprintf("Voting has started, only %s can be corrected but %s was changed", strings.Join(candidateCorrectableFields, ", "), strings.Join(locked, ", ")),
+			fmt.Errorf("%w: candidate %v fields %v", appError.ErrVotingStarted, candidateId, locked),
+		)
+	}
+
+	if reason == "" {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"Voting has started, override_reason is required to correct a candidate",
+			fmt.Errorf("%w: override_reason missing for candidate %v", appError.ErrValidation, candidateId),
+		)
+	}
+
+	return nil
+}
+
+// saveCandidateRevision records a change of a candidate in its history. The
+// candidate row must be locked or newly created by the same transaction.
+func saveCandidateRevision(ctx context.Context, tx *sql.Tx, candidateRevisionRepository repository.CandidateRevisionRepository, revision domain.CandidateRevision) error {
+	_, err := candidateRevisionRepository.Save(ctx, tx, revision)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save revision of candidate with id %v: %v", revision.CandidateId, err),
+		)
+	}
+
+	return nil
+}
+
+// candidateChanges returns the fields that differ between two versions of a
+// candidate, keyed by their JSON name
+func candidateChanges(before, after domain.Candidate) map[string]domain.CandidateFieldChange {
+	changes := make(map[string]domain.CandidateFieldChange)
+
+	addChange := func(field string, from, to any, changed bool) {
+		if changed {
+			changes[field] = domain.CandidateFieldChange{From: from, To: to}
+		}
+	}
+
+	addChange("number", before.Number, after.Number, before.Number != after.Number)
+	addChange("president", before.President, after.President, before.President != after.President)
+	addChange("vice", before.Vice, after.Vice, before.Vice != after.Vice)
+	addChange("vision", before.Vision, after.Vision, before.Vision != after.Vision)
+	addChange("mission", before.Mission, after.Mission, !slices.Equal(before.Mission, after.Mission))
+	addChange("photo_key", before.PhotoKey, after.PhotoKey, before.PhotoKey != after.PhotoKey)
+	addChange("president_study_program", before.PresidentStudyProgram, after.PresidentStudyProgram, before.PresidentStudyProgram != after.PresidentStudyProgram)
+	addChange("vice_study_program", before.ViceStudyProgram, after.ViceStudyProgram, before.ViceStudyProgram != after.ViceStudyProgram)
+	addChange("president_nim", before.PresidentNIM, after.PresidentNIM, before.PresidentNIM != after.PresidentNIM)
+	addChange("vice_nim", before.ViceNIM, after.ViceNIM, before.ViceNIM != after.ViceNIM)
+
+	return changes
+}
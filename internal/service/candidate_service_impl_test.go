@@ -0,0 +1,122 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+)
+
+func newTestCandidate() domain.Candidate {
+	return domain.Candidate{
+		Id:                    1,
+		Number:                1,
+		President:             "Budi Santoso",
+		Vice:                  "Sari Lestari",
+		Vision:                "Himpunan yang inklusif",
+		Mission:               []string{"Transparansi", "Kolaborasi"},
+		PhotoKey:              "candidates/photos/a.jpg",
+		PresidentStudyProgram: "Teknik Informatika",
+		ViceStudyProgram:      "Sistem Informasi",
+		PresidentNIM:          "2201",
+		ViceNIM:               "2202",
+		CreatedAt:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// candidateChangeIgnoredFields are bookkeeping fields that are not audited as changes
+var candidateChangeIgnoredFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+func TestCandidateChangesNoChange(t *testing.T) {
+	before := newTestCandidate()
+	after := newTestCandidate()
+	after.UpdatedAt = after.UpdatedAt.Add(time.Hour)
+
+	if changes := candidateChanges(before, after); len(changes) != 0 {
+		t.Errorf("candidateChanges = %v, want no changes", changes)
+	}
+}
+
+// TestCandidateChangesEveryField changes one field at a time, so a field
+// added to domain.Candidate without being diffed makes the test fail
+func TestCandidateChangesEveryField(t *testing.T) {
+	candidateType := reflect.TypeOf(domain.Candidate{})
+
+	for i := 0; i < candidateType.NumField(); i++ {
+		field := candidateType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if candidateChangeIgnoredFields[name] {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			before := newTestCandidate()
+			after := newTestCandidate()
+
+			value := reflect.ValueOf(&after).Elem().Field(i)
+			switch value.Kind() {
+			case reflect.Int:
+				value.SetInt(value.Int() + 1)
+			case reflect.String:
+				value.SetString(value.String() + " (edited)")
+			case reflect.Slice:
+				value.Set(reflect.Append(value, reflect.ValueOf("Pelayanan")))
+			default:
+				t.Fatalf("field %s has unhandled kind %s", field.Name, value.Kind())
+			}
+
+			changes := candidateChanges(before, after)
+			if len(changes) != 1 {
+				t.Fatalf("candidateChanges = %v, want only %s", changes, name)
+			}
+
+			change, ok := changes[name]
+			if !ok {
+				t.Fatalf("candidateChanges = %v, want a change keyed %q", changes, name)
+			}
+
+			wantFrom := reflect.ValueOf(before).Field(i).Interface()
+			wantTo := value.Interface()
+			if !reflect.DeepEqual(change.From, wantFrom) || !reflect.DeepEqual(change.To, wantTo) {
+				t.Errorf("change = %+v, want from %v to %v", change, wantFrom, wantTo)
+			}
+		})
+	}
+}
+
+func TestCandidateChangesMission(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      []string
+		after       []string
+		wantChanged bool
+	}{
+		{"same items", []string{"A", "B"}, []string{"A", "B"}, false},
+		{"nil and empty", nil, []string{}, false},
+		{"reordered", []string{"A", "B"}, []string{"B", "A"}, true},
+		{"item edited", []string{"A", "B"}, []string{"A", "C"}, true},
+		{"item removed", []string{"A", "B"}, []string{"A"}, true},
+		{"first item added", nil, []string{"A"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := newTestCandidate()
+			after := newTestCandidate()
+			before.Mission = tt.before
+			after.Mission = tt.after
+
+			_, changed := candidateChanges(before, after)["mission"]
+			if changed != tt.wantChanged {
+				t.Errorf("mission changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -10,20 +10,18 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-playground/validator/v10"
 	envConfig "github.com/mhaatha/HIMA-TI-e-Election/internal/config"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/storage"
 )
 
-func NewDownloadService(downloadRepository repository.DownloadRepository, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) DownloadService {
+func NewDownloadService(downloadRepository repository.DownloadRepository, objectStore storage.ObjectStore, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) DownloadService {
 	return &DownloadServiceImpl{
 		DownloadRepository: downloadRepository,
+		ObjectStore:        objectStore,
 		EnvConfig:          envConfig,
 		DB:                 db,
 		Validate:           validate,
@@ -32,6 +30,7 @@ func NewDownloadService(downloadRepository repository.DownloadRepository, envCon
 
 type DownloadServiceImpl struct {
 	DownloadRepository repository.DownloadRepository
+	ObjectStore        storage.ObjectStore
 	EnvConfig          *envConfig.Config
 	DB                 *sql.DB
 	Validate           *validator.Validate
@@ -69,25 +68,6 @@ func (service *DownloadServiceImpl) CreatePresignedURL(ctx context.Context, file
 		)
 	}
 
-	// Load S3 default config
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(service.EnvConfig.S3AccessKeyId, service.EnvConfig.S3SecretAccessKey, "")),
-		config.WithRegion("auto"),
-	)
-	if err != nil {
-		return web.PresignedURLResponse{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err),
-		)
-	}
-
-	// Create S3 client
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(service.EnvConfig.S3URL)
-	})
-
 	// Read the log local file
 	logFilePath, err := envConfig.GetLogFilePathByYear(fileName)
 	if err != nil {
@@ -119,38 +99,31 @@ func (service *DownloadServiceImpl) CreatePresignedURL(ctx context.Context, file
 	}
 
 	// Upload to Object Storage
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(service.EnvConfig.S3Bucket),
-		Key:    aws.String(fmt.Sprintf("logs/vote_%s.log", fileName)),
-		Body:   file,
-	})
+	defer file.Close()
+
+	err = service.ObjectStore.Put(ctx, fmt.Sprintf("logs/vote_%s.log", fileName), file, "text/plain")
 	if err != nil {
 		return web.PresignedURLResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Failed to upload log",
 			"Server failed to upload the log file to storage",
-			fmt.Errorf("failed to upload file to S3: %v", err),
+			fmt.Errorf("%w: failed to upload log file: %v", appError.ErrObjectStorage, err),
 		)
 	}
 
 	// Create the presigned URL to get the uploaded log file
-	presignClient := s3.NewPresignClient(client)
-
-	presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(service.EnvConfig.S3Bucket),
-		Key:    aws.String(fmt.Sprintf("logs/vote_%s.log", fileName)),
-	}, s3.WithPresignExpires(5*time.Minute))
+	presignedURL, err := service.ObjectStore.PresignGet(ctx, fmt.Sprintf("logs/vote_%s.log", fileName), 5*time.Minute)
 	if err != nil {
 		return web.PresignedURLResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Failed to generate download link",
 			"Server failed to generate presigned URL for log download",
-			fmt.Errorf("failed to presign GET URL: %v", err),
+			fmt.Errorf("%w: %v", appError.ErrCreatePresignedGet, err),
 		)
 	}
 
 	return web.PresignedURLResponse{
-		URL:      presignResult.URL,
+		URL:      presignedURL,
 		FileName: fmt.Sprintf("vote_%s.log", fileName),
 	}, nil
 }
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+type OutboxService interface {
+	RunWorker(ctx context.Context)
+	GetFailed(ctx context.Context) ([]web.OutboxMessageResponse, error)
+	Resend(ctx context.Context, actor web.UserResponse, messageId int) (web.OutboxMessageResponse, error)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/config"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/notifier"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+)
+
+// Outbox delivery rules
+const (
+	OutboxPollInterval = 5 * time.Second
+	OutboxBatchSize    = 20
+	OutboxLease        = 2 * time.Minute
+	OutboxSendTimeout  = 30 * time.Second
+	OutboxMaxAttempts  = 5
+	OutboxBaseBackoff  = 30 * time.Second
+	OutboxMaxBackoff   = 30 * time.Minute
+)
+
+func NewOutboxService(outboxRepository repository.OutboxRepository, userService UserService, notifier notifier.Notifier, db *sql.DB) OutboxService {
+	return &OutboxServiceImpl{
+		OutboxRepository: outboxRepository,
+		UserService:      userService,
+		Notifier:         notifier,
+		DB:               db,
+	}
+}
+
+type OutboxServiceImpl struct {
+	OutboxRepository repository.OutboxRepository
+	UserService      UserService
+	Notifier         notifier.Notifier
+	DB               *sql.DB
+}
+
+// RunWorker delivers queued messages until ctx is cancelled
+func (service *OutboxServiceImpl) RunWorker(ctx context.Context) {
+	config.Log.Info("Starting outbox worker...")
+
+	ticker := time.NewTicker(OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches are being claimed
+		for {
+			processed, err := service.deliverBatch(ctx)
+			if err != nil {
+				config.Log.Errorf("failed to deliver outbox messages: %v", err)
+				break
+			}
+			if processed < OutboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			config.Log.Info("Outbox worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *OutboxServiceImpl) GetFailed(ctx context.Context) ([]web.OutboxMessageResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return []web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Call repository
+	messages, err := service.OutboxRepository.GetFailed(ctx, tx)
+	if err != nil {
+		return []web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get failed outbox messages: %w", err),
+		)
+	}
+
+	return helper.ToOutboxMessageResponses(messages), nil
+}
+
+// Resend issues a new secret for the recipient of a failed message and
+// queues it. The failed body was cleared, so it is never sent again.
+func (service *OutboxServiceImpl) Resend(ctx context.Context, actor web.UserResponse, messageId int) (web.OutboxMessageResponse, error) {
+	message, err := service.getFailedById(ctx, messageId)
+	if err != nil {
+		return web.OutboxMessageResponse{}, err
+	}
+
+	if message.UserId == nil {
+		return web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Message can't be resent",
+			fmt.Sprintf("The recipient of message %v no longer exists", messageId),
+			fmt.Errorf("outbox message %v has no user", messageId),
+		)
+	}
+	userId := *message.UserId
+
+	// The reissue cancels the failed message and queues a new one
+	switch message.Template {
+	case notifier.TemplateCredential:
+		_, err = service.UserService.RegeneratePasswords(ctx, actor, web.UserRegeneratePasswordRequest{
+			UserIds: []int{userId},
+		})
+	case notifier.TemplateVotingToken:
+		_, err = service.UserService.GenerateVotingTokens(ctx, actor, web.UserGenerateVotingTokenRequest{
+			UserIds: []int{userId},
+		})
+	case notifier.TemplatePasswordResetOTP:
+		err = service.UserService.ResendPasswordResetOTP(ctx, userId)
+	default:
+		return web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Message can't be resent",
+			fmt.Sprintf("Messages with template '%s' can't be resent", message.Template),
+			fmt.Errorf("outbox message %v has unknown template %s", messageId, message.Template),
+		)
+	}
+	if err != nil {
+		return web.OutboxMessageResponse{}, err
+	}
+
+	config.Log.Infof("%s (id %d) resent outbox message %d as a new %s message", actor.FullName, actor.ID, messageId, message.Template)
+
+	return service.getLatestByUserId(ctx, userId, message.Template)
+}
+
+func (service *OutboxServiceImpl) getFailedById(ctx context.Context, messageId int) (domain.OutboxMessage, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.OutboxMessage{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Call repository
+	message, err := service.OutboxRepository.GetFailedById(ctx, tx, messageId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.OutboxMessage{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Message not found",
+				fmt.Sprintf("Failed message with id %v does not exist", messageId),
+				fmt.Errorf("failed outbox message with id %v not found: %w", messageId, err),
+			)
+		}
+
+		return domain.OutboxMessage{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get failed outbox message: %w", err),
+		)
+	}
+
+	return message, nil
+}
+
+func (service *OutboxServiceImpl) getLatestByUserId(ctx context.Context, userId int, template string) (web.OutboxMessageResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Call repository
+	message, err := service.OutboxRepository.GetLatestByUserId(ctx, tx, userId, template)
+	if err != nil {
+		return web.OutboxMessageResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get latest outbox message: %w", err),
+		)
+	}
+
+	return helper.ToOutboxMessageResponse(message), nil
+}
+
+// deliverBatch claims due messages, sends them and records each result
+func (service *OutboxServiceImpl) deliverBatch(ctx context.Context) (int, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	messages, err := service.OutboxRepository.ClaimDue(ctx, tx, OutboxBatchSize, OutboxLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	// Commit transaction so other workers skip the claimed messages
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	for _, message := range messages {
+		sendCtx, cancel := context.WithTimeout(ctx, OutboxSendTimeout)
+		sendErr := service.Notifier.Send(sendCtx, helper.ToNotifierRecipient(message), helper.ToNotifierMessage(message))
+		cancel()
+
+		if err := service.recordResult(ctx, message, sendErr); err != nil {
+			return len(messages), err
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (service *OutboxServiceImpl) recordResult(ctx context.Context, message domain.OutboxMessage, sendErr error) error {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	switch {
+	case sendErr == nil:
+		err = service.OutboxRepository.MarkSent(ctx, tx, message.Id)
+	case message.Attempts+1 >= OutboxMaxAttempts:
+		config.Log.Errorf("outbox message %d to %s failed permanently: %v", message.Id, message.RecipientName, sendErr)
+		err = service.OutboxRepository.MarkFailed(ctx, tx, message.Id, sendErr.Error())
+	default:
+		config.Log.Warnf("outbox message %d to %s failed, retrying: %v", message.Id, message.RecipientName, sendErr)
+		err = service.OutboxRepository.MarkRetry(ctx, tx, message.Id, sendErr.Error(), outboxBackoff(message.Attempts))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message %d: %w", message.Id, err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// outboxBackoff doubles the delay after every failed attempt up to OutboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	backoff := OutboxBaseBackoff
+	for i := 0; i < attempts && backoff < OutboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > OutboxMaxBackoff {
+		backoff = OutboxMaxBackoff
+	}
+	return backoff
+}
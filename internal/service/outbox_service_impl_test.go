@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{"no attempts", 0, OutboxBaseBackoff},
+		{"negative attempts", -1, OutboxBaseBackoff},
+		{"first retry", 1, time.Minute},
+		{"second retry", 2, 2 * time.Minute},
+		{"fifth retry", 5, 16 * time.Minute},
+		{"capped", 6, OutboxMaxBackoff},
+		{"capped after many attempts", 100, OutboxMaxBackoff},
+		{"capped without overflowing", 1 << 20, OutboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outboxBackoff(tt.attempts); got != tt.want {
+				t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboxBackoffNeverDecreases(t *testing.T) {
+	previous := outboxBackoff(0)
+	for attempts := 1; attempts <= 50; attempts++ {
+		got := outboxBackoff(attempts)
+		if got < previous {
+			t.Fatalf("outboxBackoff(%d) = %v, less than %v for the previous attempt", attempts, got, previous)
+		}
+		if got > OutboxMaxBackoff {
+			t.Fatalf("outboxBackoff(%d) = %v, more than OutboxMaxBackoff", attempts, got)
+		}
+		previous = got
+	}
+}
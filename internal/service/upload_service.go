@@ -7,5 +7,8 @@ import (
 )
 
 type UploadService interface {
-	CreatePresignedURL(ctx context.Context, fileName string) (web.PresignedURLResponse, error)
+	RunReconciler(ctx context.Context)
+	CreatePresignedURL(ctx context.Context, actor web.UserResponse, fileName string) (web.PresignedURLResponse, error)
+	ConfirmCandidatePhoto(ctx context.Context, request web.CandidateUploadConfirmRequest) (web.CandidatePhotoResponse, error)
+	ConfirmCandidateDocument(ctx context.Context, request web.CandidateUploadConfirmRequest) (web.CandidateDocumentResponse, error)
 }
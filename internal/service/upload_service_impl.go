@@ -1,67 +1,138 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-playground/validator/v10"
 	envConfig "github.com/mhaatha/HIMA-TI-e-Election/internal/config"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/imaging"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/storage"
 )
 
-func NewUploadService(uploadRepository repository.UploadRepository, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) UploadService {
+// Largest candidate files accepted on confirm
+const (
+	CandidatePhotoMaxSize    = 5 << 20
+	CandidateDocumentMaxSize = 10 << 20
+)
+
+// candidatePhotoTypes maps the accepted content types to their decoded format
+var candidatePhotoTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// candidateDocumentTypes are the accepted document content types
+var candidateDocumentTypes = map[string]string{
+	"application/pdf": "pdf",
+}
+
+// Orphaned upload cleanup rules. Objects under helper.CandidatePrefix that no
+// candidate uses are deleted once they are older than the grace period.
+const (
+	UploadReconcileInterval = time.Hour
+	UploadOrphanGracePeriod = 24 * time.Hour
+)
+
+func NewUploadService(uploadRepository repository.UploadRepository, candidateRepository repository.CandidateRepository, candidateMediaRepository repository.CandidateMediaRepository, objectStore storage.ObjectStore, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) UploadService {
 	return &UploadServiceImpl{
-		UploadRepository: uploadRepository,
-		EnvConfig:        envConfig,
-		DB:               db,
-		Validate:         validate,
+		UploadRepository:         uploadRepository,
+		CandidateRepository:      candidateRepository,
+		CandidateMediaRepository: candidateMediaRepository,
+		ObjectStore:              objectStore,
+		EnvConfig:                envConfig,
+		DB:                       db,
+		Validate:                 validate,
 	}
 }
 
 type UploadServiceImpl struct {
-	UploadRepository repository.UploadRepository
-	EnvConfig        *envConfig.Config
-	DB               *sql.DB
-	Validate         *validator.Validate
+	UploadRepository         repository.UploadRepository
+	CandidateRepository      repository.CandidateRepository
+	CandidateMediaRepository repository.CandidateMediaRepository
+	ObjectStore              storage.ObjectStore
+	EnvConfig                *envConfig.Config
+	DB                       *sql.DB
+	Validate                 *validator.Validate
 }
 
-func (service *UploadServiceImpl) CreatePresignedURL(ctx context.Context, fileName string) (web.PresignedURLResponse, error) {
-	// Load S3 default config
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(service.EnvConfig.S3AccessKeyId, service.EnvConfig.S3SecretAccessKey, "")),
-		config.WithRegion("auto"),
-	)
+// RunReconciler removes orphaned candidate uploads until ctx is cancelled
+func (service *UploadServiceImpl) RunReconciler(ctx context.Context) {
+	envConfig.Log.Info("Starting upload reconciler...")
+
+	ticker := time.NewTicker(UploadReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := service.reconcile(ctx); err != nil {
+			envConfig.Log.Errorf("failed to reconcile candidate uploads: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			envConfig.Log.Info("Upload reconciler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *UploadServiceImpl) CreatePresignedURL(ctx context.Context, actor web.UserResponse, fileName string) (web.PresignedURLResponse, error) {
+	// The key is generated so uploads can't overwrite existing objects
+	key, ok := helper.NewCandidateUploadKey(fileName)
+	if !ok {
+		return web.PresignedURLResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request query parameters",
+			"Query parameter 'filename' must end with .jpg, .jpeg, .png or .webp",
+			fmt.Errorf("%w: unsupported photo file name '%s'", appError.ErrValidation, fileName),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return web.PresignedURLResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err),
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
 		)
 	}
+	defer helper.RollbackQuietly(tx)
 
-	// Create S3 client
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(service.EnvConfig.S3URL)
+	// Track the key so it can be cleaned up when it is never used
+	_, err = service.UploadRepository.Save(ctx, tx, domain.CandidateUpload{
+		UploadKey: key,
+		Status:    domain.CandidateUploadStatusIssued,
+		IssuedBy:  &actor.ID,
 	})
-
-	// Presigned URL client
-	presignClient := s3.NewPresignClient(client)
+	if err != nil {
+		return web.PresignedURLResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save candidate upload: %w", err),
+		)
+	}
 
 	// Create presigned URL for PutObject in 5 minutes
-	presignResult, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(service.EnvConfig.S3Bucket),
-		Key:    aws.String(fileName),
-	}, s3.WithPresignExpires(5*time.Minute))
+	presignedURL, err := service.ObjectStore.PresignPut(ctx, key, 5*time.Minute)
 	if err != nil {
 		return web.PresignedURLResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
@@ -71,8 +142,518 @@ func (service *UploadServiceImpl) CreatePresignedURL(ctx context.Context, fileNa
 		)
 	}
 
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.PresignedURLResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
 	return web.PresignedURLResponse{
-		URL:      presignResult.URL,
-		FileName: fileName,
+		URL:      presignedURL,
+		FileName: key,
 	}, nil
 }
+
+func (service *UploadServiceImpl) ConfirmCandidatePhoto(ctx context.Context, request web.CandidateUploadConfirmRequest) (web.CandidatePhotoResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidatePhotoResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	upload, data, contentType, err := service.readUpload(ctx, request.FileName, "Photo", CandidatePhotoMaxSize, candidatePhotoTypes)
+	if err != nil {
+		return web.CandidatePhotoResponse{}, err
+	}
+
+	// Decode and re-encode the photo, the content must match the declared type
+	variants, err := imaging.ProcessPhoto(data)
+	if err == nil && variants.Format != candidatePhotoTypes[contentType] {
+		err = fmt.Errorf("%w: content is %s but declared as %s", imaging.ErrUnsupportedImage, variants.Format, contentType)
+	}
+	if err != nil {
+		if errors.Is(err, imaging.ErrUnsupportedImage) {
+			service.rejectUpload(ctx, upload)
+			return web.CandidatePhotoResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid photo",
+				"Photo must be a valid JPEG, PNG or WebP image of at most 40 megapixels",
+				fmt.Errorf("%w: %v", appError.ErrValidation, err),
+			)
+		}
+
+		return web.CandidatePhotoResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to process candidate photo: %w", err),
+		)
+	}
+
+	// Store the variants
+	photoKey, thumbnailKey := helper.CandidatePhotoKeys(request.FileName)
+	for key, variant := range map[string]imaging.Variant{photoKey: variants.Full, thumbnailKey: variants.Thumbnail} {
+		err = service.ObjectStore.Put(ctx, key, bytes.NewReader(variant.Content), "image/jpeg")
+		if err != nil {
+			return web.CandidatePhotoResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("%w: failed to put candidate photo variant: %v", appError.ErrObjectStorage, err),
+			)
+		}
+	}
+
+	// The original may still hold EXIF data, so it is not kept
+	if err = service.confirmUpload(ctx, upload, photoKey); err != nil {
+		return web.CandidatePhotoResponse{}, err
+	}
+
+	// Create presigned URLs for GetObject in 24 hours
+	photoURL, err := service.ObjectStore.PresignGet(ctx, photoKey, 24*time.Hour)
+	if err != nil {
+		return web.CandidatePhotoResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrCreatePresignedGet, err),
+		)
+	}
+
+	thumbnailURL, err := service.ObjectStore.PresignGet(ctx, thumbnailKey, 24*time.Hour)
+	if err != nil {
+		return web.CandidatePhotoResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrCreatePresignedGet, err),
+		)
+	}
+
+	return web.CandidatePhotoResponse{
+		PhotoKey:     photoKey,
+		PhotoURL:     photoURL,
+		ThumbnailURL: thumbnailURL,
+		Width:        variants.Full.Width,
+		Height:       variants.Full.Height,
+	}, nil
+}
+
+func (service *UploadServiceImpl) ConfirmCandidateDocument(ctx context.Context, request web.CandidateUploadConfirmRequest) (web.CandidateDocumentResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CandidateDocumentResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	upload, data, _, err := service.readUpload(ctx, request.FileName, "Document", CandidateDocumentMaxSize, candidateDocumentTypes)
+	if err != nil {
+		return web.CandidateDocumentResponse{}, err
+	}
+
+	// The content type is set by the client, so check the file itself too
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		service.rejectUpload(ctx, upload)
+		return web.CandidateDocumentResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid document",
+			"Document must be a valid PDF file",
+			fmt.Errorf("%w: candidate document has no PDF header", appError.ErrValidation),
+		)
+	}
+
+	// Store the document under its own key, so the upload can be cleaned up like photos
+	documentKey := helper.CandidateDocumentKey(request.FileName)
+	err = service.ObjectStore.Put(ctx, documentKey, bytes.NewReader(data), "application/pdf")
+	if err != nil {
+		return web.CandidateDocumentResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to put candidate document: %v", appError.ErrObjectStorage, err),
+		)
+	}
+
+	if err = service.confirmUpload(ctx, upload, documentKey); err != nil {
+		return web.CandidateDocumentResponse{}, err
+	}
+
+	// Create presigned URL for GetObject in 24 hours
+	documentURL, err := service.ObjectStore.PresignGet(ctx, documentKey, 24*time.Hour)
+	if err != nil {
+		return web.CandidateDocumentResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrCreatePresignedGet, err),
+		)
+	}
+
+	return web.CandidateDocumentResponse{
+		DocumentKey: documentKey,
+		DocumentURL: documentURL,
+		Size:        len(data),
+	}, nil
+}
+
+// readUpload checks an issued upload with HEAD, then downloads it. Uploads
+// that are too large or have a content type not in contentTypes are rejected.
+// kind names the file in error messages, e.g. "Photo".
+func (service *UploadServiceImpl) readUpload(ctx context.Context, key, kind string, maxSize int64, contentTypes map[string]string) (domain.CandidateUpload, []byte, string, error) {
+	// Only keys handed out by CreatePresignedURL can be confirmed
+	if !helper.IsCandidateUploadKey(key) {
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"File name must be the 'file_name' returned by GET /api/upload/candidates/presigned-url",
+			fmt.Errorf("%w: '%s' is not a candidate upload key", appError.ErrValidation, key),
+		)
+	}
+
+	upload, err := service.getIssuedUpload(ctx, key)
+	if err != nil {
+		return domain.CandidateUpload{}, nil, "", err
+	}
+
+	// Check the uploaded object before downloading it
+	info, err := service.ObjectStore.Head(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+				http.StatusNotFound,
+				fmt.Sprintf("%s not found", kind),
+				fmt.Sprintf("%s '%s' has not been uploaded. Upload it to the presigned URL first", kind, key),
+				err,
+			)
+		}
+
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to head candidate upload: %v", appError.ErrObjectStorage, err),
+		)
+	}
+
+	if info.Size > maxSize {
+		service.rejectUpload(ctx, upload)
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Invalid %s", strings.ToLower(kind)),
+			fmt.Sprintf("%s is %d bytes, the maximum size is %d bytes (%d MB)", kind, info.Size, maxSize, maxSize>>20),
+			fmt.Errorf("%w: candidate upload is too large", appError.ErrValidation),
+		)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(info.ContentType)
+	if _, ok := contentTypes[contentType]; !ok {
+		accepted := make([]string, 0, len(contentTypes))
+		for accept := range contentTypes {
+			accepted = append(accepted, accept)
+		}
+		sort.Strings(accepted)
+
+		service.rejectUpload(ctx, upload)
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Invalid %s", strings.ToLower(kind)),
+			fmt.Sprintf("%s content type '%s' is not supported, use %s", kind, info.ContentType, strings.Join(accepted, ", ")),
+			fmt.Errorf("%w: unsupported candidate upload content type", appError.ErrValidation),
+		)
+	}
+
+	// Download the file, reading one byte past the limit in case it changed since HEAD
+	body, err := service.ObjectStore.Get(ctx, key)
+	if err != nil {
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to get candidate upload: %v", appError.ErrObjectStorage, err),
+		)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: failed to read candidate upload: %v", appError.ErrObjectStorage, err),
+		)
+	}
+	if int64(len(data)) > maxSize {
+		service.rejectUpload(ctx, upload)
+		return domain.CandidateUpload{}, nil, "", appError.NewAppError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Invalid %s", strings.ToLower(kind)),
+			fmt.Sprintf("%s is larger than the maximum size of %d bytes (%d MB)", kind, maxSize, maxSize>>20),
+			fmt.Errorf("%w: candidate upload is too large", appError.ErrValidation),
+		)
+	}
+
+	return upload, data, contentType, nil
+}
+
+// confirmUpload marks the upload as confirmed into objectKey and deletes the original
+func (service *UploadServiceImpl) confirmUpload(ctx context.Context, upload domain.CandidateUpload, objectKey string) error {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	upload.Status = domain.CandidateUploadStatusConfirmed
+	upload.ObjectKey = objectKey
+	err = service.UploadRepository.UpdateStatus(ctx, tx, upload)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update candidate upload status: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.deleteUpload(ctx, upload.UploadKey)
+
+	return nil
+}
+
+// getIssuedUpload returns the tracked upload of key, which must still be
+// waiting for confirmation
+func (service *UploadServiceImpl) getIssuedUpload(ctx context.Context, key string) (domain.CandidateUpload, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.CandidateUpload{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	upload, err := service.UploadRepository.GetByUploadKey(ctx, tx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CandidateUpload{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Upload not found",
+				fmt.Sprintf("Upload '%s' was not issued by GET /api/upload/candidates/presigned-url", key),
+				fmt.Errorf("candidate upload '%s' not found: %w", key, err),
+			)
+		}
+
+		return domain.CandidateUpload{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get candidate upload: %w", err),
+		)
+	}
+
+	switch upload.Status {
+	case domain.CandidateUploadStatusIssued:
+		return upload, nil
+	case domain.CandidateUploadStatusConfirmed:
+		return domain.CandidateUpload{}, appError.NewAppError(
+			http.StatusConflict,
+			"Photo already confirmed",
+			fmt.Sprintf("Upload '%s' is already confirmed as '%s'", key, upload.ObjectKey),
+			fmt.Errorf("candidate upload '%s' is already confirmed", key),
+		)
+	default:
+		return domain.CandidateUpload{}, appError.NewAppError(
+			http.StatusConflict,
+			"Upload is no longer valid",
+			fmt.Sprintf("Upload '%s' is %s, request a new presigned URL", key, upload.Status),
+			fmt.Errorf("candidate upload '%s' is %s", key, upload.Status),
+		)
+	}
+}
+
+// rejectUpload deletes an upload that failed validation and marks it as
+// rejected. Failures are only logged, the reconciler removes what is left.
+func (service *UploadServiceImpl) rejectUpload(ctx context.Context, upload domain.CandidateUpload) {
+	service.deleteUpload(ctx, upload.UploadKey)
+
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		appError.LogError(fmt.Errorf("%w: %v", appError.ErrTransaction, err), "failed to mark candidate upload as rejected")
+		return
+	}
+	defer helper.RollbackQuietly(tx)
+
+	upload.Status = domain.CandidateUploadStatusRejected
+	if err = service.UploadRepository.UpdateStatus(ctx, tx, upload); err != nil {
+		appError.LogError(err, "failed to mark candidate upload as rejected")
+		return
+	}
+
+	appError.LogError(tx.Commit(), "failed to mark candidate upload as rejected")
+}
+
+// reconcile deletes objects under helper.CandidatePrefix that are older than
+// the grace period and not used by any candidate photo or media, then expires
+// uploads that were never confirmed. Deleting can take long, so every key is
+// checked against the database again right before it is deleted. No
+// transaction is held open while objects are deleted.
+func (service *UploadServiceImpl) reconcile(ctx context.Context) error {
+	objects, err := service.ObjectStore.List(ctx, helper.CandidatePrefix)
+	if err != nil {
+		return fmt.Errorf("%w: failed to list candidate objects: %v", appError.ErrObjectStorage, err)
+	}
+
+	usedKeys, err := service.usedKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deletedKeys []string
+	for _, object := range objects {
+		if usedKeys[object.Key] || time.Since(object.LastModified) < UploadOrphanGracePeriod {
+			continue
+		}
+
+		// A candidate or media may have started using the key since usedKeys was read
+		used, err := service.isKeyUsed(ctx, object.Key)
+		if err != nil {
+			appError.LogError(err, fmt.Sprintf("failed to check orphaned object '%s'", object.Key))
+			continue
+		}
+		if used {
+			continue
+		}
+
+		if err := service.ObjectStore.Delete(ctx, object.Key); err != nil {
+			appError.LogError(fmt.Errorf("%w: %v", appError.ErrObjectStorage, err), fmt.Sprintf("failed to delete orphaned object '%s'", object.Key))
+			continue
+		}
+		deletedKeys = append(deletedKeys, object.Key)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	err = service.UploadRepository.MarkDeleted(ctx, tx, deletedKeys)
+	if err != nil {
+		return fmt.Errorf("failed to mark candidate uploads as deleted: %w", err)
+	}
+
+	expired, err := service.UploadRepository.ExpireIssued(ctx, tx, UploadOrphanGracePeriod)
+	if err != nil {
+		return fmt.Errorf("failed to expire candidate uploads: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	if len(deletedKeys) > 0 || expired > 0 {
+		envConfig.Log.Infof("Upload reconciler deleted %d orphaned objects and expired %d uploads", len(deletedKeys), expired)
+	}
+
+	return nil
+}
+
+// usedKeys returns the keys of every candidate photo and media file
+func (service *UploadServiceImpl) usedKeys(ctx context.Context) (map[string]bool, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	candidates, err := service.CandidateRepository.GetAll(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all candidates: %w", err)
+	}
+
+	mediaKeys, err := service.CandidateMediaRepository.GetObjectKeys(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate media keys: %w", err)
+	}
+
+	// Photos and posters are used together with their thumbnail
+	usedKeys := make(map[string]bool, (len(candidates)+len(mediaKeys))*2)
+	for _, key := range mediaKeys {
+		usedKeys[key] = true
+		usedKeys[helper.CandidateThumbnailKey(key)] = true
+	}
+	for _, candidate := range candidates {
+		usedKeys[candidate.PhotoKey] = true
+		usedKeys[helper.CandidateThumbnailKey(candidate.PhotoKey)] = true
+	}
+
+	return usedKeys, nil
+}
+
+// isKeyUsed reports whether a candidate photo or media uses the key. A
+// thumbnail is used when its full size photo is.
+func (service *UploadServiceImpl) isKeyUsed(ctx context.Context, key string) (bool, error) {
+	keys := []string{key}
+	if photoKey := helper.CandidateThumbnailPhotoKey(key); photoKey != "" {
+		keys = append(keys, photoKey)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	used, err := service.CandidateRepository.IsObjectKeyUsed(ctx, tx, keys)
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether key '%s' is used: %w", key, err)
+	}
+
+	return used, nil
+}
+
+// deleteUpload removes a rejected or processed upload. A failure is only
+// logged because the photo itself has already been handled.
+func (service *UploadServiceImpl) deleteUpload(ctx context.Context, key string) {
+	if err := service.ObjectStore.Delete(ctx, key); err != nil {
+		appError.LogError(fmt.Errorf("%w: %v", appError.ErrObjectStorage, err), fmt.Sprintf("failed to delete candidate upload '%s'", key))
+	}
+}
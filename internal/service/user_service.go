@@ -3,19 +3,36 @@ package service
 import (
 	"context"
 
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
 )
 
 type UserService interface {
-	Create(ctx context.Context, request web.UserCreateRequest) (web.UserResponse, error)
-	UpdateCurrent(ctx context.Context, sessionId string, request web.UserUpdateCurrentRequest) (web.UserResponse, error)
+	Create(ctx context.Context, actor web.UserResponse, request web.UserCreateRequest) (web.UserResponse, error)
+	UpdateCurrent(ctx context.Context, current web.UserResponse, request web.UserUpdateCurrentRequest) (web.ProfileChangeRequestResponse, error)
+	GetCurrentProfileChanges(ctx context.Context, userId int) ([]web.ProfileChangeRequestResponse, error)
+	GetProfileChanges(ctx context.Context, status string) ([]web.ProfileChangeRequestResponse, error)
+	ApproveProfileChange(ctx context.Context, actor web.UserResponse, requestId int) (web.ProfileChangeRequestResponse, error)
+	RejectProfileChange(ctx context.Context, actor web.UserResponse, requestId int, request web.ProfileChangeRejectRequest) (web.ProfileChangeRequestResponse, error)
 	GetCurrent(ctx context.Context, sessionId string) (web.UserResponse, error)
 	GetByNIM(ctx context.Context, nim string) (web.UserGetByNimResponse, error)
 	GetAdmins(ctx context.Context) ([]web.AdminResponse, error)
 	GetById(ctx context.Context, userId int) (web.UserResponse, error)
-	GetAll(ctx context.Context) ([]web.UserResponse, error)
-	UpdateById(ctx context.Context, userId int, request web.UserUpdateByIdRequest) (web.UserResponse, error)
-	DeleteById(ctx context.Context, userId int) error
-	CreateBulk(ctx context.Context, request []web.UserCreateRequest) ([]web.UserResponse, error)
+	GetAll(ctx context.Context, request web.UserListRequest) ([]web.UserResponse, web.PaginationMeta, error)
+	UpdateById(ctx context.Context, actor web.UserResponse, userId int, request web.UserUpdateByIdRequest) (web.UserResponse, error)
+	DeleteById(ctx context.Context, actor web.UserResponse, userId int) error
+	DeactivateById(ctx context.Context, actor web.UserResponse, userId int, request web.UserDeactivateRequest) (web.UserResponse, error)
+	RestoreById(ctx context.Context, actor web.UserResponse, userId int, request web.UserRestoreRequest) (web.UserResponse, error)
+	ImportUsers(ctx context.Context, rows helper.UserRowReader, dryRun bool) (web.UserImportResponse, error)
+	ExportVoterRoll(ctx context.Context, request web.UserVoterRollExportRequest) (web.FileStream, error)
+	SyncUsers(ctx context.Context, actor web.UserResponse, rows helper.UserRowReader, dryRun bool, deactivateMissing bool) (web.UserSyncResponse, error)
+	NormalizePhoneNumbers(ctx context.Context, dryRun bool) (web.PhoneNumberMigrationReport, error)
 	GeneratePassword(ctx context.Context) error
+	ExportCredentialSheet(ctx context.Context, actor web.UserResponse, request web.UserCredentialSheetRequest) (web.FileResponse, error)
+	GenerateVotingTokens(ctx context.Context, actor web.UserResponse, request web.UserGenerateVotingTokenRequest) (web.UserGenerateVotingTokenResponse, error)
+	RegeneratePasswords(ctx context.Context, actor web.UserResponse, request web.UserRegeneratePasswordRequest) (web.UserRegeneratePasswordResponse, error)
+	ChangeCurrentPassword(ctx context.Context, userId int, sessionId string, request web.UserChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, request web.UserForgotPasswordRequest) error
+	ResendPasswordResetOTP(ctx context.Context, userId int) error
+	ResetPassword(ctx context.Context, request web.UserResetPasswordRequest) error
 }
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -9,36 +8,79 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/cache"
 	envConfig "github.com/mhaatha/HIMA-TI-e-Election/internal/config"
 	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/helper"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/notifier"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/phone"
 	"github.com/mhaatha/HIMA-TI-e-Election/internal/repository"
 )
 
-func NewUserService(userRepository repository.UserRepository, votingAccessRepository repository.VotingAccessRepository, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) UserService {
+// Password reset OTP rules
+const (
+	PasswordResetOTPTTL         = 5 * time.Minute
+	PasswordResetOTPCooldown    = 1 * time.Minute
+	PasswordResetOTPMaxAttempts = 5
+)
+
+// UserListDefaultPageSize is used when GET /api/users has no page_size
+const UserListDefaultPageSize = 20
+
+// Audit reasons of deactivations and restores done by a roster sync
+const (
+	userSyncDeactivateReason = "Missing from roster sync"
+	userSyncRestoreReason    = "Found in roster sync"
+)
+
+// UserImportBatchSize is how many roster rows are checked at a time and how
+// many users are inserted or updated per statement
+const UserImportBatchSize = 500
+
+func NewUserService(userRepository repository.UserRepository, votingAccessRepository repository.VotingAccessRepository, authRepository repository.AuthRepository, passwordResetRepository repository.PasswordResetRepository, outboxRepository repository.OutboxRepository, auditLogRepository repository.AuditLogRepository, votingTokenRepository repository.VotingTokenRepository, profileChangeRepository repository.ProfileChangeRepository, votingPeriodRepository repository.VotingPeriodRepository, sessionCache *cache.SessionCache, envConfig *envConfig.Config, db *sql.DB, validate *validator.Validate) UserService {
 	return &UserServiceImpl{
-		UserRepository:         userRepository,
-		VotingAccessRepository: votingAccessRepository,
-		EnvConfig:              envConfig,
-		DB:                     db,
-		Validate:               validate,
+		UserRepository:          userRepository,
+		VotingAccessRepository:  votingAccessRepository,
+		AuthRepository:          authRepository,
+		PasswordResetRepository: passwordResetRepository,
+		OutboxRepository:        outboxRepository,
+		AuditLogRepository:      auditLogRepository,
+		VotingTokenRepository:   votingTokenRepository,
+		ProfileChangeRepository: profileChangeRepository,
+		VotingPeriodRepository:  votingPeriodRepository,
+		SessionCache:            sessionCache,
+		EnvConfig:               envConfig,
+		DB:                      db,
+		Validate:                validate,
 	}
 }
 
 type UserServiceImpl struct {
-	UserRepository         repository.UserRepository
-	VotingAccessRepository repository.VotingAccessRepository
-	EnvConfig              *envConfig.Config
-	DB                     *sql.DB
-	Validate               *validator.Validate
+	UserRepository          repository.UserRepository
+	VotingAccessRepository  repository.VotingAccessRepository
+	AuthRepository          repository.AuthRepository
+	PasswordResetRepository repository.PasswordResetRepository
+	OutboxRepository        repository.OutboxRepository
+	AuditLogRepository      repository.AuditLogRepository
+	VotingTokenRepository   repository.VotingTokenRepository
+	ProfileChangeRepository repository.ProfileChangeRepository
+	VotingPeriodRepository  repository.VotingPeriodRepository
+	SessionCache            *cache.SessionCache
+	EnvConfig               *envConfig.Config
+	DB                      *sql.DB
+	Validate                *validator.Validate
 }
 
-func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCreateRequest) (web.UserResponse, error) {
+func (service *UserServiceImpl) Create(ctx context.Context, actor web.UserResponse, request web.UserCreateRequest) (web.UserResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
@@ -50,6 +92,15 @@ func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCrea
 		)
 	}
 
+	// Only users allowed to manage roles can grant a staff role
+	err = checkRoleAssignment(actor, request.Role)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	// Phone numbers are stored in their canonical +62 form
+	request.PhoneNumber, _ = phone.Normalize(request.PhoneNumber)
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -116,7 +167,7 @@ func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCrea
 	}
 
 	// Only role with student can have voting access
-	if user.Role == "student" {
+	if user.Role == domain.RoleStudent {
 		err := service.VotingAccessRepository.Create(ctx, tx, domain.VotingAccess{
 			UserId: user.Id,
 			Hashed: helper.HashNIM(user.NIM),
@@ -145,11 +196,13 @@ func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCrea
 	return helper.ToUserResponse(user), nil
 }
 
-func (service *UserServiceImpl) UpdateCurrent(ctx context.Context, sessionId string, request web.UserUpdateCurrentRequest) (web.UserResponse, error) {
+// UpdateCurrent queues the corrections a user asks for on their own profile.
+// Nothing is changed until an admin approves the request.
+func (service *UserServiceImpl) UpdateCurrent(ctx context.Context, current web.UserResponse, request web.UserUpdateCurrentRequest) (web.ProfileChangeRequestResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusBadRequest,
 			"Invalid request payload",
 			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
@@ -157,10 +210,13 @@ func (service *UserServiceImpl) UpdateCurrent(ctx context.Context, sessionId str
 		)
 	}
 
+	// Phone numbers are stored in their canonical +62 form
+	request.PhoneNumber, _ = phone.Normalize(request.PhoneNumber)
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -169,76 +225,65 @@ func (service *UserServiceImpl) UpdateCurrent(ctx context.Context, sessionId str
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Create map of fields to update
-	updates := make(map[string]interface{})
-
-	// Add fields to update map if they are provided in the request
-	if request.NIM != "" {
-		updates["nim"] = request.NIM
-	}
-	if request.FullName != "" {
-		updates["full_name"] = request.FullName
-	}
-	if request.StudyProgram != "" {
-		updates["study_program"] = request.StudyProgram
-	}
-
 	// Call repository to get current user
-	user, err := service.UserRepository.GetUserBySession(ctx, tx, sessionId)
+	user, err := service.UserRepository.GetById(ctx, tx, current.ID)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusNotFound,
 			"User not found",
 			"The user you are trying to update does not exist.",
-			fmt.Errorf("failed to get user by session: %w", err),
+			fmt.Errorf("failed to get user with id %v: %w", current.ID, err),
 		)
 	}
 
-	// If NIM is provided, check if it already used by another user
-	if user.NIM != request.NIM {
-		_, err := service.UserRepository.GetByNIM(ctx, tx, request.NIM)
-		// if err == nil it means NIM already used by another user
-		if err == nil {
-			return web.UserResponse{}, appError.NewAppError(
-				http.StatusBadRequest,
-				"NIM already exists",
-				"The NIM you provided already exists. Try another NIM or try logging in instead.",
-				fmt.Errorf("user with NIM %s already exists: %w", request.NIM, appError.ErrNIMAlreadyExists),
-			)
-		}
+	// Only fields that differ from the current profile are requested
+	changes := make(map[string]domain.ProfileFieldChange)
+	if request.NIM != "" && request.NIM != user.NIM {
+		changes["nim"] = domain.ProfileFieldChange{Old: user.NIM, New: request.NIM}
+	}
+	if request.FullName != "" && request.FullName != user.FullName {
+		changes["full_name"] = domain.ProfileFieldChange{Old: user.FullName, New: request.FullName}
+	}
+	if request.StudyProgram != "" && request.StudyProgram != user.StudyProgram {
+		changes["study_program"] = domain.ProfileFieldChange{Old: user.StudyProgram, New: request.StudyProgram}
+	}
+	if request.PhoneNumber != "" && request.PhoneNumber != user.PhoneNumber {
+		changes["phone_number"] = domain.ProfileFieldChange{Old: user.PhoneNumber, New: request.PhoneNumber}
 	}
 
-	// Call repository to update current user
-	updatedUser, err := service.UserRepository.UpdatePartial(ctx, tx, user.Id, updates)
-	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
-			http.StatusNotFound,
-			"User not found",
-			"The user you are trying to update does not exist.",
-			fmt.Errorf("failed to update user: %w", err),
+	if len(changes) == 0 {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"No changes",
+			"The data you provided is the same as your current profile",
+			fmt.Errorf("%w: profile change request without changes", appError.ErrValidation),
 		)
 	}
 
-	// Update hashed key in voting_access table
-	if user.NIM != request.NIM {
-		err := service.VotingAccessRepository.Update(ctx, tx, domain.VotingAccess{
-			UserId: user.Id,
-			Hashed: helper.HashNIM(request.NIM),
-		})
+	// Reject changes that could never be approved
+	err = service.checkProfileChanges(ctx, tx, user, changes)
+	if err != nil {
+		return web.ProfileChangeRequestResponse{}, err
+	}
 
-		if err != nil {
-			return web.UserResponse{}, appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to create voting_access: %w", err),
-			)
-		}
+	// A new request replaces the one still pending
+	changeRequest, err := service.ProfileChangeRepository.SavePending(ctx, tx, domain.ProfileChangeRequest{
+		UserId:  user.Id,
+		Changes: changes,
+		Note:    request.Note,
+	})
+	if err != nil {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save profile change request: %w", err),
+		)
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -246,14 +291,14 @@ func (service *UserServiceImpl) UpdateCurrent(ctx context.Context, sessionId str
 		)
 	}
 
-	return helper.ToUserResponse(updatedUser), nil
+	return helper.ToProfileChangeRequestResponse(changeRequest), nil
 }
 
-func (service *UserServiceImpl) GetCurrent(ctx context.Context, sessionId string) (web.UserResponse, error) {
+func (service *UserServiceImpl) GetCurrentProfileChanges(ctx context.Context, userId int) ([]web.ProfileChangeRequestResponse, error) {
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return nil, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -262,25 +307,50 @@ func (service *UserServiceImpl) GetCurrent(ctx context.Context, sessionId string
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Check if user exists
-	user, err := service.UserRepository.GetUserBySession(ctx, tx, sessionId)
+	changeRequests, err := service.ProfileChangeRepository.GetByUserId(ctx, tx, userId)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
-			http.StatusNotFound,
-			"User not found",
-			"The user you are trying to get does not exist.",
-			fmt.Errorf("failed to get user by session: %w", err),
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get profile change requests of user %v: %w", userId, err),
 		)
 	}
 
-	return helper.ToUserResponse(user), nil
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToProfileChangeRequestResponses(changeRequests), nil
 }
 
-func (service *UserServiceImpl) GetByNIM(ctx context.Context, nim string) (web.UserGetByNimResponse, error) {
+func (service *UserServiceImpl) GetProfileChanges(ctx context.Context, status string) ([]web.ProfileChangeRequestResponse, error) {
+	switch status {
+	case "":
+		// Pending requests are the review queue
+		status = domain.ProfileChangeStatusPending
+	case "all":
+		status = ""
+	case domain.ProfileChangeStatusPending, domain.ProfileChangeStatusApproved, domain.ProfileChangeStatusRejected:
+	default:
+		return nil, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request",
+			fmt.Sprintf("Status must be one of pending, approved, rejected or all, got '%s'", status),
+			fmt.Errorf("%w: invalid profile change status %s", appError.ErrValidation, status),
+		)
+	}
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return web.UserGetByNimResponse{}, appError.NewAppError(
+		return nil, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -289,25 +359,34 @@ func (service *UserServiceImpl) GetByNIM(ctx context.Context, nim string) (web.U
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Check if NIM exists
-	user, err := service.UserRepository.GetByNIM(ctx, tx, nim)
+	changeRequests, err := service.ProfileChangeRepository.GetByStatus(ctx, tx, status)
 	if err != nil {
-		return web.UserGetByNimResponse{}, appError.NewAppError(
-			http.StatusNotFound,
-			"User not found",
-			"The user you are trying to get does not exist.",
-			fmt.Errorf("%w: user with NIM %s is not found", appError.ErrNIMNotFound, nim),
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get profile change requests: %w", err),
 		)
 	}
 
-	return helper.ToUserGetByNimResponse(user), nil
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToProfileChangeRequestResponses(changeRequests), nil
 }
 
-func (service *UserServiceImpl) GetAdmins(ctx context.Context) ([]web.AdminResponse, error) {
+func (service *UserServiceImpl) ApproveProfileChange(ctx context.Context, actor web.UserResponse, requestId int) (web.ProfileChangeRequestResponse, error) {
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return []web.AdminResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -316,79 +395,99 @@ func (service *UserServiceImpl) GetAdmins(ctx context.Context) ([]web.AdminRespo
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Get admins
-	admins, err := service.UserRepository.GetAdmins(ctx, tx)
+	changeRequest, err := service.getPendingProfileChange(ctx, tx, actor, requestId)
 	if err != nil {
-		return []web.AdminResponse{}, appError.NewAppError(
-			http.StatusNotFound,
-			"Admins not found",
-			"The admins you are trying to get do not exist.",
-			fmt.Errorf("failed to get admins: %w", err),
-		)
+		return web.ProfileChangeRequestResponse{}, err
 	}
 
-	return helper.ToAdminResponses(admins), nil
-}
-
-func (service *UserServiceImpl) GetById(ctx context.Context, userId int) (web.UserResponse, error) {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
+	user, err := service.UserRepository.GetById(ctx, tx, changeRequest.UserId)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("failed to get user with id %v: %v", changeRequest.UserId, err),
 		)
 	}
-	defer helper.RollbackQuietly(tx)
 
-	// Check if user exists
-	user, err := service.UserRepository.GetById(ctx, tx, userId)
+	err = checkStaffTarget(actor, user, "approve profile changes of")
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
-			http.StatusNotFound,
-			"User not found",
-			"The user you are trying to get does not exist.",
-			fmt.Errorf("failed to get user by id: %w", err),
+		return web.ProfileChangeRequestResponse{}, err
+	}
+
+	// The NIM or phone number may have been taken since the request was made
+	err = service.checkProfileChanges(ctx, tx, user, changeRequest.Changes)
+	if err != nil {
+		return web.ProfileChangeRequestResponse{}, err
+	}
+
+	updates := make(map[string]interface{})
+	for field, change := range changeRequest.Changes {
+		updates[field] = change.New
+	}
+
+	_, err = service.UserRepository.UpdatePartial(ctx, tx, user.Id, updates)
+	if err != nil {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update user: %w", err),
 		)
 	}
 
-	return helper.ToUserResponse(user), nil
-}
+	// Update hashed key in voting_access table
+	if change, ok := changeRequest.Changes["nim"]; ok {
+		err := service.VotingAccessRepository.Update(ctx, tx, domain.VotingAccess{
+			UserId: user.Id,
+			Hashed: helper.HashNIM(change.New),
+		})
+		if err != nil {
+			return web.ProfileChangeRequestResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to update voting_access: %w", err),
+			)
+		}
+	}
 
-func (service *UserServiceImpl) GetAll(ctx context.Context) ([]web.UserResponse, error) {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
+	changeRequest, err = service.ProfileChangeRepository.Review(ctx, tx, requestId, domain.ProfileChangeStatusApproved, actor.ID, "")
 	if err != nil {
-		return []web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("failed to approve profile change request %v: %w", requestId, err),
 		)
 	}
-	defer helper.RollbackQuietly(tx)
 
-	// Get users
-	users, err := service.UserRepository.GetAll(ctx, tx)
+	err = service.auditProfileChange(ctx, tx, actor.ID, domain.AuditActionProfileChangeApproved, changeRequest)
 	if err != nil {
-		return []web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("transaction commit failed: %w", err),
 		)
 	}
 
-	return helper.ToUserResponses(users), nil
+	// Cached sessions still hold the old user data
+	service.SessionCache.DeleteByUserId(user.Id)
+
+	return helper.ToProfileChangeRequestResponse(changeRequest), nil
 }
 
-func (service *UserServiceImpl) UpdateById(ctx context.Context, userId int, request web.UserUpdateByIdRequest) (web.UserResponse, error) {
+func (service *UserServiceImpl) RejectProfileChange(ctx context.Context, actor web.UserResponse, requestId int, request web.ProfileChangeRejectRequest) (web.ProfileChangeRequestResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusBadRequest,
 			"Invalid request payload",
 			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
@@ -399,7 +498,7 @@ func (service *UserServiceImpl) UpdateById(ctx context.Context, userId int, requ
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -408,102 +507,2135 @@ func (service *UserServiceImpl) UpdateById(ctx context.Context, userId int, requ
 	}
 	defer helper.RollbackQuietly(tx)
 
-	// Check if user exists
-	user, err := service.UserRepository.GetByIdWithPassword(ctx, tx, userId)
+	_, err = service.getPendingProfileChange(ctx, tx, actor, requestId)
 	if err != nil {
-		// If user not found
-		if errors.Is(err, pgx.ErrNoRows) {
-			return web.UserResponse{}, appError.NewAppError(
-				http.StatusNotFound,
-				"User not found",
-				fmt.Sprintf("User with id %v does not exist", userId),
-				fmt.Errorf("user with id %v not found: %v", userId, err),
-			)
-		}
+		return web.ProfileChangeRequestResponse{}, err
+	}
 
-		return web.UserResponse{}, appError.NewAppError(
+	changeRequest, err := service.ProfileChangeRepository.Review(ctx, tx, requestId, domain.ProfileChangeStatusRejected, actor.ID, request.Reason)
+	if err != nil {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+			fmt.Errorf("failed to reject profile change request %v: %w", requestId, err),
 		)
 	}
 
-	// Check is user ever voted
-	isVoted, err := service.VotingAccessRepository.IsUserEverVoted(ctx, tx, userId)
+	err = service.auditProfileChange(ctx, tx, actor.ID, domain.AuditActionProfileChangeRejected, changeRequest)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return web.ProfileChangeRequestResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.ProfileChangeRequestResponse{}, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to check if user has voted: %v", err),
+			fmt.Errorf("transaction commit failed: %w", err),
 		)
 	}
-	if isVoted {
-		return web.UserResponse{}, appError.NewAppError(
+
+	return helper.ToProfileChangeRequestResponse(changeRequest), nil
+}
+
+// getPendingProfileChange locks a pending request for review. Reviewers can't
+// review their own requests.
+func (service *UserServiceImpl) getPendingProfileChange(ctx context.Context, tx *sql.Tx, actor web.UserResponse, requestId int) (domain.ProfileChangeRequest, error) {
+	changeRequest, err := service.ProfileChangeRepository.GetByIdForUpdate(ctx, tx, requestId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProfileChangeRequest{}, appError.NewAppError(
+				http.StatusNotFound,
+				"Profile change request not found",
+				fmt.Sprintf("Profile change request with id %v does not exist", requestId),
+				fmt.Errorf("profile change request with id %v not found: %w", requestId, err),
+			)
+		}
+
+		return domain.ProfileChangeRequest{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get profile change request %v: %w", requestId, err),
+		)
+	}
+
+	if changeRequest.Status != domain.ProfileChangeStatusPending {
+		return domain.ProfileChangeRequest{}, appError.NewAppError(
 			http.StatusConflict,
-			"User has already voted",
-			"Can't update user because user has already voted",
-			fmt.Errorf("user has already voted in: %v", err),
+			"Profile change request already reviewed",
+			fmt.Sprintf("Profile change request with id %v is already %s", requestId, changeRequest.Status),
+			fmt.Errorf("profile change request %v is %s", requestId, changeRequest.Status),
 		)
 	}
 
-	// If NIM is provided, check if it already used by another user
-	if user.NIM != request.NIM {
-		_, err := service.UserRepository.GetByNIM(ctx, tx, request.NIM)
+	if changeRequest.UserId == actor.ID {
+		return domain.ProfileChangeRequest{}, appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden",
+			"You can't review your own profile change request",
+			fmt.Errorf("%w: user %v reviewing own profile change request", appError.ErrForbiddenAccess, actor.ID),
+		)
+	}
+
+	return changeRequest, nil
+}
+
+// checkProfileChanges makes sure a requested NIM or phone number is not used
+// by another user, and that the NIM of a user who already voted stays the same
+func (service *UserServiceImpl) checkProfileChanges(ctx context.Context, tx *sql.Tx, user domain.User, changes map[string]domain.ProfileFieldChange) error {
+	if change, ok := changes["nim"]; ok {
+		isVoted, err := service.VotingAccessRepository.IsUserEverVoted(ctx, tx, user.Id)
+		if err != nil {
+			return appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to check if user has voted: %v", err),
+			)
+		}
+		if isVoted {
+			return appError.NewAppError(
+				http.StatusConflict,
+				"User has already voted",
+				"Can't change NIM because user has already voted",
+				fmt.Errorf("user %v has already voted", user.Id),
+			)
+		}
+
+		owner, err := service.UserRepository.GetByNIM(ctx, tx, change.New)
 		// if err == nil it means NIM already used by another user
-		if err == nil {
-			return web.UserResponse{}, appError.NewAppError(
+		if err == nil && owner.Id != user.Id {
+			return appError.NewAppError(
 				http.StatusBadRequest,
 				"NIM already exists",
-				"The NIM you provided already exists. Try another NIM or try logging in instead.",
-				fmt.Errorf("user with NIM %s already exists: %w", request.NIM, appError.ErrNIMAlreadyExists),
+				"The NIM you provided already exists. Try another NIM.",
+				fmt.Errorf("user with NIM %s already exists: %w", change.New, appError.ErrNIMAlreadyExists),
 			)
 		}
 	}
 
-	// If phone_number is provided, check if i already used by another user
-	if user.PhoneNumber != request.PhoneNumber {
-		_, err := service.UserRepository.GetByPhoneNumber(ctx, tx, request.PhoneNumber)
+	if change, ok := changes["phone_number"]; ok {
+		owner, err := service.UserRepository.GetByPhoneNumber(ctx, tx, change.New)
 		// if err == nil it means phone number already used by another user
-		if err == nil {
-			return web.UserResponse{}, appError.NewAppError(
+		if err == nil && owner.Id != user.Id {
+			return appError.NewAppError(
 				http.StatusBadRequest,
 				"Phone number already exists",
 				"The phone number you provided already exists. Try another phone number.",
-				fmt.Errorf("user with phone number %s already exists", request.PhoneNumber),
+				fmt.Errorf("user with phone number %s already exists", change.New),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) auditProfileChange(ctx context.Context, tx *sql.Tx, actorId int, action string, changeRequest domain.ProfileChangeRequest) error {
+	details, err := json.Marshal(map[string]interface{}{
+		"request_id":  changeRequest.Id,
+		"changes":     changeRequest.Changes,
+		"review_note": changeRequest.ReviewNote,
+	})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
+	}
+
+	targetId := changeRequest.UserId
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, []domain.AuditLog{{
+		ActorId:    &actorId,
+		Action:     action,
+		TargetType: domain.AuditTargetUser,
+		TargetId:   &targetId,
+		Details:    details,
+	}})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit log: %w", err),
+		)
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) GetCurrent(ctx context.Context, sessionId string) (web.UserResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Check if user exists
+	user, err := service.UserRepository.GetUserBySession(ctx, tx, sessionId)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"User not found",
+			"The user you are trying to get does not exist.",
+			fmt.Errorf("failed to get user by session: %w", err),
+		)
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) GetByNIM(ctx context.Context, nim string) (web.UserGetByNimResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserGetByNimResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Check if NIM exists
+	user, err := service.UserRepository.GetByNIM(ctx, tx, nim)
+	if err != nil {
+		return web.UserGetByNimResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"User not found",
+			"The user you are trying to get does not exist.",
+			fmt.Errorf("%w: user with NIM %s is not found", appError.ErrNIMNotFound, nim),
+		)
+	}
+
+	return helper.ToUserGetByNimResponse(user), nil
+}
+
+func (service *UserServiceImpl) GetAdmins(ctx context.Context) ([]web.AdminResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return []web.AdminResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Get admins
+	admins, err := service.UserRepository.GetAdmins(ctx, tx)
+	if err != nil {
+		return []web.AdminResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"Admins not found",
+			"The admins you are trying to get do not exist.",
+			fmt.Errorf("failed to get admins: %w", err),
+		)
+	}
+
+	return helper.ToAdminResponses(admins), nil
+}
+
+func (service *UserServiceImpl) GetById(ctx context.Context, userId int) (web.UserResponse, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Check if user exists
+	user, err := service.UserRepository.GetById(ctx, tx, userId)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"User not found",
+			"The user you are trying to get does not exist.",
+			fmt.Errorf("failed to get user by id: %w", err),
+		)
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) GetAll(ctx context.Context, request web.UserListRequest) ([]web.UserResponse, web.PaginationMeta, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return []web.UserResponse{}, web.PaginationMeta{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PageSize == 0 {
+		request.PageSize = UserListDefaultPageSize
+	}
+
+	filter := domain.UserListFilter{
+		Search:       strings.TrimSpace(request.Search),
+		Role:         request.Role,
+		StudyProgram: request.StudyProgram,
+		Status:       request.Status,
+		HasPassword:  request.HasPassword,
+		HasVoted:     request.HasVoted,
+		SortBy:       request.Sort,
+		SortDesc:     request.Order == "desc",
+		Limit:        request.PageSize,
+		Offset:       (request.Page - 1) * request.PageSize,
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return []web.UserResponse{}, web.PaginationMeta{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Count every matching user, then get the requested page
+	total, err := service.UserRepository.CountAll(ctx, tx, filter)
+	if err != nil {
+		return []web.UserResponse{}, web.PaginationMeta{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to count users: %w", err),
+		)
+	}
+
+	users, err := service.UserRepository.GetAll(ctx, tx, filter)
+	if err != nil {
+		return []web.UserResponse{}, web.PaginationMeta{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users: %w", err),
+		)
+	}
+
+	meta := web.PaginationMeta{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalItems: total,
+		TotalPages: (total + request.PageSize - 1) / request.PageSize,
+	}
+
+	userResponses := helper.ToUserResponses(users)
+	if userResponses == nil {
+		userResponses = []web.UserResponse{}
+	}
+
+	return userResponses, meta, nil
+}
+
+func (service *UserServiceImpl) UpdateById(ctx context.Context, actor web.UserResponse, userId int, request web.UserUpdateByIdRequest) (web.UserResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Only users allowed to manage roles can grant a staff role
+	err = checkRoleAssignment(actor, request.Role)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	// Phone numbers are stored in their canonical +62 form
+	request.PhoneNumber, _ = phone.Normalize(request.PhoneNumber)
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Check if user exists
+	user, err := service.UserRepository.GetByIdWithPassword(ctx, tx, userId)
+	if err != nil {
+		// If user not found
+		if errors.Is(err, pgx.ErrNoRows) {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusNotFound,
+				"User not found",
+				fmt.Sprintf("User with id %v does not exist", userId),
+				fmt.Errorf("user with id %v not found: %v", userId, err),
+			)
+		}
+
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+		)
+	}
+
+	// Password, phone and email of staff are as sensitive as their role
+	err = checkStaffTarget(actor, user, "update")
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	// Check is user ever voted
+	isVoted, err := service.VotingAccessRepository.IsUserEverVoted(ctx, tx, userId)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check if user has voted: %v", err),
+		)
+	}
+	if isVoted {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"User has already voted",
+			"Can't update user because user has already voted",
+			fmt.Errorf("user has already voted in: %v", err),
+		)
+	}
+
+	// If NIM is provided, check if it already used by another user
+	if request.NIM != "" && user.NIM != request.NIM {
+		_, err := service.UserRepository.GetByNIM(ctx, tx, request.NIM)
+		// if err == nil it means NIM already used by another user
+		if err == nil {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"NIM already exists",
+				"The NIM you provided already exists. Try another NIM or try logging in instead.",
+				fmt.Errorf("user with NIM %s already exists: %w", request.NIM, appError.ErrNIMAlreadyExists),
+			)
+		}
+	}
+
+	// If phone_number is provided, check if i already used by another user
+	if request.PhoneNumber != "" && user.PhoneNumber != request.PhoneNumber {
+		_, err := service.UserRepository.GetByPhoneNumber(ctx, tx, request.PhoneNumber)
+		// if err == nil it means phone number already used by another user
+		if err == nil {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusBadRequest,
+				"Phone number already exists",
+				"The phone number you provided already exists. Try another phone number.",
+				fmt.Errorf("user with phone number %s already exists", request.PhoneNumber),
+			)
+		}
+	}
+
+	// Delete the existed voting_access if the old user is a student
+	if user.Role == domain.RoleStudent {
+		err = service.VotingAccessRepository.DeleteByUserId(ctx, tx, userId)
+		if err != nil {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to delete voting_access: %v", err),
+			)
+		}
+	}
+
+	// Check the request body, if not empty, swap the user to the request body
+	if request.NIM != "" {
+		user.NIM = request.NIM
+	}
+	if request.FullName != "" {
+		user.FullName = request.FullName
+	}
+	if request.StudyProgram != "" {
+		user.StudyProgram = request.StudyProgram
+	}
+	if request.Password != "" {
+		// Hash the password
+		hashedPassword, err := helper.HashPassword(request.Password)
+		if err != nil {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to hash password: %w", err),
+			)
+		}
+
+		user.Password = hashedPassword
+	}
+	if request.Role != "" {
+		user.Role = request.Role
+	}
+	if request.PhoneNumber != "" {
+		user.PhoneNumber = request.PhoneNumber
+	}
+
+	// Create a new voting_access if the new user is a student
+	// Only role with student can have voting access
+	if user.Role == domain.RoleStudent {
+		err := service.VotingAccessRepository.Create(ctx, tx, domain.VotingAccess{
+			UserId: user.Id,
+			Hashed: helper.HashNIM(user.NIM),
+		})
+
+		if err != nil {
+			return web.UserResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to create voting_access: %w", err),
+			)
+		}
+	}
+
+	// Call repository
+	userResponse, err := service.UserRepository.UpdateById(ctx, tx, userId, user)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update user with id %v: %v", userId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	// Cached sessions still hold the old user data
+	service.SessionCache.DeleteByUserId(userId)
+
+	return helper.ToUserResponse(userResponse), nil
+}
+
+func (service *UserServiceImpl) DeleteById(ctx context.Context, actor web.UserResponse, userId int) error {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Check if user exists
+	user, err := service.UserRepository.GetById(ctx, tx, userId)
+	if err != nil {
+		// If user not found
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appError.NewAppError(
+				http.StatusNotFound,
+				"User not found",
+				fmt.Sprintf("User with id %v does not exist", userId),
+				fmt.Errorf("user with id %v not found: %v", userId, err),
+			)
+		}
+
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+		)
+	}
+
+	err = checkStaffTarget(actor, user, "delete")
+	if err != nil {
+		return err
+	}
+
+	// Check is user ever voted
+	isVoted, err := service.VotingAccessRepository.IsUserEverVoted(ctx, tx, userId)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to check if user has voted: %v", err),
+		)
+	}
+	if isVoted {
+		return appError.NewAppError(
+			http.StatusConflict,
+			"User has already voted",
+			"Can't delete user because user has already voted, deactivate the user instead",
+			fmt.Errorf("user has already voted in: %v", err),
+		)
+	}
+
+	// Call repository
+	err = service.UserRepository.DeleteById(ctx, tx, userId)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update user with id %v: %v", userId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	// Deleted user must not keep using a cached session
+	service.SessionCache.DeleteByUserId(userId)
+
+	return nil
+}
+
+func (service *UserServiceImpl) DeactivateById(ctx context.Context, actor web.UserResponse, userId int, request web.UserDeactivateRequest) (web.UserResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	if actor.ID == userId {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request",
+			"You can't deactivate your own account",
+			fmt.Errorf("%w: user %v deactivating itself", appError.ErrValidation, userId),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	user, err := service.getUserForStatusChange(ctx, tx, actor, userId)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	if user.DeactivatedAt != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"User already deactivated",
+			fmt.Sprintf("User with id %v is already deactivated", userId),
+			fmt.Errorf("%w: user with id %v", appError.ErrUserDeactivated, userId),
+		)
+	}
+
+	err = service.deactivateUsers(ctx, tx, actor.ID, []domain.User{user}, request.Reason)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	user, err = service.UserRepository.GetById(ctx, tx, userId)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	// Deactivated user must not keep using a cached session
+	service.SessionCache.DeleteByUserId(userId)
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) RestoreById(ctx context.Context, actor web.UserResponse, userId int, request web.UserRestoreRequest) (web.UserResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	user, err := service.getUserForStatusChange(ctx, tx, actor, userId)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	if user.DeactivatedAt == nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"User is active",
+			fmt.Sprintf("User with id %v is not deactivated", userId),
+			fmt.Errorf("user with id %v is not deactivated", userId),
+		)
+	}
+
+	err = service.restoreUsers(ctx, tx, actor.ID, []domain.User{user}, request.Reason)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	user, err = service.UserRepository.GetById(ctx, tx, userId)
+	if err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+// getUserForStatusChange gets the user to deactivate or restore. Staff
+// accounts may only be changed by actors who can manage roles.
+func (service *UserServiceImpl) getUserForStatusChange(ctx context.Context, tx *sql.Tx, actor web.UserResponse, userId int) (domain.User, error) {
+	user, err := service.UserRepository.GetById(ctx, tx, userId)
+	if err != nil {
+		// If user not found
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, appError.NewAppError(
+				http.StatusNotFound,
+				"User not found",
+				fmt.Sprintf("User with id %v does not exist", userId),
+				fmt.Errorf("user with id %v not found: %v", userId, err),
+			)
+		}
+
+		return domain.User{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+		)
+	}
+
+	err = checkStaffTarget(actor, user, "deactivate or restore")
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// checkStaffTarget refuses any change to a staff account by an actor who
+// can't manage roles, so admins can't take over a super admin account
+func checkStaffTarget(actor web.UserResponse, user domain.User, action string) error {
+	if user.Role != domain.RoleStudent && !helper.HasPermission(actor.Role, domain.PermissionRoleManage) {
+		return appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden",
+			fmt.Sprintf("Only a super admin can %s staff accounts", action),
+			fmt.Errorf("%w: %s trying to %s %s account", appError.ErrForbiddenAccess, actor.Role, action, user.Role),
+		)
+	}
+
+	return nil
+}
+
+// checkRoleAssignment refuses to give a staff role on behalf of an actor who
+// can't manage roles; leaving the role empty or making a student is always allowed
+func checkRoleAssignment(actor web.UserResponse, role string) error {
+	if role != "" && role != domain.RoleStudent && !helper.HasPermission(actor.Role, domain.PermissionRoleManage) {
+		return appError.NewAppError(
+			http.StatusForbidden,
+			"Forbidden access",
+			fmt.Sprintf("You do not have permission to assign role '%s'", role),
+			fmt.Errorf("%w: %s trying to assign role %s", appError.ErrForbiddenAccess, actor.Role, role),
+		)
+	}
+
+	return nil
+}
+
+// deactivateUsers blocks the users from logging in, ends their sessions and
+// unused voting tokens, and audits the reason. voting_access is kept so their
+// votes stay linked; deactivated users are left out of the voter roll instead.
+// The session cache must be cleared by the caller after commit.
+func (service *UserServiceImpl) deactivateUsers(ctx context.Context, tx *sql.Tx, actorId int, users []domain.User, reason string) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIds := make([]int, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.Id)
+	}
+
+	err := service.UserRepository.DeactivateBulk(ctx, tx, userIds)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to deactivate users in bulk: %w", err),
+		)
+	}
+
+	err = service.AuthRepository.DeleteByUserIds(ctx, tx, userIds)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to delete sessions in bulk: %w", err),
+		)
+	}
+
+	err = service.VotingTokenRepository.InvalidateByUserIds(ctx, tx, userIds)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to invalidate voting tokens: %w", err),
+		)
+	}
+
+	return service.auditUserStatus(ctx, tx, actorId, domain.AuditActionUserDeactivated, userIds, reason)
+}
+
+// restoreUsers reactivates the users and makes sure students are on the
+// voter roll again
+func (service *UserServiceImpl) restoreUsers(ctx context.Context, tx *sql.Tx, actorId int, users []domain.User, reason string) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	var (
+		userIds        []int
+		votingAccesses []domain.VotingAccess
+	)
+	for _, user := range users {
+		userIds = append(userIds, user.Id)
+		if user.Role == domain.RoleStudent {
+			votingAccesses = append(votingAccesses, domain.VotingAccess{
+				UserId: user.Id,
+				Hashed: helper.HashNIM(user.NIM),
+			})
+		}
+	}
+
+	err := service.UserRepository.RestoreBulk(ctx, tx, userIds)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to restore users in bulk: %w", err),
+		)
+	}
+
+	if len(votingAccesses) > 0 {
+		studentIds := make([]int, 0, len(votingAccesses))
+		for _, votingAccess := range votingAccesses {
+			studentIds = append(studentIds, votingAccess.UserId)
+		}
+
+		err = service.VotingAccessRepository.DeleteByUserIds(ctx, tx, studentIds)
+		if err == nil {
+			err = service.VotingAccessRepository.CreateBulk(ctx, tx, votingAccesses)
+		}
+		if err != nil {
+			return appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to recreate voting_access in bulk: %w", err),
+			)
+		}
+	}
+
+	return service.auditUserStatus(ctx, tx, actorId, domain.AuditActionUserRestored, userIds, reason)
+}
+
+func (service *UserServiceImpl) auditUserStatus(ctx context.Context, tx *sql.Tx, actorId int, action string, userIds []int, reason string) error {
+	details, err := json.Marshal(map[string]string{"reason": reason})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
+	}
+
+	auditLogs := make([]domain.AuditLog, 0, len(userIds))
+	for _, userId := range userIds {
+		targetId := userId
+		auditLogs = append(auditLogs, domain.AuditLog{
+			ActorId:    &actorId,
+			Action:     action,
+			TargetType: domain.AuditTargetUser,
+			TargetId:   &targetId,
+			Details:    details,
+		})
+	}
+
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, auditLogs)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit logs: %w", err),
+		)
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) ImportUsers(ctx context.Context, rows helper.UserRowReader, dryRun bool) (web.UserImportResponse, error) {
+	response := web.UserImportResponse{
+		DryRun: dryRun,
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserImportResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Rows are checked and inserted one batch at a time, so the file is never
+	// held in memory. Once a row has a problem nothing more is inserted, but
+	// the rest of the file is still checked to report every problem.
+	importRows := newImportRows(rows)
+	for {
+		batch, err := service.nextImportBatch(importRows)
+		if err != nil {
+			return web.UserImportResponse{}, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		err = service.checkExistingImportRows(ctx, tx, importRows, batch)
+		if err != nil {
+			return web.UserImportResponse{}, err
+		}
+
+		var users []domain.User
+		for _, row := range importRows.validRows(batch) {
+			users = append(users, domain.User{
+				NIM:          row.NIM,
+				FullName:     row.FullName,
+				StudyProgram: row.StudyProgram,
+				PhoneNumber:  row.PhoneNumber,
+			})
+		}
+		response.ValidRows += len(users)
+
+		if dryRun || len(importRows.errors) > 0 {
+			continue
+		}
+
+		// Insert users and their voting_access
+		created, err := service.saveUsersInBatches(ctx, tx, users)
+		if err != nil {
+			return web.UserImportResponse{}, err
+		}
+		response.CreatedRows += created
+	}
+
+	response.TotalRows = importRows.total
+	response.Errors = importRows.sortedErrors()
+
+	if dryRun {
+		return response, nil
+	}
+
+	// The import is all or nothing
+	if len(response.Errors) > 0 {
+		return response, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid rows",
+			fmt.Sprintf("%d of %d rows have problems, nothing was imported", response.TotalRows-response.ValidRows, response.TotalRows),
+			fmt.Errorf("%w: %d invalid import rows", appError.ErrValidation, len(response.Errors)),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserImportResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	return response, nil
+}
+
+// checkExistingImportRows reports the NIMs and phone numbers of batch that
+// are already stored, with one query per column
+func (service *UserServiceImpl) checkExistingImportRows(ctx context.Context, tx *sql.Tx, importRows *importRows, batch []web.UserImportRow) error {
+	// Only the first row of a value is checked, later ones are duplicates already
+	var nims, phoneNumbers []string
+	for _, row := range batch {
+		key := importRowKey{sheet: row.Sheet, row: row.Row}
+		if row.NIM != "" && importRows.nimRows[row.NIM] == key {
+			nims = append(nims, row.NIM)
+		}
+		if row.PhoneNumber != "" && importRows.phoneRows[row.PhoneNumber] == key {
+			phoneNumbers = append(phoneNumbers, row.PhoneNumber)
+		}
+	}
+
+	existingNIMs, err := service.UserRepository.GetExistingNIMs(ctx, tx, nims)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get existing NIMs: %w", err),
+		)
+	}
+	for _, nim := range existingNIMs {
+		importRows.addError(web.ImportRowError{
+			Sheet:   importRows.nimRows[nim].sheet,
+			Row:     importRows.nimRows[nim].row,
+			Column:  "nim",
+			Value:   nim,
+			Message: "NIM already exists",
+		})
+	}
+
+	existingPhoneNumbers, err := service.UserRepository.GetExistingPhoneNumbers(ctx, tx, phoneNumbers)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get existing phone numbers: %w", err),
+		)
+	}
+	for _, phoneNumber := range existingPhoneNumbers {
+		importRows.addError(web.ImportRowError{
+			Sheet:   importRows.phoneRows[phoneNumber].sheet,
+			Row:     importRows.phoneRows[phoneNumber].row,
+			Column:  "phone_number",
+			Value:   phoneNumber,
+			Message: "Phone number already exists",
+		})
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) SyncUsers(ctx context.Context, actor web.UserResponse, rows helper.UserRowReader, dryRun bool, deactivateMissing bool) (web.UserSyncResponse, error) {
+	importRows, err := service.readImportRows(rows)
+	if err != nil {
+		return web.UserSyncResponse{}, err
+	}
+
+	response := web.UserSyncResponse{
+		DryRun:            dryRun,
+		DeactivateMissing: deactivateMissing,
+		TotalRows:         importRows.total,
+		Created:           []string{},
+		Updated:           []web.UserSyncUpdate{},
+		Reactivated:       []string{},
+		Deactivated:       []string{},
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserSyncResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Load the stored users matching the file with one query per column
+	existingUsers, err := service.UserRepository.GetByNIMs(ctx, tx, importRows.nims)
+	if err != nil {
+		return web.UserSyncResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by NIMs: %w", err),
+		)
+	}
+
+	usersByNIM := make(map[string]domain.User, len(existingUsers))
+	for _, user := range existingUsers {
+		usersByNIM[user.NIM] = user
+
+		if user.Role != domain.RoleStudent {
+			importRows.addError(web.ImportRowError{
+				Sheet:   importRows.nimRows[user.NIM].sheet,
+				Row:     importRows.nimRows[user.NIM].row,
+				Column:  "nim",
+				Value:   user.NIM,
+				Message: "NIM belongs to a staff account",
+			})
+		}
+	}
+
+	phoneOwners, err := service.UserRepository.GetByPhoneNumbers(ctx, tx, importRows.phoneNumbers)
+	if err != nil {
+		return web.UserSyncResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by phone numbers: %w", err),
+		)
+	}
+
+	for _, owner := range phoneOwners {
+		row := importRows.phoneRows[owner.PhoneNumber]
+		if importRows.nimRows[owner.NIM] == row {
+			continue
+		}
+
+		importRows.addError(web.ImportRowError{
+			Sheet:   row.sheet,
+			Row:     row.row,
+			Column:  "phone_number",
+			Value:   owner.PhoneNumber,
+			Message: fmt.Sprintf("Phone number already used by NIM %s", owner.NIM),
+		})
+	}
+
+	// Compare every valid row with the stored student
+	var newUsers, changedUsers, reactivatedUsers []domain.User
+	for _, row := range importRows.validRows(importRows.rows) {
+		response.ValidRows++
+
+		user, ok := usersByNIM[row.NIM]
+		if !ok {
+			newUsers = append(newUsers, domain.User{
+				NIM:          row.NIM,
+				FullName:     row.FullName,
+				StudyProgram: row.StudyProgram,
+				PhoneNumber:  row.PhoneNumber,
+			})
+			response.Created = append(response.Created, row.NIM)
+			continue
+		}
+
+		changes := make(map[string]web.UserFieldChange)
+		if user.FullName != row.FullName {
+			changes["full_name"] = web.UserFieldChange{Old: user.FullName, New: row.FullName}
+		}
+		if user.StudyProgram != row.StudyProgram {
+			changes["study_program"] = web.UserFieldChange{Old: user.StudyProgram, New: row.StudyProgram}
+		}
+		if user.PhoneNumber != row.PhoneNumber {
+			changes["phone_number"] = web.UserFieldChange{Old: user.PhoneNumber, New: row.PhoneNumber}
+		}
+
+		if len(changes) > 0 {
+			user.FullName = row.FullName
+			user.StudyProgram = row.StudyProgram
+			user.PhoneNumber = row.PhoneNumber
+
+			changedUsers = append(changedUsers, user)
+			response.Updated = append(response.Updated, web.UserSyncUpdate{
+				NIM:     row.NIM,
+				Changes: changes,
+			})
+		}
+
+		if user.DeactivatedAt != nil {
+			reactivatedUsers = append(reactivatedUsers, user)
+			response.Reactivated = append(response.Reactivated, row.NIM)
+		} else if len(changes) == 0 {
+			response.Unchanged++
+		}
+	}
+
+	// Students that are no longer in the roster
+	var missingUsers []domain.User
+	if deactivateMissing {
+		missingUsers, err = service.UserRepository.GetActiveStudentsExcept(ctx, tx, importRows.nims)
+		if err != nil {
+			return web.UserSyncResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to get students missing from roster: %w", err),
+			)
+		}
+
+		for _, user := range missingUsers {
+			response.Deactivated = append(response.Deactivated, user.NIM)
+		}
+	}
+
+	response.Errors = importRows.sortedErrors()
+
+	if dryRun {
+		return response, nil
+	}
+
+	// The sync is all or nothing
+	if len(response.Errors) > 0 {
+		return response, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid rows",
+			fmt.Sprintf("%d of %d rows have problems, nothing was synced", response.TotalRows-response.ValidRows, response.TotalRows),
+			fmt.Errorf("%w: %d invalid sync rows", appError.ErrValidation, len(response.Errors)),
+		)
+	}
+
+	_, err = service.saveUsersInBatches(ctx, tx, newUsers)
+	if err != nil {
+		return web.UserSyncResponse{}, err
+	}
+
+	for start := 0; start < len(changedUsers); start += UserImportBatchSize {
+		end := min(start+UserImportBatchSize, len(changedUsers))
+
+		err = service.UserRepository.UpdateProfileBulk(ctx, tx, changedUsers[start:end])
+		if err != nil {
+			return web.UserSyncResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to update user profiles in bulk: %w", err),
+			)
+		}
+	}
+
+	// Reactivated students are back on the voter roll
+	err = service.restoreUsers(ctx, tx, actor.ID, reactivatedUsers, userSyncRestoreReason)
+	if err != nil {
+		return web.UserSyncResponse{}, err
+	}
+
+	// Students missing from the roster are deactivated, their history is kept
+	err = service.deactivateUsers(ctx, tx, actor.ID, missingUsers, userSyncDeactivateReason)
+	if err != nil {
+		return web.UserSyncResponse{}, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserSyncResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	// Deactivated users must not keep using a cached session
+	for _, user := range missingUsers {
+		service.SessionCache.DeleteByUserId(user.Id)
+	}
+
+	return response, nil
+}
+
+// saveUsersInBatches inserts users and the voting_access of students,
+// UserImportBatchSize rows per statement, and returns how many were created
+func (service *UserServiceImpl) saveUsersInBatches(ctx context.Context, tx *sql.Tx, users []domain.User) (int, error) {
+	created := 0
+
+	for start := 0; start < len(users); start += UserImportBatchSize {
+		end := min(start+UserImportBatchSize, len(users))
+
+		savedUsers, err := service.UserRepository.SaveBulk(ctx, tx, users[start:end])
+		if err != nil {
+			return created, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to create user in bulk: %w", err),
+			)
+		}
+
+		var votingAccesses []domain.VotingAccess
+		for _, user := range savedUsers {
+			if user.Role == domain.RoleStudent {
+				votingAccesses = append(votingAccesses, domain.VotingAccess{
+					UserId: user.Id,
+					Hashed: helper.HashNIM(user.NIM),
+				})
+			}
+		}
+
+		if len(votingAccesses) > 0 {
+			err = service.VotingAccessRepository.CreateBulk(ctx, tx, votingAccesses)
+			if err != nil {
+				return created, appError.NewAppError(
+					http.StatusInternalServerError,
+					"Internal Server Error",
+					"Failed to process your request due to an unexpected error. Please try again later.",
+					fmt.Errorf("failed to create voting_access in bulk: %w", err),
+				)
+			}
+		}
+
+		created += len(savedUsers)
+	}
+
+	return created, nil
+}
+
+// NormalizePhoneNumbers rewrites every stored phone number to its canonical
+// +62 form. Numbers that cannot be parsed and users whose numbers collide once
+// normalized are left unchanged and reported so they can be fixed by hand.
+func (service *UserServiceImpl) NormalizePhoneNumbers(ctx context.Context, dryRun bool) (web.PhoneNumberMigrationReport, error) {
+	report := web.PhoneNumberMigrationReport{
+		DryRun:     dryRun,
+		Invalid:    []web.PhoneNumberOwner{},
+		Collisions: []web.PhoneNumberCollision{},
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return report, fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	users, err := service.UserRepository.GetAllUnpaginated(ctx, tx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get users: %w", err)
+	}
+	report.TotalUsers = len(users)
+
+	// Group users by their normalized number, in id order
+	var (
+		phoneNumbers []string
+		owners       = make(map[string][]domain.User)
+	)
+	for _, user := range users {
+		phoneNumber, ok := phone.Normalize(user.PhoneNumber)
+		if !ok {
+			report.Invalid = append(report.Invalid, helper.ToPhoneNumberOwner(user))
+			continue
+		}
+
+		if _, ok := owners[phoneNumber]; !ok {
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+		}
+		owners[phoneNumber] = append(owners[phoneNumber], user)
+	}
+
+	var changedUsers []domain.User
+	for _, phoneNumber := range phoneNumbers {
+		group := owners[phoneNumber]
+
+		if len(group) > 1 {
+			collision := web.PhoneNumberCollision{PhoneNumber: phoneNumber}
+			for _, user := range group {
+				collision.Users = append(collision.Users, helper.ToPhoneNumberOwner(user))
+			}
+			report.Collisions = append(report.Collisions, collision)
+			continue
+		}
+
+		user := group[0]
+		if user.PhoneNumber == phoneNumber {
+			report.AlreadyCanonical++
+			continue
+		}
+
+		user.PhoneNumber = phoneNumber
+		changedUsers = append(changedUsers, user)
+	}
+	report.Normalized = len(changedUsers)
+
+	if dryRun {
+		return report, nil
+	}
+
+	for start := 0; start < len(changedUsers); start += UserImportBatchSize {
+		end := min(start+UserImportBatchSize, len(changedUsers))
+
+		err = service.UserRepository.UpdatePhoneNumberBulk(ctx, tx, changedUsers[start:end])
+		if err != nil {
+			return report, fmt.Errorf("failed to update phone numbers in bulk: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return report, fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	return report, nil
+}
+
+func (service *UserServiceImpl) GeneratePassword(ctx context.Context) error {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Get all users with null password
+	users, err := service.UserRepository.GetUsersWithNullPassword(ctx, tx)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users with null password: %w", err),
+		)
+	}
+
+	if len(users) == 0 {
+		envConfig.Log.Info("No users with null password, skipping password generation")
+		return appError.NewAppError(
+			http.StatusOK,
+			"No users with null password",
+			"Password generation skipped",
+			nil,
+		)
+	}
+
+	// Generate, save and queue the passwords
+	err = service.issueCredentials(ctx, tx, users)
+	if err != nil {
+		return err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	for _, user := range users {
+		service.SessionCache.DeleteByUserId(user.Id)
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) RegeneratePasswords(ctx context.Context, actor web.UserResponse, request web.UserRegeneratePasswordRequest) (web.UserRegeneratePasswordResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Refuse to regenerate every student's password by accident
+	if len(request.UserIds) == 0 && request.StudyProgram == "" && !request.NotVoted {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"At least one of 'user_ids', 'study_program' or 'not_voted' is required",
+			fmt.Errorf("%w: regenerate passwords without filter", appError.ErrValidation),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Get students matching the filter
+	users, err := service.UserRepository.GetByCredentialFilter(ctx, tx, domain.UserCredentialFilter{
+		Ids:          request.UserIds,
+		StudyProgram: request.StudyProgram,
+		NotVoted:     request.NotVoted,
+	})
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by credential filter: %w", err),
+		)
+	}
+
+	if len(users) == 0 {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"Users not found",
+			"No students match the given filter",
+			fmt.Errorf("%w: no students match credential filter", appError.ErrUserNotFound),
+		)
+	}
+
+	// Generate, save and queue the passwords
+	err = service.issueCredentials(ctx, tx, users)
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, err
+	}
+
+	// Audit every regenerated password
+	details, err := json.Marshal(request)
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
+	}
+
+	actorId := actor.ID
+	userIds := make([]int, 0, len(users))
+	auditLogs := make([]domain.AuditLog, 0, len(users))
+	for _, user := range users {
+		targetId := user.Id
+		userIds = append(userIds, user.Id)
+		auditLogs = append(auditLogs, domain.AuditLog{
+			ActorId:    &actorId,
+			Action:     domain.AuditActionPasswordRegenerated,
+			TargetType: domain.AuditTargetUser,
+			TargetId:   &targetId,
+			Details:    details,
+		})
+	}
+
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, auditLogs)
+	if err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit logs: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserRegeneratePasswordResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	for _, userId := range userIds {
+		service.SessionCache.DeleteByUserId(userId)
+	}
+
+	envConfig.Log.Infof("%s (id %d) regenerated passwords for %d users", actor.FullName, actor.ID, len(users))
+
+	return web.UserRegeneratePasswordResponse{
+		Total:   len(userIds),
+		UserIds: userIds,
+	}, nil
+}
+
+func (service *UserServiceImpl) ExportVoterRoll(ctx context.Context, request web.UserVoterRollExportRequest) (web.FileStream, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.FileStream{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	if request.Format == "" {
+		request.Format = "csv"
+	}
+
+	// Default to the current period
+	period := time.Now().Year()
+	if request.Period != "" {
+		period, _ = strconv.Atoi(request.Period)
+	}
+
+	// Rows are read inside WriteTo, after the response headers are sent
+	writeTo := func(w io.Writer) error {
+		tx, err := service.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return fmt.Errorf("%w: %v", appError.ErrTransaction, err)
+		}
+		defer helper.RollbackQuietly(tx)
+
+		writer, err := helper.NewVoterRollWriter(w, request.Format, period)
+		if err != nil {
+			return fmt.Errorf("failed to create voter roll writer: %w", err)
+		}
+
+		err = service.UserRepository.IterateVoterRoll(ctx, tx, period, func(entry domain.VoterRollEntry) error {
+			return writer.Write(helper.ToVoterRollRow(entry))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write voter roll: %w", err)
+		}
+
+		return writer.Close()
+	}
+
+	return web.FileStream{
+		FileName:    fmt.Sprintf("voter-roll-%d.%s", period, request.Format),
+		ContentType: helper.VoterRollContentType(request.Format),
+		WriteTo:     writeTo,
+	}, nil
+}
+
+func (service *UserServiceImpl) ExportCredentialSheet(ctx context.Context, actor web.UserResponse, request web.UserCredentialSheetRequest) (web.FileResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Refuse to reset every student's password by accident
+	if len(request.UserIds) == 0 && request.StudyProgram == "" && !request.NotVoted {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"At least one of 'user_ids', 'study_program' or 'not_voted' is required",
+			fmt.Errorf("%w: export credential sheet without filter", appError.ErrValidation),
+		)
+	}
+
+	if request.Format == "" {
+		request.Format = "pdf"
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Get students matching the filter
+	users, err := service.UserRepository.GetByCredentialFilter(ctx, tx, domain.UserCredentialFilter{
+		Ids:          request.UserIds,
+		StudyProgram: request.StudyProgram,
+		NotVoted:     request.NotVoted,
+	})
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by credential filter: %w", err),
+		)
+	}
+
+	if len(users) == 0 {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"Users not found",
+			"No students match the given filter",
+			fmt.Errorf("%w: no students match credential filter", appError.ErrUserNotFound),
+		)
+	}
+
+	// Generate and save the passwords, they are handed out on paper instead of queued
+	issuedUsers, err := service.resetPasswords(ctx, tx, users)
+	if err != nil {
+		return web.FileResponse{}, err
+	}
+
+	// Render the file before commit so a failure keeps the old passwords
+	file, err := helper.ToCredentialSheetFile(issuedUsers, request.Format, service.EnvConfig.FrontendURL, time.Now())
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to render credential sheet: %w", err),
+		)
+	}
+
+	// Audit every exported credential
+	details, err := json.Marshal(request)
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
+	}
+
+	actorId := actor.ID
+	auditLogs := make([]domain.AuditLog, 0, len(users))
+	for _, user := range users {
+		targetId := user.Id
+		auditLogs = append(auditLogs, domain.AuditLog{
+			ActorId:    &actorId,
+			Action:     domain.AuditActionCredentialExported,
+			TargetType: domain.AuditTargetUser,
+			TargetId:   &targetId,
+			Details:    details,
+		})
+	}
+
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, auditLogs)
+	if err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit logs: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.FileResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	for _, user := range users {
+		service.SessionCache.DeleteByUserId(user.Id)
+	}
+
+	envConfig.Log.Infof("%s (id %d) exported %s credential sheet for %d users", actor.FullName, actor.ID, request.Format, len(users))
+
+	return file, nil
+}
+
+func (service *UserServiceImpl) GenerateVotingTokens(ctx context.Context, actor web.UserResponse, request web.UserGenerateVotingTokenRequest) (web.UserGenerateVotingTokenResponse, error) {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			"'expires_at' must be in the future",
+			fmt.Errorf("%w: voting token expiry %v is in the past", appError.ErrValidation, *request.ExpiresAt),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Tokens expire when the voting period (the current year) closes, or
+	// earlier when told otherwise
+	period := now.Year()
+	votingPeriod, err := service.VotingPeriodRepository.GetByPeriod(ctx, tx, period)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+				http.StatusConflict,
+				"Voting period not scheduled",
+				fmt.Sprintf("Voting period %v has no schedule, set it before issuing voting tokens", period),
+				fmt.Errorf("voting period %v is not scheduled: %w", period, err),
+			)
+		}
+
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get voting period %v: %w", period, err),
+		)
+	}
+
+	if !votingPeriod.ClosesAt.After(now) {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusConflict,
+			"Voting period has closed",
+			fmt.Sprintf("Voting period %v closed at %v", period, votingPeriod.ClosesAt.Format(time.RFC3339)),
+			fmt.Errorf("voting period %v is closed", period),
+		)
+	}
+
+	expiresAt := votingPeriod.ClosesAt
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(expiresAt) {
+		expiresAt = *request.ExpiresAt
+	}
+
+	// Only eligible students that have not voted yet get a token
+	users, err := service.UserRepository.GetByCredentialFilter(ctx, tx, domain.UserCredentialFilter{
+		Ids:             request.UserIds,
+		StudyProgram:    request.StudyProgram,
+		NotVoted:        true,
+		HasVotingAccess: true,
+	})
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get users by credential filter: %w", err),
+		)
+	}
+
+	if len(users) == 0 {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusNotFound,
+			"Users not found",
+			"No eligible students match the given filter",
+			fmt.Errorf("%w: no eligible students match voting token filter", appError.ErrUserNotFound),
+		)
+	}
+
+	userIds := make([]int, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.Id)
+	}
+
+	// A new token replaces any unused one
+	err = service.VotingTokenRepository.InvalidateByUserIds(ctx, tx, userIds)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to invalidate voting tokens: %w", err),
+		)
+	}
+
+	// Revoked tokens still waiting in the outbox are not sent
+	err = service.OutboxRepository.CancelByUserIds(ctx, tx, userIds, notifier.TemplateVotingToken)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to cancel queued voting token messages: %w", err),
+		)
+	}
+
+	var (
+		votingTokens   []domain.VotingToken
+		outboxMessages []domain.OutboxMessage
+	)
+
+	for _, user := range users {
+		token, err := helper.GenerateVotingToken(helper.DefaultVotingTokenLength)
+		if err != nil {
+			return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to generate voting token: %w", err),
 			)
 		}
-	}
 
-	// Delete the existed voting_access if the old user is a student
-	if user.Role == "student" {
-		err = service.VotingAccessRepository.DeleteByUserId(ctx, tx, userId)
+		votingTokens = append(votingTokens, domain.VotingToken{
+			UserId:      user.Id,
+			HashedToken: helper.HashVotingToken(token),
+			ExpiresAt:   expiresAt.UTC(),
+		})
+
+		message, err := notifier.Render(notifier.TemplateVotingToken, notifier.VotingTokenData{
+			FullName:  user.FullName,
+			Token:     token,
+			LoginURL:  fmt.Sprintf("%s/login?token=%s", strings.TrimRight(service.EnvConfig.FrontendURL, "/"), token),
+			ExpiresAt: expiresAt.Format("02-01-2006 15:04"),
+		})
 		if err != nil {
-			return web.UserResponse{}, appError.NewAppError(
+			return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
 				http.StatusInternalServerError,
 				"Internal Server Error",
 				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to delete voting_access: %v", err),
+				fmt.Errorf("failed to render voting token message: %w", err),
 			)
 		}
+
+		outboxMessages = append(outboxMessages, helper.ToOutboxMessage(user, notifier.TemplateVotingToken, message))
 	}
 
-	// Check the request body, if not empty, swap the user to the request body
-	if request.NIM != "" {
-		user.NIM = request.NIM
+	err = service.VotingTokenRepository.SaveBulk(ctx, tx, votingTokens)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save voting tokens: %w", err),
+		)
 	}
-	if request.FullName != "" {
-		user.FullName = request.FullName
+
+	// Queue the messages, the outbox worker delivers them after commit
+	err = service.OutboxRepository.SaveBulk(ctx, tx, outboxMessages)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to queue voting token messages: %w", err),
+		)
 	}
-	if request.StudyProgram != "" {
-		user.StudyProgram = request.StudyProgram
+
+	// Audit every issued token
+	details, err := json.Marshal(map[string]interface{}{
+		"user_ids":      request.UserIds,
+		"study_program": request.StudyProgram,
+		"expires_at":    expiresAt,
+	})
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to marshal audit details: %w", err),
+		)
 	}
-	if request.Password != "" {
+
+	actorId := actor.ID
+	auditLogs := make([]domain.AuditLog, 0, len(users))
+	for _, user := range users {
+		targetId := user.Id
+		auditLogs = append(auditLogs, domain.AuditLog{
+			ActorId:    &actorId,
+			Action:     domain.AuditActionVotingTokenIssued,
+			TargetType: domain.AuditTargetUser,
+			TargetId:   &targetId,
+			Details:    details,
+		})
+	}
+
+	err = service.AuditLogRepository.SaveBulk(ctx, tx, auditLogs)
+	if err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to save audit logs: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return web.UserGenerateVotingTokenResponse{}, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	envConfig.Log.Infof("%s (id %d) issued voting tokens for %d users", actor.FullName, actor.ID, len(users))
+
+	return web.UserGenerateVotingTokenResponse{
+		Total:     len(userIds),
+		UserIds:   userIds,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// issueCredentials resets the password of every user and queues the plain
+// passwords in the outbox within the same transaction
+func (service *UserServiceImpl) issueCredentials(ctx context.Context, tx *sql.Tx, users []domain.User) error {
+	issuedUsers, err := service.resetPasswords(ctx, tx, users)
+	if err != nil {
+		return err
+	}
+
+	var outboxMessages []domain.OutboxMessage
+	for _, user := range issuedUsers {
+		message, err := notifier.Render(notifier.TemplateCredential, notifier.CredentialData{
+			FullName:    user.FullName,
+			NIM:         user.NIM,
+			Password:    user.Password,
+			FrontendURL: service.EnvConfig.FrontendURL,
+		})
+		if err != nil {
+			return appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to render credential message: %w", err),
+			)
+		}
+
+		outboxMessages = append(outboxMessages, helper.ToOutboxMessage(user, notifier.TemplateCredential, message))
+	}
+
+	// Queue the messages, the outbox worker delivers them after commit
+	err = service.OutboxRepository.SaveBulk(ctx, tx, outboxMessages)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to queue credential messages: %w", err),
+		)
+	}
+
+	return nil
+}
+
+// resetPasswords generates a new password for every user and saves the hashes.
+// Sessions and undelivered credentials of the old passwords are dropped, the
+// caller evicts the cached sessions after commit. The returned users carry
+// the plain password so it can be handed out.
+func (service *UserServiceImpl) resetPasswords(ctx context.Context, tx *sql.Tx, users []domain.User) ([]domain.User, error) {
+	var (
+		listOfUsers []domain.User
+		issuedUsers []domain.User
+	)
+
+	for _, user := range users {
+		// Generate password
+		password, err := helper.GeneratePassword(helper.DefaultPasswordLength)
+		if err != nil {
+			return nil, appError.NewAppError(
+				http.StatusInternalServerError,
+				"Internal Server Error",
+				"Failed to process your request due to an unexpected error. Please try again later.",
+				fmt.Errorf("failed to generate password: %w", err),
+			)
+		}
+
 		// Hash the password
-		hashedPassword, err := helper.HashPassword(request.Password)
+		hashedPassword, err := helper.HashPassword(password)
 		if err != nil {
-			return web.UserResponse{}, appError.NewAppError(
+			return nil, appError.NewAppError(
 				http.StatusInternalServerError,
 				"Internal Server Error",
 				"Failed to process your request due to an unexpected error. Please try again later.",
@@ -511,124 +2643,331 @@ func (service *UserServiceImpl) UpdateById(ctx context.Context, userId int, requ
 			)
 		}
 
+		issuedUser := user
+		issuedUser.Password = password
+		issuedUsers = append(issuedUsers, issuedUser)
+
 		user.Password = hashedPassword
+		listOfUsers = append(listOfUsers, user)
 	}
-	if request.Role != "" {
-		user.Role = request.Role
+
+	// Bulk update for all users
+	err := service.UserRepository.UpdateBulk(ctx, tx, listOfUsers)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update user in bulk: %w", err),
+		)
 	}
-	if request.PhoneNumber != "" {
-		user.PhoneNumber = request.PhoneNumber
+
+	userIds := make([]int, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.Id)
+	}
+
+	// Log out every device that used the old password
+	err = service.AuthRepository.DeleteByUserIds(ctx, tx, userIds)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to delete sessions: %w", err),
+		)
+	}
+
+	// Old passwords that are still queued must not be delivered
+	err = service.OutboxRepository.CancelByUserIds(ctx, tx, userIds, notifier.TemplateCredential)
+	if err != nil {
+		return nil, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to cancel queued credential messages: %w", err),
+		)
+	}
+
+	return issuedUsers, nil
+}
+
+func (service *UserServiceImpl) ChangeCurrentPassword(ctx context.Context, userId int, sessionId string, request web.UserChangePasswordRequest) error {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
+	}
+	defer helper.RollbackQuietly(tx)
+
+	// Get current user with password
+	user, err := service.UserRepository.GetByIdWithPassword(ctx, tx, userId)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusNotFound,
+			"User not found",
+			"The user you are trying to update does not exist.",
+			fmt.Errorf("failed to get user with id %v: %w", userId, err),
+		)
+	}
+
+	// Old password must match
+	if !helper.CheckPasswordHash(user.Password, request.OldPassword) {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid credentials",
+			"The old password is incorrect",
+			fmt.Errorf("%w: old password mismatch for user with id %v", appError.ErrInvalidCredentials, userId),
+		)
+	}
+
+	// Hash the new password
+	hashedPassword, err := helper.HashPassword(request.NewPassword)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to hash password: %w", err),
+		)
+	}
+
+	_, err = service.UserRepository.UpdatePartial(ctx, tx, userId, map[string]interface{}{
+		"password": hashedPassword,
+	})
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to update password of user with id %v: %w", userId, err),
+		)
+	}
+
+	// Log out every other device, keep the current session
+	err = service.AuthRepository.DeleteByUserId(ctx, tx, userId, sessionId)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to delete other sessions: %w", err),
+		)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
+		)
+	}
+
+	service.SessionCache.DeleteByUserId(userId)
+
+	return nil
+}
+
+func (service *UserServiceImpl) ForgotPassword(ctx context.Context, request web.UserForgotPasswordRequest) error {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
+	issued, err := service.issuePasswordResetOTP(ctx, func(tx *sql.Tx) (domain.User, error) {
+		return service.UserRepository.GetByNIM(ctx, tx, request.NIM)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Unknown NIMs, deactivated users and rate-limited requests get the same
+	// response as a sent OTP, after the same hashing work, so NIMs can't be
+	// enumerated
+	if !issued {
+		hashDummyOTP()
+	}
+
+	return nil
+}
+
+// ResendPasswordResetOTP issues a new OTP for the user, e.g. when the last one
+// could not be delivered. Unlike ForgotPassword it reports when nothing was
+// issued.
+func (service *UserServiceImpl) ResendPasswordResetOTP(ctx context.Context, userId int) error {
+	issued, err := service.issuePasswordResetOTP(ctx, func(tx *sql.Tx) (domain.User, error) {
+		return service.UserRepository.GetById(ctx, tx, userId)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !issued {
+		return appError.NewAppError(
+			http.StatusConflict,
+			"OTP not issued",
+			fmt.Sprintf("No OTP was issued for user %v: the account does not exist or is deactivated, or an OTP was sent less than %v ago", userId, PasswordResetOTPCooldown),
+			fmt.Errorf("password reset OTP for user %v not issued", userId),
+		)
+	}
+
+	return nil
+}
+
+// issuePasswordResetOTP queues a new OTP for the user returned by getUser. It
+// reports false without an error when the user is unknown or deactivated, or
+// the previous OTP is still within its cooldown.
+func (service *UserServiceImpl) issuePasswordResetOTP(ctx context.Context, getUser func(tx *sql.Tx) (domain.User, error)) (bool, error) {
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+		)
 	}
+	defer helper.RollbackQuietly(tx)
 
-	// Create a new voting_access if the new user is a student
-	// Only role with student can have voting access
-	if user.Role == "student" {
-		err := service.VotingAccessRepository.Create(ctx, tx, domain.VotingAccess{
-			UserId: user.Id,
-			Hashed: helper.HashNIM(user.NIM),
-		})
+	user, err := getUser(tx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) && !errors.Is(err, sql.ErrNoRows) {
+		return false, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get user: %w", err),
+		)
+	}
+	if err != nil || user.DeactivatedAt != nil {
+		envConfig.Log.Debug("password reset requested for an unknown or deactivated account")
+		return false, nil
+	}
 
-		if err != nil {
-			return web.UserResponse{}, appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to create voting_access: %w", err),
-			)
-		}
+	// Don't send a new OTP while the previous one is still fresh
+	latestOTP, err := service.PasswordResetRepository.GetLatestUnusedByUserIdForUpdate(ctx, tx, user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to get latest OTP: %w", err),
+		)
+	}
+	if err == nil && time.Since(latestOTP.CreatedAt) < PasswordResetOTPCooldown {
+		envConfig.Log.Infof("password reset for user with id %v ignored during OTP cooldown", user.Id)
+		return false, nil
 	}
 
-	// Call repository
-	userResponse, err := service.UserRepository.UpdateById(ctx, tx, userId, user)
+	// Only the newest OTP can be used
+	err = service.PasswordResetRepository.InvalidateByUserId(ctx, tx, user.Id)
 	if err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to update user with id %v: %v", userId, err),
+			fmt.Errorf("failed to invalidate previous OTP: %w", err),
 		)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return web.UserResponse{}, appError.NewAppError(
+	err = service.OutboxRepository.CancelByUserIds(ctx, tx, []int{user.Id}, notifier.TemplatePasswordResetOTP)
+	if err != nil {
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("transaction commit failed: %w", err),
+			fmt.Errorf("failed to cancel queued OTP messages: %w", err),
 		)
 	}
 
-	return helper.ToUserResponse(userResponse), nil
-}
-
-func (service *UserServiceImpl) DeleteById(ctx context.Context, userId int) error {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
+	otp, err := helper.GenerateOTP(helper.DefaultOTPLength)
 	if err != nil {
-		return appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("failed to generate OTP: %w", err),
 		)
 	}
-	defer helper.RollbackQuietly(tx)
 
-	// Check if user exists
-	_, err = service.UserRepository.GetById(ctx, tx, userId)
+	hashedOTP, err := helper.HashPassword(otp)
 	if err != nil {
-		// If user not found
-		if errors.Is(err, pgx.ErrNoRows) {
-			return appError.NewAppError(
-				http.StatusNotFound,
-				"User not found",
-				fmt.Sprintf("User with id %v does not exist", userId),
-				fmt.Errorf("user with id %v not found: %v", userId, err),
-			)
-		}
-
-		return appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get user with id %v: %v", userId, err),
+			fmt.Errorf("failed to hash OTP: %w", err),
 		)
 	}
 
-	// Check is user ever voted
-	isVoted, err := service.VotingAccessRepository.IsUserEverVoted(ctx, tx, userId)
+	_, err = service.PasswordResetRepository.Create(ctx, tx, domain.PasswordResetOTP{
+		UserId:    user.Id,
+		HashedOTP: hashedOTP,
+		ExpiresAt: time.Now().Add(PasswordResetOTPTTL).UTC(),
+	})
 	if err != nil {
-		return appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to check if user has voted: %v", err),
+			fmt.Errorf("failed to save OTP: %w", err),
 		)
 	}
-	if isVoted {
-		return appError.NewAppError(
-			http.StatusConflict,
-			"User has already voted",
-			"Can't delete user because user has already voted",
-			fmt.Errorf("user has already voted in: %v", err),
+
+	// Queue the OTP for the registered contact
+	message, err := notifier.Render(notifier.TemplatePasswordResetOTP, notifier.PasswordResetOTPData{
+		FullName:         user.FullName,
+		OTP:              otp,
+		ExpiresInMinutes: int(PasswordResetOTPTTL.Minutes()),
+	})
+	if err != nil {
+		return false, appError.NewAppError(
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("failed to render password reset message: %w", err),
 		)
 	}
 
-	// Call repository
-	err = service.UserRepository.DeleteById(ctx, tx, userId)
+	err = service.OutboxRepository.SaveBulk(ctx, tx, []domain.OutboxMessage{
+		helper.ToOutboxMessage(user, notifier.TemplatePasswordResetOTP, message),
+	})
 	if err != nil {
-		return appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to update user with id %v: %v", userId, err),
+			fmt.Errorf("failed to queue OTP message: %w", err),
 		)
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return appError.NewAppError(
+		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -636,14 +2975,32 @@ func (service *UserServiceImpl) DeleteById(ctx context.Context, userId int) erro
 		)
 	}
 
-	return nil
+	return true, nil
+}
+
+// hashDummyOTP does the work of issuing an OTP without keeping it, so a
+// password reset that issues nothing takes as long as one that does
+func hashDummyOTP() {
+	otp, _ := helper.GenerateOTP(helper.DefaultOTPLength)
+	_, _ = helper.HashPassword(otp)
 }
 
-func (service *UserServiceImpl) CreateBulk(ctx context.Context, request []web.UserCreateRequest) ([]web.UserResponse, error) {
+func (service *UserServiceImpl) ResetPassword(ctx context.Context, request web.UserResetPasswordRequest) error {
+	// Validate request
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid request payload",
+			appError.FormatValidationDetails(err.(validator.ValidationErrors)),
+			fmt.Errorf("%w: %v", appError.ErrValidation, err),
+		)
+	}
+
 	// Open Transaction
 	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return []web.UserResponse{}, appError.NewAppError(
+		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
@@ -652,274 +3009,279 @@ func (service *UserServiceImpl) CreateBulk(ctx context.Context, request []web.Us
 	}
 	defer helper.RollbackQuietly(tx)
 
-	var users []domain.User
-
-	// Validate each request
-	for i, currentUser := range request {
-		// Validate request
-		err := service.Validate.Struct(currentUser)
-		if err != nil {
-			return []web.UserResponse{}, appError.NewAppError(
-				http.StatusBadRequest,
-				"Invalid request payload",
-				appError.FormatValidationDetailsWithRow((err.(validator.ValidationErrors)), i+1),
-				fmt.Errorf("%w: %v in csv line %d", appError.ErrValidation, err, i+1),
-			)
-		}
-
-		// Check if NIM already exists
-		_, err = service.UserRepository.GetByNIM(ctx, tx, currentUser.NIM)
-		if err == nil {
-			return []web.UserResponse{}, appError.NewAppError(
-				http.StatusBadRequest,
-				"NIM already exists",
-				fmt.Sprintf("CSV's line %d: NIM '%s' is already exists", i+1, currentUser.NIM),
-				fmt.Errorf("user with NIM %s already exists", currentUser.NIM),
-			)
-		}
-
-		// Check if phone_number already exists
-		_, err = service.UserRepository.GetByPhoneNumber(ctx, tx, currentUser.PhoneNumber)
-		if err == nil {
-			return []web.UserResponse{}, appError.NewAppError(
-				http.StatusBadRequest,
-				"Phone number already exists",
-				fmt.Sprintf("CSV's line %d: Phone number '%s' is already exists", i+1, currentUser.PhoneNumber),
-				fmt.Errorf("user with phone number %s already exists", currentUser.PhoneNumber),
-			)
-		}
+	invalidOTPError := appError.NewAppError(
+		http.StatusBadRequest,
+		"Invalid OTP",
+		"The NIM or OTP is incorrect",
+		fmt.Errorf("%w: NIM %s", appError.ErrInvalidOTP, request.NIM),
+	)
 
-		users = append(users, domain.User{
-			NIM:          currentUser.NIM,
-			FullName:     currentUser.FullName,
-			StudyProgram: currentUser.StudyProgram,
-			PhoneNumber:  currentUser.PhoneNumber,
-		})
+	user, err := service.UserRepository.GetByNIM(ctx, tx, request.NIM)
+	if err != nil {
+		return invalidOTPError
 	}
 
-	// Insert user data to database in bulk
-	users, err = service.UserRepository.SaveBulk(ctx, tx, users)
+	otp, err := service.PasswordResetRepository.GetLatestUnusedByUserIdForUpdate(ctx, tx, user.Id)
 	if err != nil {
-		return []web.UserResponse{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to create user in bulk: %w", err),
-		)
+		return invalidOTPError
 	}
 
-	var votingAccesses []domain.VotingAccess
+	if time.Now().After(otp.ExpiresAt) || otp.Attempts >= PasswordResetOTPMaxAttempts {
+		return appError.NewAppError(
+			http.StatusBadRequest,
+			"OTP expired",
+			"The OTP has expired. Please request a new one.",
+			fmt.Errorf("%w: user with id %v", appError.ErrOTPExpired, user.Id),
+		)
+	}
 
-	// Initialize voting_access
-	for _, user := range users {
-		if user.Role == "student" {
-			votingAccesses = append(votingAccesses, domain.VotingAccess{
-				UserId: user.Id,
-				Hashed: helper.HashNIM(user.NIM),
-			})
+	if !helper.CheckPasswordHash(otp.HashedOTP, request.OTP) {
+		// Count the failed attempt even though the request fails
+		err = service.PasswordResetRepository.IncrementAttempts(ctx, tx, otp.Id)
+		if err == nil {
+			err = tx.Commit()
 		}
+		appError.LogError(err, "failed to record OTP attempt")
+
+		return invalidOTPError
 	}
 
-	// Insert voting_access data to database in bulk
-	err = service.VotingAccessRepository.CreateBulk(ctx, tx, votingAccesses)
+	// Hash the new password
+	hashedPassword, err := helper.HashPassword(request.NewPassword)
 	if err != nil {
-		return []web.UserResponse{}, appError.NewAppError(
+		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to create voting_access in bulk: %w", err),
+			fmt.Errorf("failed to hash password: %w", err),
 		)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return nil, appError.NewAppError(
+	_, err = service.UserRepository.UpdatePartial(ctx, tx, user.Id, map[string]interface{}{
+		"password": hashedPassword,
+	})
+	if err != nil {
+		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("transaction commit failed: %w", err),
+			fmt.Errorf("failed to update password of user with id %v: %w", user.Id, err),
 		)
 	}
 
-	return helper.ToUserResponses(users), nil
-}
-
-func (service *UserServiceImpl) GeneratePassword(ctx context.Context) error {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
+	err = service.PasswordResetRepository.MarkUsed(ctx, tx, otp.Id)
 	if err != nil {
 		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
+			fmt.Errorf("failed to mark OTP as used: %w", err),
 		)
 	}
-	defer helper.RollbackQuietly(tx)
 
-	// Get all users with null password
-	users, err := service.UserRepository.GetUsersWithNullPassword(ctx, tx)
+	// Log out every device that used the old password
+	err = service.AuthRepository.DeleteByUserId(ctx, tx, user.Id, "")
 	if err != nil {
 		return appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get users with null password: %w", err),
+			fmt.Errorf("failed to delete sessions: %w", err),
 		)
 	}
 
-	if len(users) == 0 {
-		envConfig.Log.Info("No users with null password, skipping password generation")
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
 		return appError.NewAppError(
-			http.StatusOK,
-			"No users with null password",
-			"Password generation skipped",
-			nil,
+			http.StatusInternalServerError,
+			"Internal Server Error",
+			"Failed to process your request due to an unexpected error. Please try again later.",
+			fmt.Errorf("transaction commit failed: %w", err),
 		)
 	}
 
-	var listOfUsers []domain.User
-	/*
-		Required data to perform send message via WhatsApp:
-		1. FullName
-		2. Password
-		3. PhoneNumber
-	*/
-	var willBeSendToWhatsApp []domain.User
+	service.SessionCache.DeleteByUserId(user.Id)
 
-	// Generate password for each user
-	for _, user := range users {
-		// Generate password
-		password, err := helper.GeneratePassword(helper.DefaultPasswordLength)
-		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to generate password: %w", err),
-			)
-		}
+	return nil
+}
 
-		// Data that will be send to WA
-		willBeSendToWhatsApp = append(willBeSendToWhatsApp, domain.User{
-			FullName:    user.FullName,
-			Password:    password,
-			PhoneNumber: user.PhoneNumber,
-		})
+// importRowKey locates a row by sheet (XLSX only) and row number
+type importRowKey struct {
+	sheet string
+	row   int
+}
 
-		// Hash the password
-		hashedPassword, err := helper.HashPassword(password)
-		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to hash password: %w", err),
-			)
-		}
+func (key importRowKey) String() string {
+	if key.sheet == "" {
+		return fmt.Sprintf("row %d", key.row)
+	}
+	return fmt.Sprintf("sheet %s row %d", key.sheet, key.row)
+}
 
-		user.Password = hashedPassword
+// importRows holds the roster rows read from a file together with the
+// problems found so far, keyed by row number. Only the keys seen so far are
+// kept for duplicate checks, rows are read in batches of UserImportBatchSize.
+type importRows struct {
+	reader       helper.UserRowReader
+	rows         []web.UserImportRow
+	total        int
+	errors       []web.ImportRowError
+	invalid      map[importRowKey]bool
+	nimRows      map[string]importRowKey
+	phoneRows    map[string]importRowKey
+	sheetOrder   map[string]int
+	nims         []string
+	phoneNumbers []string
+}
 
-		listOfUsers = append(listOfUsers, user)
+func newImportRows(reader helper.UserRowReader) *importRows {
+	return &importRows{
+		reader:     reader,
+		errors:     []web.ImportRowError{},
+		invalid:    make(map[importRowKey]bool),
+		nimRows:    make(map[string]importRowKey),
+		phoneRows:  make(map[string]importRowKey),
+		sheetOrder: make(map[string]int),
 	}
+}
 
-	// Bulk update for all users
-	err = service.UserRepository.UpdateBulk(ctx, tx, listOfUsers)
-	if err != nil {
-		return appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to update user in bulk: %w", err),
-		)
+func (rows *importRows) addError(rowError web.ImportRowError) {
+	rows.trackSheet(rowError.Sheet)
+	rows.errors = append(rows.errors, rowError)
+	rows.invalid[importRowKey{sheet: rowError.Sheet, row: rowError.Row}] = true
+}
+
+// trackSheet remembers the order sheets are read in, to sort errors by it
+func (rows *importRows) trackSheet(sheet string) {
+	if _, ok := rows.sheetOrder[sheet]; !ok {
+		rows.sheetOrder[sheet] = len(rows.sheetOrder)
 	}
+}
 
-	// Send the password to WhatsApp using Fonnte
-	URL := service.EnvConfig.FonnteSendURL
-	for _, user := range willBeSendToWhatsApp {
-		payload := map[string]string{
-			"target":  user.PhoneNumber,
-			"message": fmt.Sprintf("Halo *%s*, ini adalah password Voting Anda: *%s*\nGunakan password ini untuk login dan berikan suara terbaikmu!\nTerima kasih atas partisipasinya.\n\nVote di sini: %s", user.FullName, user.Password, service.EnvConfig.FrontendURL),
+// validRows returns the rows of batch without any reported problem
+func (rows *importRows) validRows(batch []web.UserImportRow) []web.UserImportRow {
+	var valid []web.UserImportRow
+	for _, row := range batch {
+		if !rows.invalid[importRowKey{sheet: row.Sheet, row: row.Row}] {
+			valid = append(valid, row)
 		}
+	}
+	return valid
+}
 
-		// Convert the user data to JSON
-		jsonPayload, err := json.Marshal(payload)
-		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to convert user data to JSON: %w", err),
-			)
+func (rows *importRows) sortedErrors() []web.ImportRowError {
+	sort.SliceStable(rows.errors, func(i, j int) bool {
+		a, b := rows.errors[i], rows.errors[j]
+		if a.Sheet != b.Sheet {
+			return rows.sheetOrder[a.Sheet] < rows.sheetOrder[b.Sheet]
 		}
+		return a.Row < b.Row
+	})
+	return rows.errors
+}
 
-		// Create the request
-		req, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(jsonPayload))
+// readImportRows reads every row of the file, for callers that need the
+// whole roster at once
+func (service *UserServiceImpl) readImportRows(reader helper.UserRowReader) (*importRows, error) {
+	rows := newImportRows(reader)
+
+	for {
+		batch, err := service.nextImportBatch(rows)
 		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to create request: %w", err),
-			)
+			return nil, err
 		}
+		if len(batch) == 0 {
+			return rows, nil
+		}
+
+		rows.rows = append(rows.rows, batch...)
+	}
+}
 
-		// Set the request header
-		req.Header.Set("Authorization", service.EnvConfig.FonnteAPIKey)
-		req.Header.Set("Content-Type", "application/json")
+// nextImportBatch reads up to UserImportBatchSize rows, validating each one
+// and detecting duplicate NIMs and phone numbers within the file. It returns
+// an empty batch after the last row.
+func (service *UserServiceImpl) nextImportBatch(rows *importRows) ([]web.UserImportRow, error) {
+	var batch []web.UserImportRow
 
-		// Send the request to Fonnte
-		client := http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to send request to Fonnte: %w", err),
-			)
+	for len(batch) < UserImportBatchSize {
+		row, err := rows.reader.Next()
+		if err == io.EOF {
+			break
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
+		var rowError *helper.RowError
+		if errors.As(err, &rowError) {
+			rows.total++
+			rows.addError(rowError.ImportRowError)
+			continue
+		}
 		if err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to read response body: %w", err),
+			return nil, appError.NewAppError(
+				http.StatusBadRequest,
+				"Invalid file",
+				"Uploaded file could not be read",
+				fmt.Errorf("failed to read import row: %w", err),
 			)
 		}
 
-		var fonnteResp map[string]interface{}
-		if err := json.Unmarshal(body, &fonnteResp); err != nil {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				"Failed to process your request due to an unexpected error. Please try again later.",
-				fmt.Errorf("failed to unmarshal response body: %w", err),
-			)
+		rows.total++
+		rows.trackSheet(row.Sheet)
+
+		if err := service.Validate.Struct(row); err != nil {
+			for _, fe := range err.(validator.ValidationErrors) {
+				rows.addError(web.ImportRowError{
+					Sheet:   row.Sheet,
+					Row:     row.Row,
+					Column:  helper.ToImportColumn(fe.Field()),
+					Value:   fmt.Sprintf("%v", fe.Value()),
+					Message: appError.FormatFieldError(fe),
+				})
+			}
 		}
 
-		if !fonnteResp["status"].(bool) {
-			return appError.NewAppError(
-				http.StatusInternalServerError,
-				"Internal Server Error",
-				fmt.Sprintf("Failed to send message to WhatsApp: %v", fonnteResp["reason"].(string)),
-				fmt.Errorf("failed to send message to WhatsApp: %v", fonnteResp["reason"].(string)),
-			)
+		// Duplicates are checked on the canonical +62 form, so 0812... and 62812... match
+		row.PhoneNumber, _ = phone.Normalize(row.PhoneNumber)
+
+		key := importRowKey{sheet: row.Sheet, row: row.Row}
+
+		if firstRow, ok := rows.nimRows[row.NIM]; ok && row.NIM != "" {
+			rows.addError(web.ImportRowError{
+				Sheet:   row.Sheet,
+				Row:     row.Row,
+				Column:  "nim",
+				Value:   row.NIM,
+				Message: fmt.Sprintf("Duplicate NIM, already used in %s", firstRow),
+			})
+		} else if row.NIM != "" {
+			rows.nimRows[row.NIM] = key
+			rows.nims = append(rows.nims, row.NIM)
+		}
+
+		if firstRow, ok := rows.phoneRows[row.PhoneNumber]; ok && row.PhoneNumber != "" {
+			rows.addError(web.ImportRowError{
+				Sheet:   row.Sheet,
+				Row:     row.Row,
+				Column:  "phone_number",
+				Value:   row.PhoneNumber,
+				Message: fmt.Sprintf("Duplicate phone number, already used in %s", firstRow),
+			})
+		} else if row.PhoneNumber != "" {
+			rows.phoneRows[row.PhoneNumber] = key
+			rows.phoneNumbers = append(rows.phoneNumbers, row.PhoneNumber)
 		}
+
+		batch = append(batch, row)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("transaction commit failed: %w", err),
+	if len(batch) == 0 && rows.total == 0 {
+		return nil, appError.NewAppError(
+			http.StatusBadRequest,
+			"Invalid file",
+			"Uploaded file does not contain any rows",
+			fmt.Errorf("%w: empty import file", appError.ErrValidation),
 		)
 	}
 
-	return nil
+	return batch, nil
 }
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/domain"
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/model/web"
+)
+
+func TestCheckRoleAssignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorRole string
+		role      string
+		wantErr   bool
+	}{
+		{"admin leaves role empty", domain.RoleAdmin, "", false},
+		{"admin assigns student", domain.RoleAdmin, domain.RoleStudent, false},
+		{"admin assigns admin", domain.RoleAdmin, domain.RoleAdmin, true},
+		{"admin assigns super admin", domain.RoleAdmin, domain.RoleSuperAdmin, true},
+		{"admin assigns candidate manager", domain.RoleAdmin, domain.RoleCandidateManager, true},
+		{"admin assigns committee observer", domain.RoleAdmin, domain.RoleCommitteeObserver, true},
+		{"super admin assigns super admin", domain.RoleSuperAdmin, domain.RoleSuperAdmin, false},
+		{"super admin assigns candidate manager", domain.RoleSuperAdmin, domain.RoleCandidateManager, false},
+		{"super admin assigns student", domain.RoleSuperAdmin, domain.RoleStudent, false},
+		{"unknown actor assigns student", "", domain.RoleStudent, false},
+		{"unknown actor assigns admin", "", domain.RoleAdmin, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRoleAssignment(web.UserResponse{Role: tt.actorRole}, tt.role)
+			assertForbidden(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCheckStaffTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetRole string
+		wantErr    bool
+	}{
+		{"admin changes student", domain.RoleAdmin, domain.RoleStudent, false},
+		{"admin changes admin", domain.RoleAdmin, domain.RoleAdmin, true},
+		{"admin changes super admin", domain.RoleAdmin, domain.RoleSuperAdmin, true},
+		{"admin changes candidate manager", domain.RoleAdmin, domain.RoleCandidateManager, true},
+		{"admin changes committee observer", domain.RoleAdmin, domain.RoleCommitteeObserver, true},
+		{"super admin changes student", domain.RoleSuperAdmin, domain.RoleStudent, false},
+		{"super admin changes admin", domain.RoleSuperAdmin, domain.RoleAdmin, false},
+		{"super admin changes super admin", domain.RoleSuperAdmin, domain.RoleSuperAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStaffTarget(web.UserResponse{Role: tt.actorRole}, domain.User{Role: tt.targetRole}, "update")
+			assertForbidden(t, err, tt.wantErr)
+		})
+	}
+}
+
+// assertForbidden checks that err is nil, or a 403 wrapping ErrForbiddenAccess when wantErr is set
+func assertForbidden(t *testing.T, err error, wantErr bool) {
+	t.Helper()
+
+	if !wantErr {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	var customError *appError.AppError
+	if !errors.As(err, &customError) {
+		t.Fatalf("error = %v, want *AppError", err)
+	}
+	if customError.StatusCode != http.StatusForbidden {
+		t.Errorf("status code = %d, want %d", customError.StatusCode, http.StatusForbidden)
+	}
+	if !errors.Is(customError.Err, appError.ErrForbiddenAccess) {
+		t.Errorf("underlying error = %v, want ErrForbiddenAccess", customError.Err)
+	}
+}
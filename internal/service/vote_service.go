@@ -11,7 +11,7 @@ type VoteService interface {
 	SetCandidateService(candidateService CandidateService)
 
 	GetByCandidateId(ctx context.Context, candidateId int) ([]web.VoteResponse, error)
-	SaveVoteRecord(ctx context.Context, request web.VoteCreateRequest, userId int) (web.VoteCreateResponse, error)
+	SaveVoteRecord(ctx context.Context, request web.VoteCreateRequest, user web.UserResponse) (web.VoteCreateResponse, error)
 	GetTotalVotesByCandidateId(ctx context.Context, candidateId int) (web.TotalVoteResponse, error)
-	CheckIfUserHasVoted(ctx context.Context, sessionId string) (bool, error)
+	CheckIfUserHasVoted(ctx context.Context, user web.UserResponse) (bool, error)
 }
@@ -71,7 +71,7 @@ func (service *VoteServiceImpl) GetByCandidateId(ctx context.Context, candidateI
 	return helper.ToVotesResponse(votes), nil
 }
 
-func (service *VoteServiceImpl) SaveVoteRecord(ctx context.Context, request web.VoteCreateRequest, userId int) (web.VoteCreateResponse, error) {
+func (service *VoteServiceImpl) SaveVoteRecord(ctx context.Context, request web.VoteCreateRequest, user web.UserResponse) (web.VoteCreateResponse, error) {
 	// Validate request
 	err := service.Validate.Struct(request)
 	if err != nil {
@@ -114,7 +114,7 @@ func (service *VoteServiceImpl) SaveVoteRecord(ctx context.Context, request web.
 	}
 
 	// Users can only vote once in a period. Users can vote again if the period has changed.
-	votingAccess, err := service.VotingAccessRepository.GetByUserId(ctx, tx, userId)
+	votingAccess, err := service.VotingAccessRepository.GetByUserId(ctx, tx, user.ID)
 	if err != nil {
 		return web.VoteCreateResponse{}, appError.NewAppError(
 			http.StatusForbidden,
@@ -156,17 +156,6 @@ func (service *VoteServiceImpl) SaveVoteRecord(ctx context.Context, request web.
 		)
 	}
 
-	// Get user by user_id
-	user, err := service.UserService.GetById(ctx, userId)
-	if err != nil {
-		return web.VoteCreateResponse{}, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get user by user_id: %v", err),
-		)
-	}
-
 	// Write the log to the file
 	config.FileLog.Infof("%s with NIM %s from %s has voted", user.FullName, user.NIM, user.StudyProgram)
 
@@ -224,53 +213,31 @@ func (service *VoteServiceImpl) GetTotalVotesByCandidateId(ctx context.Context,
 	}, nil
 }
 
-func (service *VoteServiceImpl) CheckIfUserHasVoted(ctx context.Context, sessionId string) (bool, error) {
-	// Open Transaction
-	tx, err := service.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return false, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
-		)
-	}
-	defer helper.RollbackQuietly(tx)
-
-	// Check if sessionId is not from admin
-	// Get session by sessionId
-	session, err := service.AuthRepository.GetSessionById(ctx, tx, sessionId)
-	if err != nil {
+func (service *VoteServiceImpl) CheckIfUserHasVoted(ctx context.Context, user web.UserResponse) (bool, error) {
+	// Only roles that can cast a vote have a voting status
+	if !helper.HasPermission(user.Role, domain.PermissionVoteCast) {
 		return false, appError.NewAppError(
-			http.StatusInternalServerError,
-			"Internal Server Error",
-			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get session by sessionId: %v", err),
+			http.StatusBadRequest,
+			"User cannot vote",
+			fmt.Sprintf("Role '%s' does not have permission to vote", user.Role),
+			fmt.Errorf("%s has role %s, cannot vote", user.FullName, user.Role),
 		)
 	}
 
-	// Get user by userId
-	user, err := service.UserService.GetById(ctx, session.UserId)
+	// Open Transaction
+	tx, err := service.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return false, appError.NewAppError(
 			http.StatusInternalServerError,
 			"Internal Server Error",
 			"Failed to process your request due to an unexpected error. Please try again later.",
-			fmt.Errorf("failed to get user by userId: %v", err),
-		)
-	}
-
-	if user.Role == "admin" {
-		return false, appError.NewAppError(
-			http.StatusBadRequest,
-			"Admin cannot vote",
-			"Admin does not have permission to vote",
-			fmt.Errorf("%s is an admin, cannot vote", user.FullName),
+			fmt.Errorf("%w: %v", appError.ErrTransaction, err),
 		)
 	}
+	defer helper.RollbackQuietly(tx)
 
 	// Check is user already voted in this period
-	isVoted, err := service.VoteRepository.IsUserEverVoted(ctx, tx, session.UserId)
+	isVoted, err := service.VoteRepository.IsUserEverVoted(ctx, tx, user.ID)
 	if err != nil {
 		return false, appError.NewAppError(
 			http.StatusInternalServerError,
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalRoutePrefix is where the API serves objects of the local store
+const LocalRoutePrefix = "/api/storage"
+
+// LocalMaxObjectSize limits uploads to a presigned local URL
+const LocalMaxObjectSize = 10 << 20
+
+// NewLocalObjectStore keeps objects under dir and signs URLs that point to
+// the API itself, so development and tests need no bucket. Without a signing
+// key a random one is used and URLs stop working after a restart.
+func NewLocalObjectStore(dir, baseURL, signingKey string) (ObjectStore, error) {
+	if dir == "" {
+		dir = "storage"
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+
+	key := []byte(signingKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate storage signing key: %w", err)
+		}
+	}
+
+	return &LocalObjectStore{
+		Dir:        dir,
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		SigningKey: key,
+	}, nil
+}
+
+type LocalObjectStore struct {
+	Dir        string
+	BaseURL    string
+	SigningKey []byte
+}
+
+func (store *LocalObjectStore) PresignPut(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return store.presign(http.MethodPut, key, expires), nil
+}
+
+func (store *LocalObjectStore) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return store.presign(http.MethodGet, key, expires), nil
+}
+
+func (store *LocalObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(store.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (store *LocalObjectStore) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	filePath := store.path(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filePath)
+}
+
+func (store *LocalObjectStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(store.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Head sniffs the content type from the first bytes, as the local store
+// does not keep the type sent on upload
+func (store *LocalObjectStore) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	file, err := os.Open(store.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return ObjectInfo{}, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  http.DetectContentType(head[:n]),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+// List returns the objects whose key starts with prefix. The content type is
+// left empty like in S3 listings.
+func (store *LocalObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	// Only walk the directory part of the prefix
+	root := store.path(path.Dir("/" + prefix))
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(store.Dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relPath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// ServeHTTP handles GET and PUT requests on URLs made by PresignGet and
+// PresignPut under LocalRoutePrefix
+func (store *LocalObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, LocalRoutePrefix), "/")
+	if key == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires || !hmac.Equal([]byte(query.Get("signature")), []byte(store.sign(r.Method, key, expires))) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		file, err := os.Open(store.path(key))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		stat, err := file.Stat()
+		if err != nil || stat.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.ServeContent(w, r, path.Base(key), stat.ModTime(), file)
+	case http.MethodPut:
+		body := http.MaxBytesReader(w, r.Body, LocalMaxObjectSize)
+		if err := store.Put(r.Context(), key, body, r.Header.Get("Content-Type")); err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				http.Error(w, "object too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			http.Error(w, "failed to store object", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (store *LocalObjectStore) presign(method, key string, expires time.Duration) string {
+	expiresAt := time.Now().Add(expires).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", store.sign(method, key, expiresAt))
+
+	return fmt.Sprintf("%s%s/%s?%s", store.BaseURL, LocalRoutePrefix, (&url.URL{Path: key}).EscapedPath(), query.Encode())
+}
+
+func (store *LocalObjectStore) sign(method, key string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, store.SigningKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d", method, key, expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key inside Dir, cleaning it so keys can't escape the directory
+func (store *LocalObjectStore) path(key string) string {
+	return filepath.Join(store.Dir, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	appError "github.com/mhaatha/HIMA-TI-e-Election/internal/errors"
+)
+
+// NewS3ObjectStore builds the S3 client once. Any S3 compatible endpoint
+// such as Cloudflare R2 can be used.
+func NewS3ObjectStore(ctx context.Context, accessKeyId, secretAccessKey, bucket, endpoint string) (ObjectStore, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyId, secretAccessKey, "")),
+		config.WithRegion("auto"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", appError.ErrLoadDefaultConfig, err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	})
+
+	return &S3ObjectStore{
+		Bucket:        bucket,
+		Client:        client,
+		PresignClient: s3.NewPresignClient(client),
+	}, nil
+}
+
+type S3ObjectStore struct {
+	Bucket        string
+	Client        *s3.Client
+	PresignClient *s3.PresignClient
+}
+
+func (store *S3ObjectStore) PresignPut(ctx context.Context, key string, expires time.Duration) (string, error) {
+	result, err := store.PresignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+
+	return result.URL, nil
+}
+
+func (store *S3ObjectStore) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	result, err := store.PresignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+
+	return result.URL, nil
+}
+
+func (store *S3ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	result, err := store.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
+func (store *S3ObjectStore) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	_, err := store.Client.PutObject(ctx, input)
+	return err
+}
+
+func (store *S3ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := store.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (store *S3ObjectStore) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	result, err := store.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(result.ContentLength),
+		ContentType:  aws.ToString(result.ContentType),
+		LastModified: aws.ToTime(result.LastModified),
+	}, nil
+}
+
+// List returns the objects under prefix. S3 listings don't include the
+// content type, so it is left empty.
+func (store *S3ObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	paginator := s3.NewListObjectsV2Paginator(store.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(store.Bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+// isS3NotFound matches NotFound from HEAD and NoSuchKey from GET. It also
+// checks the status code because HEAD responses have no body, so some S3
+// compatible providers don't return a typed NotFound error
+func isS3NotFound(err error) bool {
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return true
+	}
+
+	var responseError *awshttp.ResponseError
+	return errors.As(err, &responseError) && responseError.HTTPStatusCode() == http.StatusNotFound
+}
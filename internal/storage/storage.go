@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mhaatha/HIMA-TI-e-Election/internal/config"
+)
+
+// Supported storage providers
+const (
+	ProviderS3    = "s3"
+	ProviderLocal = "local"
+)
+
+// ErrObjectNotFound is returned by Get and Head when the key does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// ObjectStore stores files such as candidate photos and vote logs. Presigned
+// URLs let clients upload and download without going through the API.
+type ObjectStore interface {
+	PresignPut(ctx context.Context, key string, expires time.Duration) (string, error)
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	Head(ctx context.Context, key string) (ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// NewObjectStore builds the store selected by STORAGE_PROVIDER, defaulting to S3
+func NewObjectStore(ctx context.Context, cfg *config.Config) (ObjectStore, error) {
+	switch cfg.StorageProvider {
+	case "", ProviderS3:
+		return NewS3ObjectStore(ctx, cfg.S3AccessKeyId, cfg.S3SecretAccessKey, cfg.S3Bucket, cfg.S3URL)
+	case ProviderLocal:
+		return NewLocalObjectStore(cfg.StorageLocalDir, cfg.APIURL, cfg.StorageSigningKey)
+	default:
+		return nil, fmt.Errorf("unknown storage provider '%s'", cfg.StorageProvider)
+	}
+}
+
+// PublicURL returns the URL of key under a public base URL, e.g. a CDN in
+// front of a public-read bucket
+func PublicURL(baseURL, key string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/" + (&url.URL{Path: key}).EscapedPath()
+}